@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrCacheMiss is returned when a key is not in the cache
+var ErrCacheMiss = errors.New("cache: miss")
+
+// Cache is a string key/value cache with expiry
+type Cache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	// SetNX sets key only if it does not exist and reports whether it was set
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, keys ...string) error
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// RedisCache stores entries in Redis under a key prefix
+type RedisCache struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisCache creates a new Redis-backed cache
+func NewRedisCache(client redis.UniversalClient, prefix string) *RedisCache {
+	if prefix == "" {
+		prefix = "cache"
+	}
+	return &RedisCache{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (c *RedisCache) key(key string) string {
+	return c.prefix + ":" + key
+}
+
+// Ping checks that Redis is reachable
+func (c *RedisCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// Get returns the value of key or ErrCacheMiss
+func (c *RedisCache) Get(ctx context.Context, key string) (string, error) {
+	value, err := c.client.Get(ctx, c.key(key)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrCacheMiss
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s from cache: %w", key, err)
+	}
+	return value, nil
+}
+
+// Set stores value under key for ttl
+func (c *RedisCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	if err := c.client.Set(ctx, c.key(key), value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set %s in cache: %w", key, err)
+	}
+	return nil
+}
+
+// SetNX stores value under key only if it does not exist
+func (c *RedisCache) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	ok, err := c.client.SetNX(ctx, c.key(key), value, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to setnx %s in cache: %w", key, err)
+	}
+	return ok, nil
+}
+
+// Delete removes keys
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, k := range keys {
+		prefixed[i] = c.key(k)
+	}
+	if err := c.client.Del(ctx, prefixed...).Err(); err != nil {
+		return fmt.Errorf("failed to delete from cache: %w", err)
+	}
+	return nil
+}
+
+// Exists reports whether key is cached
+func (c *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := c.client.Exists(ctx, c.key(key)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s in cache: %w", key, err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec converts values to and from their cached representation. Other
+// formats (e.g. msgpack) can be plugged in by implementing it.
+type Codec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// JSONCodec encodes values as JSON
+type JSONCodec[T any] struct{}
+
+// Marshal encodes value as JSON
+func (JSONCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal decodes JSON into a new T
+func (JSONCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// ProtoCodec encodes protobuf messages in the binary wire format
+type ProtoCodec[T proto.Message] struct {
+	// New returns an empty message to decode into
+	New func() T
+}
+
+// Marshal encodes value in the protobuf wire format
+func (c ProtoCodec[T]) Marshal(value T) ([]byte, error) {
+	return proto.Marshal(value)
+}
+
+// Unmarshal decodes data into a message from New
+func (c ProtoCodec[T]) Unmarshal(data []byte) (T, error) {
+	if c.New == nil {
+		var zero T
+		return zero, fmt.Errorf("proto codec requires New")
+	}
+	value := c.New()
+	err := proto.Unmarshal(data, value)
+	return value, err
+}
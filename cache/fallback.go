@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrDeleteQueueFull is returned by Delete while degraded once the queue of
+// deletes to replay on recovery is full; those keys stay stale in Redis
+var ErrDeleteQueueFull = errors.New("cache degraded: pending delete queue is full")
+
+// Pinger checks the health of the primary cache
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// FallbackConfig configures degraded mode
+type FallbackConfig struct {
+	// MaxEntries bounds the in-process fallback cache
+	MaxEntries int
+	// MaxTTL caps entry lifetimes while degraded, since the local copy is
+	// not shared with other instances
+	MaxTTL time.Duration
+	// HealthInterval is how often the primary is pinged
+	HealthInterval time.Duration
+	// MaxPendingDeletes bounds the keys deleted while degraded that are
+	// replayed against Redis before it is used again
+	MaxPendingDeletes int
+	// OnStateChange is called when the cache enters or leaves degraded mode
+	OnStateChange func(degraded bool)
+}
+
+// primary is the cache and health check FallbackCache delegates to
+type primary interface {
+	Cache
+	Pinger
+}
+
+// FallbackCache serves from Redis and switches to a bounded in-process cache
+// while Redis is unreachable, instead of failing every request
+type FallbackCache struct {
+	primary  primary
+	fallback *MemoryCache
+	config   FallbackConfig
+	degraded atomic.Bool
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+}
+
+// NewFallbackCache wraps primary (usually a *RedisCache) with degraded-mode fallback
+func NewFallbackCache(primary primary, config FallbackConfig) *FallbackCache {
+	if config.MaxTTL <= 0 {
+		config.MaxTTL = 30 * time.Second
+	}
+	if config.HealthInterval <= 0 {
+		config.HealthInterval = 2 * time.Second
+	}
+	if config.MaxPendingDeletes <= 0 {
+		config.MaxPendingDeletes = 10000
+	}
+	return &FallbackCache{
+		primary:  primary,
+		fallback: NewMemoryCache(config.MaxEntries),
+		config:   config,
+		pending:  make(map[string]struct{}),
+	}
+}
+
+// Healthy reports whether the primary cache is in use
+func (c *FallbackCache) Healthy() bool {
+	return !c.degraded.Load()
+}
+
+// Degraded reports whether the in-process fallback is in use
+func (c *FallbackCache) Degraded() bool {
+	return c.degraded.Load()
+}
+
+func (c *FallbackCache) setDegraded(degraded bool) {
+	if c.degraded.Swap(degraded) == degraded {
+		return
+	}
+	if degraded {
+		log.Printf("⚠️ Cache degraded: Redis unavailable, serving from in-process fallback")
+	} else {
+		// Local entries may be stale relative to Redis
+		c.fallback.Clear()
+		log.Printf("✅ Cache recovered: Redis available again")
+	}
+	if c.config.OnStateChange != nil {
+		c.config.OnStateChange(degraded)
+	}
+}
+
+// Start pings the primary periodically until ctx is cancelled
+func (c *FallbackCache) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.config.HealthInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.checkHealth(ctx)
+			}
+		}
+	}()
+}
+
+// checkHealth pings the primary and switches modes accordingly
+func (c *FallbackCache) checkHealth(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, c.config.HealthInterval)
+	err := c.primary.Ping(pingCtx)
+	cancel()
+
+	recovering := err == nil && c.Degraded()
+	if recovering {
+		// Stay degraded until Redis has caught up on the deletes it missed
+		if err = c.replayDeletes(ctx); err != nil {
+			log.Printf("⚠️ Cache recovery postponed: %v", err)
+		}
+	}
+	c.setDegraded(err != nil)
+	if recovering && err == nil {
+		// Deletes queued while the first replay ran
+		if err := c.replayDeletes(ctx); err != nil {
+			c.failed(ctx, err)
+		}
+	}
+}
+
+// failed switches to degraded mode on infrastructure errors; misses and
+// caller cancellations do not count
+func (c *FallbackCache) failed(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, ErrCacheMiss) || ctx.Err() != nil {
+		return false
+	}
+	c.setDegraded(true)
+	return true
+}
+
+func (c *FallbackCache) ttl(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.config.MaxTTL {
+		return c.config.MaxTTL
+	}
+	return ttl
+}
+
+// Get returns the value of key or ErrCacheMiss
+func (c *FallbackCache) Get(ctx context.Context, key string) (string, error) {
+	if c.Healthy() {
+		value, err := c.primary.Get(ctx, key)
+		if !c.failed(ctx, err) {
+			return value, err
+		}
+	}
+	return c.fallback.Get(ctx, key)
+}
+
+// Set stores value under key for ttl
+func (c *FallbackCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	if c.Healthy() {
+		err := c.primary.Set(ctx, key, value, ttl)
+		if !c.failed(ctx, err) {
+			return err
+		}
+	}
+	return c.fallback.Set(ctx, key, value, c.ttl(ttl))
+}
+
+// SetNX stores value under key only if it does not exist. While degraded the
+// check is local to this instance.
+func (c *FallbackCache) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	if c.Healthy() {
+		ok, err := c.primary.SetNX(ctx, key, value, ttl)
+		if !c.failed(ctx, err) {
+			return ok, err
+		}
+	}
+	return c.fallback.SetNX(ctx, key, value, c.ttl(ttl))
+}
+
+// Delete removes keys from both tiers. While degraded the keys are queued
+// and deleted from Redis before it is used again, so other instances never
+// read a value this one invalidated.
+func (c *FallbackCache) Delete(ctx context.Context, keys ...string) error {
+	c.fallback.Delete(ctx, keys...)
+	if c.Healthy() {
+		err := c.primary.Delete(ctx, keys...)
+		if !c.failed(ctx, err) {
+			return err
+		}
+	}
+	return c.queueDeletes(keys)
+}
+
+// queueDeletes records keys to delete from Redis on recovery
+func (c *FallbackCache) queueDeletes(keys []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if _, ok := c.pending[key]; ok {
+			continue
+		}
+		if len(c.pending) >= c.config.MaxPendingDeletes {
+			return ErrDeleteQueueFull
+		}
+		c.pending[key] = struct{}{}
+	}
+	return nil
+}
+
+// replayDeletes deletes the queued keys from Redis; keys stay queued on failure
+func (c *FallbackCache) replayDeletes(ctx context.Context) error {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.pending))
+	for key := range c.pending {
+		keys = append(keys, key)
+	}
+	c.mu.Unlock()
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := c.primary.Delete(ctx, keys...); err != nil {
+		return fmt.Errorf("failed to replay %d deletes: %w", len(keys), err)
+	}
+
+	c.mu.Lock()
+	for _, key := range keys {
+		delete(c.pending, key)
+	}
+	c.mu.Unlock()
+	log.Printf("✅ Replayed %d cache deletes made while degraded", len(keys))
+	return nil
+}
+
+// Exists reports whether key is cached
+func (c *FallbackCache) Exists(ctx context.Context, key string) (bool, error) {
+	if c.Healthy() {
+		ok, err := c.primary.Exists(ctx, key)
+		if !c.failed(ctx, err) {
+			return ok, err
+		}
+	}
+	return c.fallback.Exists(ctx, key)
+}
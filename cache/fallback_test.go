@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestDeleteWhileDegradedIsReplayedOnRecovery(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer client.Close()
+
+	c := NewFallbackCache(NewRedisCache(client, ""), FallbackConfig{})
+	if err := c.Set(ctx, "fare:trip-1", "120", 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+	if err := c.Delete(ctx, "fare:trip-1"); err != nil {
+		t.Fatalf("Delete while Redis is down: %v", err)
+	}
+	if !c.Degraded() {
+		t.Fatal("cache did not switch to degraded mode")
+	}
+
+	mr.SetError("")
+	c.checkHealth(ctx)
+	if !c.Healthy() {
+		t.Fatal("cache did not recover")
+	}
+	if _, err := c.Get(ctx, "fare:trip-1"); err != ErrCacheMiss {
+		t.Errorf("Get after recovery = %v, want ErrCacheMiss", err)
+	}
+}
+
+func TestDeleteQueueIsBounded(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer client.Close()
+
+	c := NewFallbackCache(NewRedisCache(client, ""), FallbackConfig{MaxPendingDeletes: 1})
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+	if err := c.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := c.Delete(ctx, "b"); err != ErrDeleteQueueFull {
+		t.Errorf("Delete past the queue bound = %v, want ErrDeleteQueueFull", err)
+	}
+}
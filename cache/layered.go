@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultInvalidationChannel is the pub/sub channel for local cache invalidations
+const DefaultInvalidationChannel = "cache:invalidate"
+
+// LayeredConfig configures a LayeredCache
+type LayeredConfig struct {
+	// MaxEntries bounds the in-process tier
+	MaxEntries int
+	// LocalTTL caps how long entries live in-process, bounding staleness if
+	// an invalidation message is lost
+	LocalTTL time.Duration
+	// Channel carries invalidations between instances
+	Channel string
+}
+
+// invalidation is broadcast when keys change on one instance
+type invalidation struct {
+	Source string   `json:"source"`
+	Keys   []string `json:"keys"`
+	// All clears the whole local tier, e.g. after a reconnect
+	All bool `json:"all,omitempty"`
+}
+
+// LayeredCache serves hot keys (service configs, fare tables) from an
+// in-process LRU in front of a shared remote cache. Writes and deletes are
+// broadcast over Redis pub/sub so other instances drop their local copies.
+type LayeredCache struct {
+	local  *MemoryCache
+	remote Cache
+	client redis.UniversalClient
+	config LayeredConfig
+	id     string
+}
+
+// NewLayeredCache layers an in-process cache over remote (usually a
+// *RedisCache); client is used for invalidation broadcasts
+func NewLayeredCache(remote Cache, client redis.UniversalClient, config LayeredConfig) *LayeredCache {
+	if config.LocalTTL <= 0 {
+		config.LocalTTL = 30 * time.Second
+	}
+	if config.Channel == "" {
+		config.Channel = DefaultInvalidationChannel
+	}
+	return &LayeredCache{
+		local:  NewMemoryCache(config.MaxEntries),
+		remote: remote,
+		client: client,
+		config: config,
+		id:     uuid.NewString(),
+	}
+}
+
+// Start listens for invalidations from other instances until ctx is cancelled
+func (c *LayeredCache) Start(ctx context.Context) {
+	pubsub := c.client.Subscribe(ctx, c.config.Channel)
+	go func() {
+		defer pubsub.Close()
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				c.handleInvalidation(msg.Payload)
+			}
+		}
+	}()
+}
+
+func (c *LayeredCache) handleInvalidation(payload string) {
+	var inv invalidation
+	if err := json.Unmarshal([]byte(payload), &inv); err != nil {
+		log.Printf("⚠️ Ignoring malformed cache invalidation: %v", err)
+		return
+	}
+	if inv.Source == c.id {
+		return
+	}
+	if inv.All {
+		c.local.Clear()
+		return
+	}
+	c.local.Delete(context.Background(), inv.Keys...)
+}
+
+// publish tells other instances to drop keys; failures only delay
+// convergence until LocalTTL expires, so they are logged
+func (c *LayeredCache) publish(ctx context.Context, inv invalidation) {
+	inv.Source = c.id
+	payload, err := json.Marshal(inv)
+	if err != nil {
+		return
+	}
+	if err := c.client.Publish(ctx, c.config.Channel, payload).Err(); err != nil {
+		log.Printf("⚠️ Failed to broadcast cache invalidation: %v", err)
+	}
+}
+
+func (c *LayeredCache) localTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.config.LocalTTL {
+		return c.config.LocalTTL
+	}
+	return ttl
+}
+
+// Get returns key from the local tier, then the remote one, or ErrCacheMiss
+func (c *LayeredCache) Get(ctx context.Context, key string) (string, error) {
+	if value, err := c.local.Get(ctx, key); err == nil {
+		return value, nil
+	}
+	value, err := c.remote.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	c.local.Set(ctx, key, value, c.config.LocalTTL)
+	return value, nil
+}
+
+// Set stores value in both tiers and invalidates other instances
+func (c *LayeredCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	if err := c.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	c.local.Set(ctx, key, value, c.localTTL(ttl))
+	c.publish(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+// SetNX stores value only if key does not exist remotely
+func (c *LayeredCache) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	ok, err := c.remote.SetNX(ctx, key, value, ttl)
+	if err != nil || !ok {
+		return ok, err
+	}
+	c.local.Set(ctx, key, value, c.localTTL(ttl))
+	c.publish(ctx, invalidation{Keys: []string{key}})
+	return true, nil
+}
+
+// Delete removes keys from both tiers on every instance
+func (c *LayeredCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	c.local.Delete(ctx, keys...)
+	err := c.remote.Delete(ctx, keys...)
+	c.publish(ctx, invalidation{Keys: keys})
+	return err
+}
+
+// Exists reports whether key is cached in either tier
+func (c *LayeredCache) Exists(ctx context.Context, key string) (bool, error) {
+	if ok, _ := c.local.Exists(ctx, key); ok {
+		return true, nil
+	}
+	return c.remote.Exists(ctx, key)
+}
+
+// Purge clears the local tier on every instance; remote entries are kept
+func (c *LayeredCache) Purge(ctx context.Context) {
+	c.local.Clear()
+	c.publish(ctx, invalidation{All: true})
+}
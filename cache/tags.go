@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KEYS[1] entry, KEYS[2..] tag sets; ARGV value, ttl (ms, 0 = none)
+// Tag sets live as long as their longest-lived member
+var setTaggedScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+  redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+  redis.call('SET', KEYS[1], ARGV[1])
+end
+for i = 2, #KEYS do
+  local current = redis.call('PTTL', KEYS[i])
+  redis.call('SADD', KEYS[i], KEYS[1])
+  if ttl == 0 then
+    redis.call('PERSIST', KEYS[i])
+  elseif current == -2 or (current ~= -1 and current < ttl) then
+    redis.call('PEXPIRE', KEYS[i], ttl)
+  end
+end
+return 1
+`)
+
+// KEYS tag sets; deletes every member and the sets themselves
+var invalidateTagsScript = redis.NewScript(`
+local deleted = 0
+for i = 1, #KEYS do
+  local members = redis.call('SMEMBERS', KEYS[i])
+  for j = 1, #members, 500 do
+    deleted = deleted + redis.call('DEL', unpack(members, j, math.min(j + 499, #members)))
+  end
+  redis.call('DEL', KEYS[i])
+end
+return deleted
+`)
+
+func (c *RedisCache) tagKey(tag string) string {
+	return c.prefix + ":tag:" + tag
+}
+
+// SetWithTags stores value under key for ttl and records it under each tag
+// (e.g. "trip:123", "driver:42") so InvalidateTag can remove it later. In
+// cluster mode the key and its tags must share a hash slot via {hash tags}.
+func (c *RedisCache) SetWithTags(ctx context.Context, key, value string, ttl time.Duration, tags ...string) error {
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, c.key(key))
+	for _, tag := range tags {
+		keys = append(keys, c.tagKey(tag))
+	}
+
+	if err := setTaggedScript.Run(ctx, c.client, keys, value, ttl.Milliseconds()).Err(); err != nil {
+		return fmt.Errorf("failed to set %s in cache: %w", key, err)
+	}
+	return nil
+}
+
+// InvalidateTag atomically deletes every entry recorded under the tags and
+// returns how many entries were removed
+func (c *RedisCache) InvalidateTag(ctx context.Context, tags ...string) (int64, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = c.tagKey(tag)
+	}
+
+	deleted, err := invalidateTagsScript.Run(ctx, c.client, keys).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to invalidate cache tags: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound is returned by loaders for missing records and by TypedCache
+// while a not-found result is negatively cached
+var ErrNotFound = errors.New("cache: not found")
+
+// notFoundMarker is stored for negatively cached keys
+const notFoundMarker = "\x00notfound"
+
+// TypedConfig tunes a TypedCache
+type TypedConfig struct {
+	// TTL is the base expiry of cached values
+	TTL time.Duration
+	// Jitter spreads expiry by up to this fraction of TTL (e.g. 0.1 = ±10%)
+	// so keys written together do not expire together
+	Jitter float64
+	// NegativeTTL caches ErrNotFound from loaders; 0 disables negative caching
+	NegativeTTL time.Duration
+	// LoadTimeout bounds a shared load, which runs detached from the
+	// cancellation of the caller that started it
+	LoadTimeout time.Duration
+}
+
+// DefaultTypedConfig returns the default typed cache settings
+func DefaultTypedConfig() TypedConfig {
+	return TypedConfig{
+		TTL:         5 * time.Minute,
+		Jitter:      0.1,
+		NegativeTTL: 30 * time.Second,
+		LoadTimeout: 10 * time.Second,
+	}
+}
+
+// TypedCache stores values of type T in a Cache using a Codec
+type TypedCache[T any] struct {
+	cache  Cache
+	codec  Codec[T]
+	config TypedConfig
+	group  singleflight.Group
+}
+
+// NewTypedCache wraps cache; a nil codec defaults to JSON
+func NewTypedCache[T any](cache Cache, codec Codec[T], config TypedConfig) *TypedCache[T] {
+	if codec == nil {
+		codec = JSONCodec[T]{}
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultTypedConfig().TTL
+	}
+	if config.Jitter < 0 {
+		config.Jitter = 0
+	}
+	if config.LoadTimeout <= 0 {
+		config.LoadTimeout = DefaultTypedConfig().LoadTimeout
+	}
+	return &TypedCache[T]{
+		cache:  cache,
+		codec:  codec,
+		config: config,
+	}
+}
+
+func (c *TypedCache[T]) jitter(ttl time.Duration) time.Duration {
+	if c.config.Jitter == 0 {
+		return ttl
+	}
+	spread := float64(ttl) * c.config.Jitter
+	return ttl + time.Duration((rand.Float64()*2-1)*spread)
+}
+
+// Get returns the value of key, ErrCacheMiss, or ErrNotFound when a missing
+// record is negatively cached
+func (c *TypedCache[T]) Get(ctx context.Context, key string) (T, error) {
+	var zero T
+	data, err := c.cache.Get(ctx, key)
+	if err != nil {
+		return zero, err
+	}
+	if data == notFoundMarker {
+		return zero, ErrNotFound
+	}
+
+	value, err := c.codec.Unmarshal([]byte(data))
+	if err != nil {
+		return zero, fmt.Errorf("failed to decode %s from cache: %w", key, err)
+	}
+	return value, nil
+}
+
+// Set stores value under key for the configured TTL with jitter
+func (c *TypedCache[T]) Set(ctx context.Context, key string, value T) error {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s for cache: %w", key, err)
+	}
+	return c.cache.Set(ctx, key, string(data), c.jitter(c.config.TTL))
+}
+
+// Delete removes keys, including negatively cached ones
+func (c *TypedCache[T]) Delete(ctx context.Context, keys ...string) error {
+	return c.cache.Delete(ctx, keys...)
+}
+
+// GetOrLoad returns the cached value of key or calls load and caches the
+// result. Concurrent callers for the same key share a single load, and a
+// load returning ErrNotFound is cached for NegativeTTL. Cache failures are
+// logged and fall through to load. The shared load keeps ctx's values but not
+// its cancellation, bounded by LoadTimeout instead, so one caller going away
+// does not fail everyone waiting on the same key; each caller still returns
+// as soon as its own ctx is done.
+func (c *TypedCache[T]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	value, err := c.Get(ctx, key)
+	switch {
+	case err == nil, errors.Is(err, ErrNotFound):
+		return value, err
+	case !errors.Is(err, ErrCacheMiss):
+		log.Printf("⚠️ Cache read for %s failed, loading from source: %v", key, err)
+	}
+
+	results := c.group.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.config.LoadTimeout)
+		defer cancel()
+
+		value, err := load(ctx)
+		if errors.Is(err, ErrNotFound) {
+			if c.config.NegativeTTL > 0 {
+				if err := c.cache.Set(ctx, key, notFoundMarker, c.config.NegativeTTL); err != nil {
+					log.Printf("⚠️ Failed to cache not-found for %s: %v", key, err)
+				}
+			}
+			return value, err
+		}
+		if err != nil {
+			return value, err
+		}
+
+		if err := c.Set(ctx, key, value); err != nil {
+			log.Printf("⚠️ Failed to cache %s: %v", key, err)
+		}
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case res := <-results:
+		value, _ = res.Val.(T)
+		return value, res.Err
+	}
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCancelledCallerDoesNotFailSharedLoad(t *testing.T) {
+	c := NewTypedCache[string](NewMemoryCache(0), nil, TypedConfig{})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	load := func(ctx context.Context) (string, error) {
+		once.Do(func() { close(started) })
+		select {
+		case <-release:
+			return "fare-table", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad(firstCtx, "fares", load)
+		firstErr <- err
+	}()
+	<-started
+
+	second := make(chan string, 1)
+	go func() {
+		value, _ := c.GetOrLoad(context.Background(), "fares", load)
+		second <- value
+	}()
+
+	cancelFirst()
+	if err := <-firstErr; err != context.Canceled {
+		t.Fatalf("first caller = %v, want context.Canceled", err)
+	}
+	close(release)
+
+	select {
+	case value := <-second:
+		if value != "fare-table" {
+			t.Errorf("second caller got %q", value)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("second caller never received the shared load")
+	}
+}
@@ -0,0 +1,236 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mihirk-khode/motocabz-common/util/timefmt"
+	"github.com/mihirk-khode/motocabz-common/websocket"
+	"github.com/redis/go-redis/v9"
+)
+
+// Message is a chat message exchanged between the rider and driver of a trip
+type Message struct {
+	ID            string    `json:"id"`
+	TripID        string    `json:"tripId"`
+	SenderID      string    `json:"senderId"`
+	SenderType    string    `json:"senderType"`
+	RecipientID   string    `json:"recipientId"`
+	RecipientType string    `json:"recipientType"`
+	Text          string    `json:"text"`
+	SentAt        time.Time `json:"sentAt"`
+}
+
+// Config configures chat persistence
+type Config struct {
+	// HistoryLimit caps the number of stored messages per trip
+	HistoryLimit int64
+	// ActiveTTL bounds how long an open chat is kept, as a safety net
+	ActiveTTL time.Duration
+	// RetentionAfterCompletion is how long messages are kept after the trip closes
+	RetentionAfterCompletion time.Duration
+	KeyPrefix                string
+}
+
+// Service relays chat messages between trip participants over WebSocket
+type Service struct {
+	client  redis.UniversalClient
+	ws      websocket.IWebSocketManager
+	config  Config
+	filters []Filter
+}
+
+// Chat errors
+var (
+	ErrChatNotOpen    = errors.New("chat is not open for this trip")
+	ErrNotParticipant = errors.New("sender is not a participant of this trip")
+	ErrEmptyMessage   = errors.New("chat message is empty")
+)
+
+// NewService creates a new chat service; filters run in order on every message
+func NewService(client redis.UniversalClient, ws websocket.IWebSocketManager, config Config, filters ...Filter) *Service {
+	if config.HistoryLimit <= 0 {
+		config.HistoryLimit = 200
+	}
+	if config.ActiveTTL <= 0 {
+		config.ActiveTTL = 24 * time.Hour
+	}
+	if config.RetentionAfterCompletion <= 0 {
+		config.RetentionAfterCompletion = 72 * time.Hour
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "chat:trip:"
+	}
+	return &Service{
+		client:  client,
+		ws:      ws,
+		config:  config,
+		filters: filters,
+	}
+}
+
+// tripKey hash-tags the trip so its message list and participants share a
+// Redis Cluster slot for CloseTrip's transaction
+func (s *Service) tripKey(tripID, suffix string) string {
+	return "{" + s.config.KeyPrefix + tripID + "}:" + suffix
+}
+
+func (s *Service) messagesKey(tripID string) string {
+	return s.tripKey(tripID, "messages")
+}
+
+func (s *Service) participantsKey(tripID string) string {
+	return s.tripKey(tripID, "participants")
+}
+
+// OpenTrip starts a chat between the rider and driver of a trip
+func (s *Service) OpenTrip(ctx context.Context, tripID, riderID, driverID string) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.participantsKey(tripID), websocket.UserTypeRider, riderID, websocket.UserTypeDriver, driverID)
+		pipe.Expire(ctx, s.participantsKey(tripID), s.config.ActiveTTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open chat for trip %s: %w", tripID, err)
+	}
+	return nil
+}
+
+// CloseTrip stops accepting messages and keeps history for the retention period
+func (s *Service) CloseTrip(ctx context.Context, tripID string) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.participantsKey(tripID))
+		pipe.Expire(ctx, s.messagesKey(tripID), s.config.RetentionAfterCompletion)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to close chat for trip %s: %w", tripID, err)
+	}
+	return nil
+}
+
+// Send filters, stores and delivers a message to the other participant
+func (s *Service) Send(ctx context.Context, tripID, senderID, senderType, text string) (*Message, error) {
+	recipientID, recipientType, err := s.counterpart(ctx, tripID, senderID, senderType)
+	if err != nil {
+		return nil, err
+	}
+
+	text = strings.TrimSpace(text)
+	for _, filter := range s.filters {
+		if text, err = filter.Filter(text); err != nil {
+			return nil, err
+		}
+	}
+	if text == "" {
+		return nil, ErrEmptyMessage
+	}
+
+	msg := &Message{
+		ID:            uuid.NewString(),
+		TripID:        tripID,
+		SenderID:      senderID,
+		SenderType:    senderType,
+		RecipientID:   recipientID,
+		RecipientType: recipientType,
+		Text:          text,
+		SentAt:        time.Now().UTC(),
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal chat message: %w", err)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, s.messagesKey(tripID), data)
+		pipe.LTrim(ctx, s.messagesKey(tripID), -s.config.HistoryLimit, -1)
+		pipe.Expire(ctx, s.messagesKey(tripID), s.config.ActiveTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store chat message: %w", err)
+	}
+
+	wsMsg := websocket.NewPayloadMessage(msg.toPayload())
+	s.ws.SendMessage(recipientID, recipientType, wsMsg)
+	s.ws.SendMessage(senderID, senderType, wsMsg) // echo so the sender's other screens stay in sync
+	return msg, nil
+}
+
+// Typing notifies the other participant that the sender started or stopped typing
+func (s *Service) Typing(ctx context.Context, tripID, senderID, senderType string, typing bool) error {
+	recipientID, recipientType, err := s.counterpart(ctx, tripID, senderID, senderType)
+	if err != nil {
+		return err
+	}
+
+	return s.ws.SendMessage(recipientID, recipientType, websocket.NewPayloadMessage(websocket.ChatTypingPayload{
+		TripID:     tripID,
+		SenderID:   senderID,
+		SenderType: senderType,
+		Typing:     typing,
+	}))
+}
+
+// History returns up to limit of the most recent messages of a trip, oldest first
+func (s *Service) History(ctx context.Context, tripID string, limit int64) ([]Message, error) {
+	if limit <= 0 || limit > s.config.HistoryLimit {
+		limit = s.config.HistoryLimit
+	}
+
+	raws, err := s.client.LRange(ctx, s.messagesKey(tripID), -limit, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chat history for trip %s: %w", tripID, err)
+	}
+
+	messages := make([]Message, 0, len(raws))
+	for _, raw := range raws {
+		var msg Message
+		if err := json.Unmarshal([]byte(raw), &msg); err == nil {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+// counterpart returns the participant the sender is chatting with
+func (s *Service) counterpart(ctx context.Context, tripID, senderID, senderType string) (string, string, error) {
+	participants, err := s.client.HGetAll(ctx, s.participantsKey(tripID)).Result()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load chat participants for trip %s: %w", tripID, err)
+	}
+	if len(participants) == 0 {
+		return "", "", ErrChatNotOpen
+	}
+	if participants[senderType] != senderID {
+		return "", "", ErrNotParticipant
+	}
+
+	switch senderType {
+	case websocket.UserTypeRider:
+		return participants[websocket.UserTypeDriver], websocket.UserTypeDriver, nil
+	case websocket.UserTypeDriver:
+		return participants[websocket.UserTypeRider], websocket.UserTypeRider, nil
+	default:
+		return "", "", ErrNotParticipant
+	}
+}
+
+func (m *Message) toPayload() websocket.ChatMessagePayload {
+	return websocket.ChatMessagePayload{
+		ID:            m.ID,
+		TripID:        m.TripID,
+		SenderID:      m.SenderID,
+		SenderType:    m.SenderType,
+		RecipientID:   m.RecipientID,
+		RecipientType: m.RecipientType,
+		Text:          m.Text,
+		SentAt:        timefmt.NewTime(m.SentAt),
+	}
+}
@@ -0,0 +1,67 @@
+package chat
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrMessageRejected is returned by filters that block a message outright
+var ErrMessageRejected = errors.New("chat message rejected")
+
+// Filter inspects and rewrites message text before it is stored and delivered.
+// Returning an error rejects the message.
+type Filter interface {
+	Filter(text string) (string, error)
+}
+
+// FilterFunc adapts a function to the Filter interface
+type FilterFunc func(text string) (string, error)
+
+// Filter calls f(text)
+func (f FilterFunc) Filter(text string) (string, error) {
+	return f(text)
+}
+
+var (
+	phonePattern = regexp.MustCompile(`\+?\d[\d\s\-]{7,}\d`)
+	emailPattern = regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
+)
+
+// PIIFilter masks phone numbers and email addresses so riders and drivers
+// keep communicating through the app
+func PIIFilter() Filter {
+	return FilterFunc(func(text string) (string, error) {
+		text = phonePattern.ReplaceAllString(text, "[hidden]")
+		return emailPattern.ReplaceAllString(text, "[hidden]"), nil
+	})
+}
+
+// ProfanityFilter masks the given words, matched case-insensitively as whole words
+func ProfanityFilter(words []string) Filter {
+	if len(words) == 0 {
+		return FilterFunc(func(text string) (string, error) { return text, nil })
+	}
+
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = regexp.QuoteMeta(word)
+	}
+	pattern := regexp.MustCompile(`(?i)\b(` + strings.Join(quoted, "|") + `)\b`)
+
+	return FilterFunc(func(text string) (string, error) {
+		return pattern.ReplaceAllStringFunc(text, func(match string) string {
+			return strings.Repeat("*", len(match))
+		}), nil
+	})
+}
+
+// MaxLengthFilter rejects messages longer than max characters
+func MaxLengthFilter(max int) Filter {
+	return FilterFunc(func(text string) (string, error) {
+		if len([]rune(text)) > max {
+			return "", ErrMessageRejected
+		}
+		return text, nil
+	})
+}
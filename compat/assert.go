@@ -0,0 +1,41 @@
+package compat
+
+import "encoding/json"
+
+// TB is the subset of testing.TB used by the assertions, so this package
+// does not pull the testing package into production binaries
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+}
+
+// MustMatchSchema fails the test when payload does not conform to schema.
+// payload may be raw JSON bytes or any value that marshals to JSON.
+func MustMatchSchema(t TB, schema Schema, payload interface{}) {
+	t.Helper()
+
+	data, ok := payload.([]byte)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			t.Fatalf("compat: failed to marshal %s payload: %v", schema.Name, err)
+			return
+		}
+	}
+	if err := schema.Validate(data); err != nil {
+		t.Fatalf("compat: %v", err)
+	}
+}
+
+// MustBeCompatible fails the test when the envelopes in the linked version of
+// common break the frozen contract
+func MustBeCompatible(t TB) {
+	t.Helper()
+
+	current := Current()
+	for name, frozen := range Frozen() {
+		if changes := frozen.BreakingChanges(current[name]); len(changes) > 0 {
+			t.Fatalf("compat: breaking envelope changes: %v", changes)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package compat
+
+import (
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/events"
+	"github.com/mihirk-khode/motocabz-common/websocket"
+)
+
+// Frozen envelope shapes. These are the contract consumers rely on; change
+// them only together with a coordinated rollout.
+var (
+	RsBaseSchema = Schema{
+		Name: "RsBase",
+		Fields: map[string]Field{
+			"apiVersion": {Kind: KindString},
+			"data":       {Kind: KindAny},
+			"error":      {Kind: KindObject},
+			"meta":       {Kind: KindObject},
+		},
+	}
+
+	WebSocketMessageSchema = Schema{
+		Name: "WebSocketMessage",
+		Fields: map[string]Field{
+			"type":      {Kind: KindString, Required: true},
+			"data":      {Kind: KindObject, Required: true},
+			"timestamp": {Kind: KindString, Required: true},
+			"error":     {Kind: KindString},
+		},
+	}
+
+	BaseEventSchema = Schema{
+		Name: "BaseEvent",
+		Fields: map[string]Field{
+			"id":            {Kind: KindString, Required: true},
+			"type":          {Kind: KindString, Required: true},
+			"aggregateId":   {Kind: KindString, Required: true},
+			"aggregateType": {Kind: KindString, Required: true},
+			"version":       {Kind: KindNumber, Required: true},
+			"timestamp":     {Kind: KindString, Required: true},
+			"source":        {Kind: KindString},
+			"data":          {Kind: KindAny},
+			"metadata":      {Kind: KindObject},
+		},
+	}
+)
+
+// Frozen returns the frozen schemas keyed by name
+func Frozen() map[string]Schema {
+	return map[string]Schema{
+		RsBaseSchema.Name:           RsBaseSchema,
+		WebSocketMessageSchema.Name: WebSocketMessageSchema,
+		BaseEventSchema.Name:        BaseEventSchema,
+	}
+}
+
+// Current returns the shapes derived from the types compiled into this build
+func Current() map[string]Schema {
+	return map[string]Schema{
+		RsBaseSchema.Name:           ShapeOf(RsBaseSchema.Name, common.RsBase{}),
+		WebSocketMessageSchema.Name: ShapeOf(WebSocketMessageSchema.Name, websocket.WebSocketMessage{}),
+		BaseEventSchema.Name:        ShapeOf(BaseEventSchema.Name, events.BaseEvent{}),
+	}
+}
@@ -0,0 +1,194 @@
+package compat
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind is the JSON type of a field
+type Kind string
+
+const (
+	KindString Kind = "string"
+	KindNumber Kind = "number"
+	KindBool   Kind = "bool"
+	KindObject Kind = "object"
+	KindArray  Kind = "array"
+	KindAny    Kind = "any"
+)
+
+// Field describes one top-level property of an envelope
+type Field struct {
+	Kind     Kind `json:"kind"`
+	Required bool `json:"required"`
+}
+
+// Schema is the JSON shape of an envelope. Unknown fields are allowed so that
+// additive changes stay compatible.
+type Schema struct {
+	Name   string           `json:"name"`
+	Fields map[string]Field `json:"fields"`
+}
+
+// Validate checks that a JSON payload conforms to the schema
+func (s Schema) Validate(data []byte) error {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("%s: payload is not a JSON object: %w", s.Name, err)
+	}
+
+	var problems []string
+	for _, name := range s.fieldNames() {
+		field := s.Fields[name]
+		raw, ok := payload[name]
+		if !ok {
+			if field.Required {
+				problems = append(problems, fmt.Sprintf("missing required field %q", name))
+			}
+			continue
+		}
+		kind := kindOfJSON(raw)
+		if kind == "null" {
+			if field.Required && field.Kind != KindAny {
+				problems = append(problems, fmt.Sprintf("field %q is null", name))
+			}
+			continue
+		}
+		if field.Kind != KindAny && kind != field.Kind {
+			problems = append(problems, fmt.Sprintf("field %q is %s, want %s", name, kind, field.Kind))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s: %s", s.Name, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// BreakingChanges lists the changes from s to next that would break consumers
+// of s: removed fields, changed kinds, and fields that became required
+func (s Schema) BreakingChanges(next Schema) []string {
+	var changes []string
+	for _, name := range s.fieldNames() {
+		old := s.Fields[name]
+		cur, ok := next.Fields[name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%s: field %q removed", s.Name, name))
+		case old.Kind != cur.Kind && old.Kind != KindAny:
+			changes = append(changes, fmt.Sprintf("%s: field %q changed from %s to %s", s.Name, name, old.Kind, cur.Kind))
+		case !old.Required && cur.Required:
+			changes = append(changes, fmt.Sprintf("%s: field %q became required", s.Name, name))
+		}
+	}
+	for _, name := range next.fieldNames() {
+		if _, ok := s.Fields[name]; !ok && next.Fields[name].Required {
+			changes = append(changes, fmt.Sprintf("%s: new required field %q", s.Name, name))
+		}
+	}
+	return changes
+}
+
+func (s Schema) fieldNames() []string {
+	names := make([]string, 0, len(s.Fields))
+	for name := range s.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func kindOfJSON(raw json.RawMessage) Kind {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" {
+		return "null"
+	}
+	switch trimmed[0] {
+	case '"':
+		return KindString
+	case '{':
+		return KindObject
+	case '[':
+		return KindArray
+	case 't', 'f':
+		return KindBool
+	case 'n':
+		return "null"
+	default:
+		return KindNumber
+	}
+}
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	rawMsgType = reflect.TypeOf(json.RawMessage{})
+)
+
+// ShapeOf derives the schema of a struct from its json tags
+func ShapeOf(name string, v interface{}) Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	schema := Schema{Name: name, Fields: make(map[string]Field)}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		jsonName := parts[0]
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+		omitempty := false
+		for _, opt := range parts[1:] {
+			if opt == "omitempty" || opt == "omitzero" {
+				omitempty = true
+			}
+		}
+
+		schema.Fields[jsonName] = Field{
+			Kind:     kindOfType(f.Type),
+			Required: !omitempty && f.Type.Kind() != reflect.Ptr,
+		}
+	}
+	return schema
+}
+
+func kindOfType(t reflect.Type) Kind {
+	if t == timeType {
+		return KindString
+	}
+	if t == rawMsgType {
+		return KindAny
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return kindOfType(t.Elem())
+	case reflect.String:
+		return KindString
+	case reflect.Bool:
+		return KindBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return KindNumber
+	case reflect.Slice, reflect.Array:
+		return KindArray
+	case reflect.Map, reflect.Struct:
+		return KindObject
+	default:
+		return KindAny
+	}
+}
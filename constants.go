@@ -1,5 +1,7 @@
 package common
 
+import "time"
+
 // Service Names
 const (
 	ServiceTrip     = "trip-service"
@@ -75,6 +77,27 @@ const (
 	EnvRedisPort     = "REDIS_PORT"
 	EnvRedisPassword = "REDIS_PASSWORD"
 	EnvRedisDB       = "REDIS_DB"
+
+	EnvRedisClusterAddrs  = "REDIS_CLUSTER_ADDRS"
+	EnvRedisSentinelAddrs = "REDIS_SENTINEL_ADDRS"
+	EnvRedisMasterName    = "REDIS_MASTER_NAME"
+	EnvRedisReplicaAddrs  = "REDIS_REPLICA_ADDRS"
+
+	EnvRedisUsername              = "REDIS_USERNAME"
+	EnvRedisTLS                   = "REDIS_TLS"
+	EnvRedisTLSCAFile             = "REDIS_TLS_CA_FILE"
+	EnvRedisTLSInsecureSkipVerify = "REDIS_TLS_INSECURE_SKIP_VERIFY"
+
+	EnvRedisStartupRetries = "REDIS_STARTUP_RETRIES"
+	EnvRedisLazyConnect    = "REDIS_LAZY_CONNECT"
+
+	// Timeout Overrides
+	EnvBiddingTimer          = "BIDDING_TIMER_DURATION"
+	EnvWebSocketPingInterval = "WS_PING_INTERVAL"
+	EnvWebSocketWriteTimeout = "WS_WRITE_TIMEOUT"
+	EnvWebSocketReadTimeout  = "WS_READ_TIMEOUT"
+	EnvWebSocketPongTimeout  = "WS_PONG_TIMEOUT"
+	EnvRequestTimeout        = "REQUEST_TIMEOUT"
 )
 
 // WebSocket Message Types
@@ -89,6 +112,9 @@ const (
 	WSMessageTypePong                  = "pong"
 	WSMessageTypeConnectionEstablished = "connection_established"
 	WSMessageTypeSystemMessage         = "system_message"
+	WSMessageTypeChatMessage           = "chat_message"
+	WSMessageTypeChatTyping            = "chat_typing"
+	WSMessageTypeEmergencyAlert        = "emergency_alert"
 )
 
 const (
@@ -123,6 +149,14 @@ const (
 	EventTypeBidRejected           = "BidRejected"
 	EventTypeBidCountered          = "BidCountered"
 	EventTypeInstantMatched        = "InstantMatched"
+	EventTypeDriverArrived         = "DriverArrived"
+	EventTypeTripStarted           = "TripStarted"
+	EventTypeEmergencyRaised       = "EmergencyRaised"
+	EventTypeEmergencyAcknowledged = "EmergencyAcknowledged"
+	EventTypeEmergencyEscalated    = "EmergencyEscalated"
+	EventTypeEmergencyResolved     = "EmergencyResolved"
+	EventTypeDriverOnline          = "DriverOnline"
+	EventTypeDriverOffline         = "DriverOffline"
 )
 
 // Aggregate Types
@@ -131,6 +165,8 @@ const (
 	AggregateTypeBiddingSession = "BiddingSession"
 	AggregateTypeBooking        = "Booking"
 	AggregateTypeBidding        = "Bidding"
+	AggregateTypeEmergency      = "Emergency"
+	AggregateTypeDriver         = "Driver"
 )
 
 // User Types
@@ -174,6 +210,29 @@ const (
 	BiddingStatusCancelled = "cancelled"
 )
 
+// Subscription Tiers
+const (
+	SubscriptionTierBasic      = "BASIC"
+	SubscriptionTierPremium    = "PREMIUM"
+	SubscriptionTierEnterprise = "ENTERPRISE"
+)
+
+// Vehicle Classes
+const (
+	VehicleClassEconomy = "ECONOMY"
+	VehicleClassComfort = "COMFORT"
+	VehicleClassMinivan = "MINIVAN"
+	VehicleClassLuxury  = "LUXURY"
+)
+
+// Vehicle Capacity Limits
+const (
+	MinVehicleSeats      = 1
+	MaxVehicleSeats      = 14
+	MaxVehicleLuggage    = 12
+	MaxPassengersPerTrip = MaxVehicleSeats
+)
+
 // Location Validation
 const (
 	MinLatitude  = -90.0
@@ -182,13 +241,14 @@ const (
 	MaxLongitude = 180.0
 )
 
-// Timeouts and Intervals
+// Timeouts and Intervals in whole seconds, kept for existing callers.
+// Deprecated: use GetTimeouts, which returns typed values with env overrides.
 const (
-	BiddingTimerDuration  = 20 // seconds
-	WebSocketPingInterval = 30 // seconds
-	WebSocketWriteTimeout = 10 // seconds
-	WebSocketReadTimeout  = 10 // seconds
-	DefaultRequestTimeout = 30 // seconds
+	BiddingTimerDuration  = int(DefaultBiddingTimer / time.Second)
+	WebSocketPingInterval = int(DefaultWebSocketPingInterval / time.Second)
+	WebSocketWriteTimeout = int(DefaultWebSocketWriteTimeout / time.Second)
+	WebSocketReadTimeout  = int(DefaultWebSocketReadTimeout / time.Second)
+	DefaultRequestTimeout = int(DefaultRequestTimeoutPeriod / time.Second)
 )
 
 // Pub/Sub Topics
@@ -200,6 +260,7 @@ const (
 	TopicDriverNotifications = "driver.notifications"
 	TopicRiderNotifications  = "rider.notifications"
 	TopicBiddingEvents       = "bidding.events"
+	TopicEmergencyEvents     = "emergency.events"
 )
 
 // Dapr Components
@@ -0,0 +1,117 @@
+package crashreport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Sources identify where a panic was recovered
+const (
+	SourceHTTP      = "http"
+	SourceGRPC      = "grpc"
+	SourceTask      = "task"
+	SourceWebSocket = "websocket"
+)
+
+// Report describes a recovered panic
+type Report struct {
+	Service   string            `json:"service,omitempty"`
+	Source    string            `json:"source"`
+	Operation string            `json:"operation,omitempty"`
+	RequestID string            `json:"requestId,omitempty"`
+	TraceID   string            `json:"traceId,omitempty"`
+	Message   string            `json:"message"`
+	Stack     string            `json:"stack"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// Sink receives crash reports
+type Sink interface {
+	Capture(ctx context.Context, report Report)
+}
+
+// SinkFunc adapts a function to Sink
+type SinkFunc func(ctx context.Context, report Report)
+
+// Capture calls f
+func (f SinkFunc) Capture(ctx context.Context, report Report) {
+	f(ctx, report)
+}
+
+// LogSink writes reports to the standard logger
+type LogSink struct{}
+
+// Capture logs the report with its stack trace
+func (LogSink) Capture(_ context.Context, report Report) {
+	log.Printf("💥 Panic recovered [%s %s] request=%s trace=%s: %s\n%s",
+		report.Source, report.Operation, report.RequestID, report.TraceID, report.Message, report.Stack)
+}
+
+// MultiSink fans a report out to several sinks
+type MultiSink []Sink
+
+// Capture forwards the report to every sink
+func (m MultiSink) Capture(ctx context.Context, report Report) {
+	for _, sink := range m {
+		sink.Capture(ctx, report)
+	}
+}
+
+var (
+	mu          sync.RWMutex
+	defaultSink Sink = LogSink{}
+	serviceName string
+)
+
+// SetSink installs the process-wide sink; nil restores LogSink
+func SetSink(sink Sink) {
+	mu.Lock()
+	defer mu.Unlock()
+	if sink == nil {
+		sink = LogSink{}
+	}
+	defaultSink = sink
+}
+
+// SetService sets the service name stamped on every report
+func SetService(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	serviceName = name
+}
+
+// NewReport builds a report for a recovered value, capturing the current stack
+func NewReport(source, operation string, recovered interface{}) Report {
+	mu.RLock()
+	service := serviceName
+	mu.RUnlock()
+
+	return Report{
+		Service:   service,
+		Source:    source,
+		Operation: operation,
+		Message:   fmt.Sprint(recovered),
+		Stack:     string(debug.Stack()),
+		Timestamp: time.Now().UTC(),
+	}
+}
+
+// Capture forwards report to the configured sink. A panicking sink is
+// swallowed so reporting never takes the process down.
+func Capture(ctx context.Context, report Report) {
+	mu.RLock()
+	sink := defaultSink
+	mu.RUnlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Crash report sink panicked: %v", r)
+		}
+	}()
+	sink.Capture(ctx, report)
+}
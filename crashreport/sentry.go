@@ -0,0 +1,173 @@
+package crashreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sentryQueueSize bounds the reports waiting to be sent
+const sentryQueueSize = 100
+
+// SentrySink posts reports to a Sentry-compatible store endpoint. Reports are
+// sent by a background worker so a slow or unreachable Sentry never delays
+// the recovering request; when the queue is full new reports are dropped.
+type SentrySink struct {
+	endpoint    string
+	authHeader  string
+	environment string
+	release     string
+	client      *http.Client
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan []byte
+	done   chan struct{}
+}
+
+// NewSentrySink parses a DSN of the form https://<key>@<host>/<project>
+func NewSentrySink(dsn, environment, release string) (*SentrySink, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sentry dsn: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("sentry dsn is missing the public key")
+	}
+
+	project := strings.Trim(u.Path, "/")
+	if project == "" {
+		return nil, fmt.Errorf("sentry dsn is missing the project id")
+	}
+
+	endpoint := fmt.Sprintf("%s://%s/api/%s/store/", u.Scheme, u.Host, project)
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=motocabz-common/1.0, sentry_key=%s", u.User.Username())
+
+	s := &SentrySink{
+		endpoint:    endpoint,
+		authHeader:  auth,
+		environment: environment,
+		release:     release,
+		client:      &http.Client{Timeout: 5 * time.Second},
+		queue:       make(chan []byte, sentryQueueSize),
+		done:        make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// Close stops accepting reports and waits until the queued ones are sent or
+// ctx is done
+func (s *SentrySink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run sends queued reports until Close
+func (s *SentrySink) run() {
+	defer close(s.done)
+	for body := range s.queue {
+		s.send(body)
+	}
+}
+
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Platform    string                 `json:"platform"`
+	Logger      string                 `json:"logger"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	Message     string                 `json:"message"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
+
+// Capture queues the report for sending; failures are logged, never returned
+func (s *SentrySink) Capture(ctx context.Context, report Report) {
+	tags := map[string]string{"source": report.Source}
+	for k, v := range report.Tags {
+		tags[k] = v
+	}
+	if report.RequestID != "" {
+		tags["request_id"] = report.RequestID
+	}
+	if report.TraceID != "" {
+		tags["trace_id"] = report.TraceID
+	}
+
+	event := sentryEvent{
+		EventID:     strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Timestamp:   report.Timestamp.Format(time.RFC3339),
+		Level:       "fatal",
+		Platform:    "go",
+		Logger:      report.Source,
+		ServerName:  report.Service,
+		Environment: s.environment,
+		Release:     s.release,
+		Message:     fmt.Sprintf("panic in %s: %s", report.Operation, report.Message),
+		Tags:        tags,
+		Extra:       map[string]interface{}{"stack": report.Stack},
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to encode crash report: %v", err)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		log.Printf("⚠️ Dropping crash report %s: sink closed", event.EventID)
+		return
+	}
+	select {
+	case s.queue <- body:
+	default:
+		log.Printf("⚠️ Dropping crash report %s: send queue full", event.EventID)
+	}
+}
+
+// send posts one encoded event
+func (s *SentrySink) send(body []byte) {
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Failed to build crash report request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", s.authHeader)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		log.Printf("Failed to send crash report: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("Crash report rejected with status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,42 @@
+package crashreport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSentrySinkSendsInBackground(t *testing.T) {
+	release := make(chan struct{})
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		received.Add(1)
+	}))
+	defer server.Close()
+
+	sink, err := NewSentrySink(strings.Replace(server.URL, "://", "://key@", 1)+"/42", "test", "")
+	if err != nil {
+		t.Fatalf("NewSentrySink: %v", err)
+	}
+
+	start := time.Now()
+	sink.Capture(context.Background(), NewReport(SourceHTTP, "GET /trips", "boom"))
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("Capture blocked for %v on a slow Sentry", elapsed)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := sink.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if received.Load() != 1 {
+		t.Errorf("Sentry received %d reports, want 1", received.Load())
+	}
+}
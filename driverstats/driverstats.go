@@ -0,0 +1,257 @@
+package driverstats
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/refdata"
+	"github.com/redis/go-redis/v9"
+)
+
+// Outcomes recorded per driver
+const (
+	OutcomeOffered   = "offered"
+	OutcomeAccepted  = "accepted"
+	OutcomeDeclined  = "declined"
+	OutcomeCancelled = "cancelled"
+	OutcomeCompleted = "completed"
+)
+
+var outcomes = []string{OutcomeOffered, OutcomeAccepted, OutcomeDeclined, OutcomeCancelled, OutcomeCompleted}
+
+// Config configures the tracker
+type Config struct {
+	KeyPrefix string
+	// DailyRetention and WeeklyRetention bound how long rollups are kept
+	DailyRetention  time.Duration
+	WeeklyRetention time.Duration
+	// PriorAcceptanceRate and PriorWeight smooth rates for drivers with few
+	// offers, so a single decline does not sink a new driver's score
+	PriorAcceptanceRate   float64
+	PriorCancellationRate float64
+	PriorWeight           float64
+}
+
+// Counts holds raw outcome counters
+type Counts struct {
+	Offered   int64 `json:"offered"`
+	Accepted  int64 `json:"accepted"`
+	Declined  int64 `json:"declined"`
+	Cancelled int64 `json:"cancelled"`
+	Completed int64 `json:"completed"`
+}
+
+func (c *Counts) add(field string, n int64) {
+	switch field {
+	case OutcomeOffered:
+		c.Offered += n
+	case OutcomeAccepted:
+		c.Accepted += n
+	case OutcomeDeclined:
+		c.Declined += n
+	case OutcomeCancelled:
+		c.Cancelled += n
+	case OutcomeCompleted:
+		c.Completed += n
+	}
+}
+
+// Rates are the derived driver quality rates
+type Rates struct {
+	DriverID         string  `json:"driverId"`
+	Counts           Counts  `json:"counts"`
+	AcceptanceRate   float64 `json:"acceptanceRate"`
+	CancellationRate float64 `json:"cancellationRate"`
+	CompletionRate   float64 `json:"completionRate"`
+}
+
+// Tracker records driver responses to offers with daily and weekly rollups
+type Tracker struct {
+	client redis.UniversalClient
+	config Config
+	now    func() time.Time
+}
+
+// NewTracker creates a new driver stats tracker
+func NewTracker(client redis.UniversalClient, config Config) *Tracker {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "driverstats"
+	}
+	if config.DailyRetention <= 0 {
+		config.DailyRetention = 35 * 24 * time.Hour
+	}
+	if config.WeeklyRetention <= 0 {
+		config.WeeklyRetention = 15 * 7 * 24 * time.Hour
+	}
+	if config.PriorAcceptanceRate <= 0 {
+		config.PriorAcceptanceRate = 0.8
+	}
+	if config.PriorCancellationRate <= 0 {
+		config.PriorCancellationRate = 0.05
+	}
+	if config.PriorWeight <= 0 {
+		config.PriorWeight = 10
+	}
+	return &Tracker{
+		client: client,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// dailyKey buckets by the local (Addis Ababa) calendar day
+func (t *Tracker) dailyKey(driverID string, day time.Time) string {
+	return fmt.Sprintf("%s:%s:d:%s", t.config.KeyPrefix, driverID, refdata.InAddisAbaba(day).Format("20060102"))
+}
+
+func (t *Tracker) weeklyKey(driverID string, day time.Time) string {
+	year, week := refdata.InAddisAbaba(day).ISOWeek()
+	return fmt.Sprintf("%s:%s:w:%d%02d", t.config.KeyPrefix, driverID, year, week)
+}
+
+// Record increments the counter for an outcome in the current day and week
+func (t *Tracker) Record(ctx context.Context, driverID, outcome string) error {
+	now := t.now()
+	daily := t.dailyKey(driverID, now)
+	weekly := t.weeklyKey(driverID, now)
+
+	_, err := t.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, daily, outcome, 1)
+		pipe.Expire(ctx, daily, t.config.DailyRetention)
+		pipe.HIncrBy(ctx, weekly, outcome, 1)
+		pipe.Expire(ctx, weekly, t.config.WeeklyRetention)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record %s for driver %s: %w", outcome, driverID, err)
+	}
+	return nil
+}
+
+// Offered records an offer sent to the driver
+func (t *Tracker) Offered(ctx context.Context, driverID string) error {
+	return t.Record(ctx, driverID, OutcomeOffered)
+}
+
+// Accepted records an accepted offer
+func (t *Tracker) Accepted(ctx context.Context, driverID string) error {
+	return t.Record(ctx, driverID, OutcomeAccepted)
+}
+
+// Declined records a declined or expired offer
+func (t *Tracker) Declined(ctx context.Context, driverID string) error {
+	return t.Record(ctx, driverID, OutcomeDeclined)
+}
+
+// Cancelled records a trip cancelled by the driver after accepting
+func (t *Tracker) Cancelled(ctx context.Context, driverID string) error {
+	return t.Record(ctx, driverID, OutcomeCancelled)
+}
+
+// Completed records a completed trip
+func (t *Tracker) Completed(ctx context.Context, driverID string) error {
+	return t.Record(ctx, driverID, OutcomeCompleted)
+}
+
+// Daily returns the counts of the trailing number of days, including today
+func (t *Tracker) Daily(ctx context.Context, driverID string, days int) (Counts, error) {
+	counts, err := t.sumKeys(ctx, [][]string{t.dailyKeys(driverID, days)})
+	if err != nil {
+		return Counts{}, err
+	}
+	return counts[0], nil
+}
+
+// Weekly returns the counts of the current ISO week
+func (t *Tracker) Weekly(ctx context.Context, driverID string) (Counts, error) {
+	counts, err := t.sumKeys(ctx, [][]string{{t.weeklyKey(driverID, t.now())}})
+	if err != nil {
+		return Counts{}, err
+	}
+	return counts[0], nil
+}
+
+// Rates returns smoothed rates over the trailing number of days
+func (t *Tracker) Rates(ctx context.Context, driverID string, days int) (Rates, error) {
+	rates, err := t.RatesFor(ctx, []string{driverID}, days)
+	if err != nil {
+		return Rates{}, err
+	}
+	return rates[driverID], nil
+}
+
+// RatesFor returns rates for many drivers in a single round trip, for the matching scorer
+func (t *Tracker) RatesFor(ctx context.Context, driverIDs []string, days int) (map[string]Rates, error) {
+	groups := make([][]string, len(driverIDs))
+	for i, id := range driverIDs {
+		groups[i] = t.dailyKeys(id, days)
+	}
+
+	counts, err := t.sumKeys(ctx, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]Rates, len(driverIDs))
+	for i, id := range driverIDs {
+		rates[id] = t.rates(id, counts[i])
+	}
+	return rates, nil
+}
+
+func (t *Tracker) rates(driverID string, c Counts) Rates {
+	w := t.config.PriorWeight
+	r := Rates{
+		DriverID:         driverID,
+		Counts:           c,
+		AcceptanceRate:   (float64(c.Accepted) + t.config.PriorAcceptanceRate*w) / (float64(c.Offered) + w),
+		CancellationRate: (float64(c.Cancelled) + t.config.PriorCancellationRate*w) / (float64(c.Accepted) + w),
+	}
+	if c.Accepted > 0 {
+		r.CompletionRate = float64(c.Completed) / float64(c.Accepted)
+	}
+	return r
+}
+
+func (t *Tracker) dailyKeys(driverID string, days int) []string {
+	if days <= 0 {
+		days = 1
+	}
+	now := t.now()
+	keys := make([]string, days)
+	for i := range keys {
+		keys[i] = t.dailyKey(driverID, now.AddDate(0, 0, -i))
+	}
+	return keys
+}
+
+// sumKeys reads every hash in one pipeline and sums each group
+func (t *Tracker) sumKeys(ctx context.Context, groups [][]string) ([]Counts, error) {
+	cmds := make([][]*redis.MapStringStringCmd, len(groups))
+	_, err := t.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, keys := range groups {
+			for _, key := range keys {
+				cmds[i] = append(cmds[i], pipe.HGetAll(ctx, key))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read driver stats: %w", err)
+	}
+
+	counts := make([]Counts, len(groups))
+	for i, group := range cmds {
+		for _, cmd := range group {
+			for _, field := range outcomes {
+				if v, ok := cmd.Val()[field]; ok {
+					n, _ := strconv.ParseInt(v, 10, 64)
+					counts[i].add(field, n)
+				}
+			}
+		}
+	}
+	return counts, nil
+}
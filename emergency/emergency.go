@@ -0,0 +1,435 @@
+package emergency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/events"
+	"github.com/mihirk-khode/motocabz-common/location"
+	"github.com/mihirk-khode/motocabz-common/util/timefmt"
+	"github.com/mihirk-khode/motocabz-common/websocket"
+	"github.com/redis/go-redis/v9"
+)
+
+// Alert statuses
+const (
+	StatusOpen         = "open"
+	StatusAcknowledged = "acknowledged"
+	StatusResolved     = "resolved"
+)
+
+// Alert is an SOS raised by a rider or driver
+type Alert struct {
+	ID              string             `json:"id"`
+	TripID          string             `json:"tripId,omitempty"`
+	UserID          string             `json:"userId"`
+	UserType        string             `json:"userType"`
+	Message         string             `json:"message,omitempty"`
+	Location        *location.Location `json:"location,omitempty"`
+	Status          string             `json:"status"`
+	RaisedAt        time.Time          `json:"raisedAt"`
+	AcknowledgedBy  string             `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt  *time.Time         `json:"acknowledgedAt,omitempty"`
+	ResolvedAt      *time.Time         `json:"resolvedAt,omitempty"`
+	EscalationLevel int                `json:"escalationLevel"`
+}
+
+// Summary returns a short human readable description for SMS and logs
+func (a *Alert) Summary() string {
+	summary := fmt.Sprintf("SOS from %s %s", a.UserType, a.UserID)
+	if a.TripID != "" {
+		summary += " on trip " + a.TripID
+	}
+	if a.Location != nil {
+		summary += fmt.Sprintf(" at https://maps.google.com/?q=%s", a.Location.String())
+	}
+	if a.EscalationLevel > 0 {
+		summary += fmt.Sprintf(" (ESCALATION %d, unacknowledged)", a.EscalationLevel)
+	}
+	return summary
+}
+
+// toWebSocket converts the alert to its WebSocket representation
+func (a *Alert) toWebSocket() websocket.EmergencyAlert {
+	alert := websocket.EmergencyAlert{
+		ID:              a.ID,
+		TripID:          a.TripID,
+		UserID:          a.UserID,
+		UserType:        a.UserType,
+		Message:         a.Message,
+		Location:        a.Location,
+		Status:          a.Status,
+		RaisedAt:        timefmt.NewTime(a.RaisedAt),
+		AcknowledgedBy:  a.AcknowledgedBy,
+		EscalationLevel: a.EscalationLevel,
+	}
+	if a.AcknowledgedAt != nil {
+		t := timefmt.NewTime(*a.AcknowledgedAt)
+		alert.AcknowledgedAt = &t
+	}
+	if a.ResolvedAt != nil {
+		t := timefmt.NewTime(*a.ResolvedAt)
+		alert.ResolvedAt = &t
+	}
+	return alert
+}
+
+// LocationProvider returns the last known location of a user for the snapshot
+type LocationProvider func(ctx context.Context, userID, userType string) (*location.Location, error)
+
+// Config configures the emergency pipeline
+type Config struct {
+	// AckTimeout is how long an alert may stay unacknowledged before escalating
+	AckTimeout time.Duration
+	// MaxEscalations bounds how many times an alert escalates
+	MaxEscalations int
+	// Retention is how long alerts are kept in Redis
+	Retention time.Duration
+	// KeyPrefix is wrapped in a {hash tag} so an alert and the pending set
+	// share a cluster slot and can be updated in one transaction
+	KeyPrefix string
+	Topic     string
+}
+
+// Service raises, fans out and tracks emergency alerts
+type Service struct {
+	client    redis.UniversalClient
+	ws        websocket.IWebSocketManager
+	publisher events.Publisher
+	locate    LocationProvider
+	config    Config
+
+	notifiers           []Notifier
+	escalationNotifiers []Notifier
+}
+
+// Emergency errors
+var (
+	ErrAlertNotFound     = errors.New("emergency alert not found")
+	ErrInvalidTransition = errors.New("emergency alert cannot move to this status")
+)
+
+// errAlreadyClaimed means another escalator handled a due alert first
+var errAlreadyClaimed = errors.New("emergency alert already escalated")
+
+// maxUpdateAttempts bounds retries when an alert is changed concurrently
+const maxUpdateAttempts = 5
+
+// NewService creates a new emergency service; publisher and locate may be nil
+func NewService(client redis.UniversalClient, ws websocket.IWebSocketManager, publisher events.Publisher, locate LocationProvider, config Config) *Service {
+	if config.AckTimeout <= 0 {
+		config.AckTimeout = 30 * time.Second
+	}
+	if config.MaxEscalations <= 0 {
+		config.MaxEscalations = 3
+	}
+	if config.Retention <= 0 {
+		config.Retention = 30 * 24 * time.Hour
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "emergency"
+	}
+	if config.Topic == "" {
+		config.Topic = common.TopicEmergencyEvents
+	}
+	return &Service{
+		client:    client,
+		ws:        ws,
+		publisher: publisher,
+		locate:    locate,
+		config:    config,
+	}
+}
+
+// AddNotifier registers a channel notified as soon as an alert is raised
+func (s *Service) AddNotifier(n Notifier) {
+	s.notifiers = append(s.notifiers, n)
+}
+
+// AddEscalationNotifier registers a channel notified when an alert escalates
+func (s *Service) AddEscalationNotifier(n Notifier) {
+	s.escalationNotifiers = append(s.escalationNotifiers, n)
+}
+
+func (s *Service) prefix() string            { return "{" + strings.TrimSuffix(s.config.KeyPrefix, ":") + "}:" }
+func (s *Service) alertKey(id string) string { return s.prefix() + "alert:" + id }
+func (s *Service) pendingKey() string        { return s.prefix() + "pending" }
+
+// Raise records an alert and immediately fans it out to admins and notifiers
+func (s *Service) Raise(ctx context.Context, alert Alert) (*Alert, error) {
+	if alert.UserID == "" {
+		return nil, errors.New(common.ErrMsgUserIDRequired)
+	}
+
+	alert.ID = uuid.NewString()
+	alert.Status = StatusOpen
+	alert.RaisedAt = time.Now().UTC()
+	alert.EscalationLevel = 0
+
+	if alert.Location == nil && s.locate != nil {
+		if loc, err := s.locate(ctx, alert.UserID, alert.UserType); err == nil {
+			alert.Location = loc
+		} else {
+			log.Printf("Emergency %s: failed to snapshot location: %v", alert.ID, err)
+		}
+	}
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, s.pendingKey(), redis.Z{
+			Score:  float64(alert.RaisedAt.Add(s.config.AckTimeout).UnixMilli()),
+			Member: alert.ID,
+		})
+		return s.save(ctx, &alert, pipe)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record alert %s: %w", alert.ID, err)
+	}
+
+	log.Printf("🚨 %s", alert.Summary())
+	s.broadcast(&alert)
+	s.publish(ctx, common.EventTypeEmergencyRaised, &alert)
+	s.notify(ctx, s.notifiers, &alert)
+	return &alert, nil
+}
+
+// Acknowledge marks an open alert as handled by an admin and stops escalation
+func (s *Service) Acknowledge(ctx context.Context, alertID, adminID string) (*Alert, error) {
+	return s.transition(ctx, alertID, common.EventTypeEmergencyAcknowledged, func(tx *redis.Tx, alert *Alert) error {
+		if alert.Status != StatusOpen {
+			return fmt.Errorf("%w: %s is %s", ErrInvalidTransition, alert.ID, alert.Status)
+		}
+		now := time.Now().UTC()
+		alert.Status = StatusAcknowledged
+		alert.AcknowledgedBy = adminID
+		alert.AcknowledgedAt = &now
+		return nil
+	})
+}
+
+// Resolve closes an open or acknowledged alert; resolved is terminal
+func (s *Service) Resolve(ctx context.Context, alertID string) (*Alert, error) {
+	return s.transition(ctx, alertID, common.EventTypeEmergencyResolved, func(tx *redis.Tx, alert *Alert) error {
+		if alert.Status == StatusResolved {
+			return fmt.Errorf("%w: %s is already resolved", ErrInvalidTransition, alert.ID)
+		}
+		now := time.Now().UTC()
+		alert.Status = StatusResolved
+		alert.ResolvedAt = &now
+		return nil
+	})
+}
+
+func (s *Service) transition(ctx context.Context, alertID, eventType string, mutate func(*redis.Tx, *Alert) error) (*Alert, error) {
+	alert, err := s.update(ctx, alertID, mutate, func(pipe redis.Pipeliner, alert *Alert) error {
+		pipe.ZRem(ctx, s.pendingKey(), alertID)
+		return s.save(ctx, alert, pipe)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.broadcast(alert)
+	s.publish(ctx, eventType, alert)
+	return alert, nil
+}
+
+// update changes an alert atomically: the alert key is watched while mutate
+// checks and changes the loaded alert, and write queues the changes in a
+// transaction. When another writer changes the alert first the transaction
+// fails and mutate runs again on the fresh state, so a status check in
+// mutate acts as a compare-and-set. Every writer saves the alert, so the
+// pending set needs no watch of its own.
+func (s *Service) update(ctx context.Context, alertID string, mutate func(*redis.Tx, *Alert) error, write func(redis.Pipeliner, *Alert) error) (*Alert, error) {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		var alert *Alert
+		err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+			current, err := s.load(ctx, tx, alertID)
+			if err != nil {
+				return err
+			}
+			if err := mutate(tx, current); err != nil {
+				return err
+			}
+			alert = current
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				return write(pipe, current)
+			})
+			return err
+		}, s.alertKey(alertID))
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			if errors.Is(err, ErrAlertNotFound) || errors.Is(err, ErrInvalidTransition) || errors.Is(err, errAlreadyClaimed) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to update alert %s: %w", alertID, err)
+		}
+		return alert, nil
+	}
+	return nil, fmt.Errorf("failed to update alert %s: too much contention", alertID)
+}
+
+// Get returns an alert by ID
+func (s *Service) Get(ctx context.Context, alertID string) (*Alert, error) {
+	return s.load(ctx, s.client, alertID)
+}
+
+func (s *Service) load(ctx context.Context, cmd redis.Cmdable, alertID string) (*Alert, error) {
+	data, err := cmd.Get(ctx, s.alertKey(alertID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrAlertNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert %s: %w", alertID, err)
+	}
+
+	var alert Alert
+	if err := json.Unmarshal(data, &alert); err != nil {
+		return nil, fmt.Errorf("failed to decode alert %s: %w", alertID, err)
+	}
+	return &alert, nil
+}
+
+// Pending returns the IDs of unacknowledged alerts
+func (s *Service) Pending(ctx context.Context) ([]string, error) {
+	return s.client.ZRange(ctx, s.pendingKey(), 0, -1).Result()
+}
+
+// RunEscalator escalates unacknowledged alerts until ctx is cancelled.
+// Run it on every instance; claims are atomic so each escalation fires once.
+func (s *Service) RunEscalator(ctx context.Context, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.escalateDue(ctx)
+		}
+	}
+}
+
+func (s *Service) escalateDue(ctx context.Context) {
+	due, err := s.client.ZRangeByScore(ctx, s.pendingKey(), &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		log.Printf("Emergency escalator: failed to load due alerts: %v", err)
+		return
+	}
+
+	for _, alertID := range due {
+		// The claim, the escalation and the reschedule commit together, so a
+		// failed update leaves the alert pending for the next poll. Another
+		// instance escalating first moves the due time, which the re-check
+		// after its conflicting write sees.
+		now := time.Now()
+		alert, err := s.update(ctx, alertID, func(tx *redis.Tx, alert *Alert) error {
+			due, err := tx.ZScore(ctx, s.pendingKey(), alertID).Result()
+			if errors.Is(err, redis.Nil) || (err == nil && due > float64(now.UnixMilli())) {
+				return errAlreadyClaimed
+			}
+			if err != nil {
+				return err
+			}
+			if alert.Status != StatusOpen {
+				return ErrInvalidTransition
+			}
+			alert.EscalationLevel++
+			return nil
+		}, func(pipe redis.Pipeliner, alert *Alert) error {
+			if alert.EscalationLevel < s.config.MaxEscalations {
+				pipe.ZAdd(ctx, s.pendingKey(), redis.Z{
+					Score:  float64(now.Add(s.config.AckTimeout).UnixMilli()),
+					Member: alert.ID,
+				})
+			} else {
+				pipe.ZRem(ctx, s.pendingKey(), alert.ID)
+			}
+			return s.save(ctx, alert, pipe)
+		})
+		switch {
+		case errors.Is(err, errAlreadyClaimed):
+			continue
+		case errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrAlertNotFound):
+			// no longer escalatable; stop polling it
+			s.client.ZRem(ctx, s.pendingKey(), alertID)
+			continue
+		case err != nil:
+			log.Printf("Emergency escalator: %v", err)
+			continue
+		}
+
+		log.Printf("🚨 Escalating unacknowledged alert: %s", alert.Summary())
+		s.broadcast(alert)
+		s.publish(ctx, common.EventTypeEmergencyEscalated, alert)
+		s.notify(ctx, s.escalationNotifiers, alert)
+	}
+}
+
+func (s *Service) save(ctx context.Context, alert *Alert, cmd redis.Cmdable) error {
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	if err := cmd.Set(ctx, s.alertKey(alert.ID), data, s.config.Retention).Err(); err != nil {
+		return fmt.Errorf("failed to store alert %s: %w", alert.ID, err)
+	}
+	return nil
+}
+
+// broadcast pushes the alert to every connected admin
+func (s *Service) broadcast(alert *Alert) {
+	if s.ws == nil {
+		return
+	}
+	s.ws.BroadcastToType(websocket.UserTypeAdmin, websocket.NewPayloadMessage(websocket.EmergencyAlertPayload{
+		Alert: alert.toWebSocket(),
+	}))
+}
+
+func (s *Service) publish(ctx context.Context, eventType string, alert *Alert) {
+	if s.publisher == nil {
+		return
+	}
+	event, err := events.NewEvent(eventType, common.AggregateTypeEmergency, alert.ID, alert)
+	if err != nil {
+		log.Printf("Emergency %s: %v", alert.ID, err)
+		return
+	}
+	event.Metadata[events.MetadataPriority] = events.PriorityHigh
+	if err := s.publisher.Publish(ctx, s.config.Topic, event); err != nil {
+		log.Printf("Emergency %s: %v", alert.ID, err)
+	}
+}
+
+// notify calls all notifiers concurrently and waits for them
+func (s *Service) notify(ctx context.Context, notifiers []Notifier, alert *Alert) {
+	var wg sync.WaitGroup
+	for _, n := range notifiers {
+		wg.Add(1)
+		go func(n Notifier) {
+			defer wg.Done()
+			if err := n.Notify(ctx, alert); err != nil {
+				log.Printf("Emergency %s: notifier failed: %v", alert.ID, err)
+			}
+		}(n)
+	}
+	wg.Wait()
+}
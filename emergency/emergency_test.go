@@ -0,0 +1,142 @@
+package emergency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestService(t *testing.T, config Config) (*Service, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	if config.AckTimeout == 0 {
+		config.AckTimeout = time.Millisecond
+	}
+	return NewService(client, nil, nil, nil, config), mr
+}
+
+func raise(t *testing.T, s *Service) *Alert {
+	t.Helper()
+	alert, err := s.Raise(context.Background(), Alert{UserID: "rider-1", UserType: "rider"})
+	if err != nil {
+		t.Fatalf("Raise: %v", err)
+	}
+	return alert
+}
+
+func pending(t *testing.T, s *Service) []string {
+	t.Helper()
+	ids, err := s.Pending(context.Background())
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	return ids
+}
+
+func TestKeysShareHashSlot(t *testing.T) {
+	s, _ := newTestService(t, Config{})
+	if got := s.alertKey("a"); got != "{emergency}:alert:a" {
+		t.Errorf("alertKey = %q", got)
+	}
+	if got := s.pendingKey(); got != "{emergency}:pending" {
+		t.Errorf("pendingKey = %q", got)
+	}
+}
+
+func TestEscalateReschedulesOnce(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService(t, Config{AckTimeout: 20 * time.Millisecond})
+	alert := raise(t, s)
+
+	time.Sleep(30 * time.Millisecond)
+	s.escalateDue(ctx)
+	s.escalateDue(ctx) // not due again yet
+
+	got, err := s.Get(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.EscalationLevel != 1 {
+		t.Errorf("EscalationLevel = %d, want 1", got.EscalationLevel)
+	}
+	if ids := pending(t, s); len(ids) != 1 {
+		t.Errorf("pending = %v, want the alert rescheduled", ids)
+	}
+}
+
+func TestEscalationStopsAtMax(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService(t, Config{MaxEscalations: 1})
+	alert := raise(t, s)
+
+	time.Sleep(5 * time.Millisecond)
+	s.escalateDue(ctx)
+
+	got, _ := s.Get(ctx, alert.ID)
+	if got.EscalationLevel != 1 {
+		t.Errorf("EscalationLevel = %d, want 1", got.EscalationLevel)
+	}
+	if ids := pending(t, s); len(ids) != 0 {
+		t.Errorf("pending = %v, want empty after the last escalation", ids)
+	}
+}
+
+func TestEscalationFailureKeepsAlertPending(t *testing.T) {
+	ctx := context.Background()
+	s, mr := newTestService(t, Config{})
+	alert := raise(t, s)
+
+	// a corrupt record makes the update fail after the alert is due
+	mr.Set(s.alertKey(alert.ID), "not json")
+	time.Sleep(5 * time.Millisecond)
+	s.escalateDue(ctx)
+
+	if ids := pending(t, s); len(ids) != 1 || ids[0] != alert.ID {
+		t.Fatalf("pending = %v, want %s kept for retry", ids, alert.ID)
+	}
+}
+
+func TestAcknowledgeStopsEscalation(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService(t, Config{})
+	alert := raise(t, s)
+
+	if _, err := s.Acknowledge(ctx, alert.ID, "admin-1"); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	s.escalateDue(ctx)
+
+	got, _ := s.Get(ctx, alert.ID)
+	if got.Status != StatusAcknowledged || got.EscalationLevel != 0 {
+		t.Errorf("alert = %s level %d, want acknowledged level 0", got.Status, got.EscalationLevel)
+	}
+	if ids := pending(t, s); len(ids) != 0 {
+		t.Errorf("pending = %v, want empty", ids)
+	}
+}
+
+func TestResolvedAlertRejectsTransitions(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService(t, Config{})
+	alert := raise(t, s)
+
+	if _, err := s.Resolve(ctx, alert.ID); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if _, err := s.Acknowledge(ctx, alert.ID, "admin-1"); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Acknowledge after resolve: err = %v, want ErrInvalidTransition", err)
+	}
+	if _, err := s.Resolve(ctx, alert.ID); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Resolve twice: err = %v, want ErrInvalidTransition", err)
+	}
+	if _, err := s.Acknowledge(ctx, "missing", "admin-1"); !errors.Is(err, ErrAlertNotFound) {
+		t.Errorf("Acknowledge unknown: err = %v, want ErrAlertNotFound", err)
+	}
+}
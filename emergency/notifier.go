@@ -0,0 +1,89 @@
+package emergency
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Notifier forwards an alert to an external channel
+type Notifier interface {
+	Notify(ctx context.Context, alert *Alert) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(ctx context.Context, alert *Alert) error
+
+// Notify calls f(ctx, alert)
+func (f NotifierFunc) Notify(ctx context.Context, alert *Alert) error {
+	return f(ctx, alert)
+}
+
+// WebhookNotifier posts alerts as JSON to a URL
+type WebhookNotifier struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier(url string, headers map[string]string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:     url,
+		Headers: headers,
+		Client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Notify posts the alert to the webhook
+func (n *WebhookNotifier) Notify(ctx context.Context, alert *Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call emergency webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("emergency webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SMSSender sends a text message; implemented by the SMS gateway integration
+type SMSSender interface {
+	SendSMS(ctx context.Context, to, body string) error
+}
+
+// SMSNotifier texts an alert summary to a list of phone numbers
+type SMSNotifier struct {
+	Sender     SMSSender
+	Recipients []string
+}
+
+// Notify sends the alert summary to every recipient, returning the last error
+func (n *SMSNotifier) Notify(ctx context.Context, alert *Alert) error {
+	var lastErr error
+	for _, to := range n.Recipients {
+		if err := n.Sender.SendSMS(ctx, to, alert.Summary()); err != nil {
+			lastErr = fmt.Errorf("failed to send emergency SMS to %s: %w", to, err)
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,114 @@
+package enterprise
+
+import (
+	"errors"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+)
+
+// Organization statuses
+const (
+	OrganizationActive    = "active"
+	OrganizationSuspended = "suspended"
+)
+
+// Member roles
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// Spend check errors
+var (
+	ErrOrganizationSuspended = errors.New("organization account is suspended")
+	ErrNotEnterprise         = errors.New("organization is not on the enterprise tier")
+	ErrMemberInactive        = errors.New("member is not active in the organization")
+	ErrTripLimitExceeded     = errors.New("fare exceeds the per-trip limit")
+	ErrMemberLimitExceeded   = errors.New("member monthly spend limit exceeded")
+	ErrOrgLimitExceeded      = errors.New("organization monthly spend limit exceeded")
+)
+
+// Organization is a corporate account billed centrally for its members' trips
+type Organization struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Tier         string `json:"tier"`
+	Status       string `json:"status"`
+	BillingEmail string `json:"billingEmail"`
+	Currency     string `json:"currency"`
+	// MonthlySpendLimit caps total spend per calendar month; 0 means unlimited
+	MonthlySpendLimit float64 `json:"monthlySpendLimit,omitempty"`
+	// PerTripLimit caps a single fare; 0 means unlimited
+	PerTripLimit float64 `json:"perTripLimit,omitempty"`
+	// MemberMonthlyLimit is the default per-member cap; 0 means unlimited
+	MemberMonthlyLimit float64   `json:"memberMonthlyLimit,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
+// IsEnterprise reports whether the organization is on the enterprise tier
+func (o Organization) IsEnterprise() bool {
+	return o.Tier == common.SubscriptionTierEnterprise
+}
+
+// Member links a rider to an organization
+type Member struct {
+	OrganizationID string `json:"organizationId"`
+	UserID         string `json:"userId"`
+	Role           string `json:"role"`
+	CostCenter     string `json:"costCenter,omitempty"`
+	Active         bool   `json:"active"`
+	// MonthlySpendLimit overrides the organization default; 0 uses the default
+	MonthlySpendLimit float64   `json:"monthlySpendLimit,omitempty"`
+	JoinedAt          time.Time `json:"joinedAt"`
+}
+
+// MonthlyLimit returns the member's effective monthly cap
+func (m Member) MonthlyLimit(org Organization) float64 {
+	if m.MonthlySpendLimit > 0 {
+		return m.MonthlySpendLimit
+	}
+	return org.MemberMonthlyLimit
+}
+
+// TripCharge is a completed trip billed to an organization
+type TripCharge struct {
+	TripID         string    `json:"tripId"`
+	OrganizationID string    `json:"organizationId"`
+	UserID         string    `json:"userId"`
+	CostCenter     string    `json:"costCenter,omitempty"`
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency"`
+	DistanceKm     float64   `json:"distanceKm,omitempty"`
+	CompletedAt    time.Time `json:"completedAt"`
+}
+
+// Spend is the amount already charged this month
+type Spend struct {
+	Organization float64 `json:"organization"`
+	Member       float64 `json:"member"`
+}
+
+// CheckSpend decides whether a member may request a trip with the estimated
+// fare, given what the organization and member have spent this month
+func CheckSpend(org Organization, member Member, spent Spend, estimatedFare float64) error {
+	if !org.IsEnterprise() {
+		return ErrNotEnterprise
+	}
+	if org.Status != OrganizationActive {
+		return ErrOrganizationSuspended
+	}
+	if !member.Active || member.OrganizationID != org.ID {
+		return ErrMemberInactive
+	}
+	if org.PerTripLimit > 0 && estimatedFare > org.PerTripLimit {
+		return ErrTripLimitExceeded
+	}
+	if limit := member.MonthlyLimit(org); limit > 0 && spent.Member+estimatedFare > limit {
+		return ErrMemberLimitExceeded
+	}
+	if org.MonthlySpendLimit > 0 && spent.Organization+estimatedFare > org.MonthlySpendLimit {
+		return ErrOrgLimitExceeded
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package enterprise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/refdata"
+	"github.com/redis/go-redis/v9"
+)
+
+// SpendTracker keeps month-to-date spend per organization and member in Redis
+type SpendTracker struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	now       func() time.Time
+}
+
+// NewSpendTracker creates a new spend tracker
+func NewSpendTracker(client redis.UniversalClient, keyPrefix string) *SpendTracker {
+	if keyPrefix == "" {
+		keyPrefix = "enterprise:spend"
+	}
+	return &SpendTracker{
+		client:    client,
+		keyPrefix: keyPrefix,
+		now:       time.Now,
+	}
+}
+
+// period returns the billing month of t in local time, e.g. "202401"
+func period(t time.Time) string {
+	return refdata.InAddisAbaba(t).Format("200601")
+}
+
+// spendRetention keeps totals for a few months so statements can be reconciled
+const spendRetention = 100 * 24 * time.Hour
+
+// recordChargeScript dedups the trip and adds its amount in one step.
+// KEYS seen set, org total, member total; ARGV trip ID, amount, TTL (s)
+// Returns 1 when recorded, 0 for a duplicate
+var recordChargeScript = redis.NewScript(`
+if redis.call('SADD', KEYS[1], ARGV[1]) == 0 then
+  return 0
+end
+redis.call('INCRBYFLOAT', KEYS[2], ARGV[2])
+redis.call('INCRBYFLOAT', KEYS[3], ARGV[2])
+for i = 1, 3 do
+  redis.call('EXPIRE', KEYS[i], ARGV[3])
+end
+return 1
+`)
+
+// orgTag hash-tags every key of an organization into one cluster slot
+func (s *SpendTracker) orgTag(orgID string) string {
+	return "{" + s.keyPrefix + ":" + orgID + "}"
+}
+
+func (s *SpendTracker) orgKey(orgID, month string) string {
+	return fmt.Sprintf("%s:%s", s.orgTag(orgID), month)
+}
+
+func (s *SpendTracker) memberKey(orgID, userID, month string) string {
+	return fmt.Sprintf("%s:%s:%s", s.orgTag(orgID), month, userID)
+}
+
+func (s *SpendTracker) seenKey(orgID, month string) string {
+	return s.orgKey(orgID, month) + ":trips"
+}
+
+// Record adds a completed trip to the month-to-date totals. Charges are
+// deduplicated by trip ID so redelivered completion events are not billed twice.
+func (s *SpendTracker) Record(ctx context.Context, charge TripCharge) error {
+	month := period(charge.CompletedAt)
+	keys := []string{
+		s.seenKey(charge.OrganizationID, month),
+		s.orgKey(charge.OrganizationID, month),
+		s.memberKey(charge.OrganizationID, charge.UserID, month),
+	}
+
+	err := recordChargeScript.Run(ctx, s.client, keys,
+		charge.TripID, charge.Amount, int64(spendRetention.Seconds())).Err()
+	if err != nil {
+		return fmt.Errorf("failed to record charge for trip %s: %w", charge.TripID, err)
+	}
+	return nil
+}
+
+// Spent returns the month-to-date spend of an organization and one member
+func (s *SpendTracker) Spent(ctx context.Context, orgID, userID string) (Spend, error) {
+	month := period(s.now())
+	values, err := s.client.MGet(ctx, s.orgKey(orgID, month), s.memberKey(orgID, userID, month)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return Spend{}, fmt.Errorf("failed to read spend for organization %s: %w", orgID, err)
+	}
+
+	var spend Spend
+	if len(values) == 2 {
+		spend.Organization = parseAmount(values[0])
+		spend.Member = parseAmount(values[1])
+	}
+	return spend, nil
+}
+
+// Check evaluates CheckSpend against the current month-to-date spend
+func (s *SpendTracker) Check(ctx context.Context, org Organization, member Member, estimatedFare float64) error {
+	spent, err := s.Spent(ctx, org.ID, member.UserID)
+	if err != nil {
+		return err
+	}
+	return CheckSpend(org, member, spent, estimatedFare)
+}
+
+func parseAmount(v interface{}) float64 {
+	s, ok := v.(string)
+	if !ok {
+		return 0
+	}
+	amount, _ := strconv.ParseFloat(s, 64)
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,47 @@
+package enterprise
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRecordDeduplicatesTripsInOneSlot(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	completed := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker := NewSpendTracker(client, "")
+	tracker.now = func() time.Time { return completed }
+
+	charges := []TripCharge{
+		{TripID: "trip-1", OrganizationID: "org-1", UserID: "user-1", Amount: 120.5, CompletedAt: completed},
+		{TripID: "trip-1", OrganizationID: "org-1", UserID: "user-1", Amount: 120.5, CompletedAt: completed},
+		{TripID: "trip-2", OrganizationID: "org-1", UserID: "user-2", Amount: 80, CompletedAt: completed},
+	}
+	for _, charge := range charges {
+		if err := tracker.Record(ctx, charge); err != nil {
+			t.Fatalf("Record(%s): %v", charge.TripID, err)
+		}
+	}
+
+	spent, err := tracker.Spent(ctx, "org-1", "user-1")
+	if err != nil {
+		t.Fatalf("Spent: %v", err)
+	}
+	if spent.Organization != 200.5 || spent.Member != 120.5 {
+		t.Errorf("Spent = %+v, want organization 200.5 and member 120.5", spent)
+	}
+
+	for _, key := range mr.Keys() {
+		if !strings.HasPrefix(key, "{enterprise:spend:org-1}:") {
+			t.Errorf("key %q is not hash-tagged by organization", key)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package enterprise
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/refdata"
+)
+
+// StatementLine is one trip on a monthly statement
+type StatementLine struct {
+	TripID      string    `json:"tripId"`
+	UserID      string    `json:"userId"`
+	CostCenter  string    `json:"costCenter,omitempty"`
+	Amount      float64   `json:"amount"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// Subtotal aggregates spend for a member or cost center
+type Subtotal struct {
+	Key       string  `json:"key"`
+	TripCount int     `json:"tripCount"`
+	Amount    float64 `json:"amount"`
+}
+
+// Statement is the monthly bill of an organization
+type Statement struct {
+	OrganizationID   string          `json:"organizationId"`
+	OrganizationName string          `json:"organizationName"`
+	Year             int             `json:"year"`
+	Month            time.Month      `json:"month"`
+	Currency         string          `json:"currency"`
+	Lines            []StatementLine `json:"lines"`
+	ByMember         []Subtotal      `json:"byMember"`
+	ByCostCenter     []Subtotal      `json:"byCostCenter"`
+	TripCount        int             `json:"tripCount"`
+	Total            float64         `json:"total"`
+	GeneratedAt      time.Time       `json:"generatedAt"`
+}
+
+// BuildStatement aggregates the charges of one calendar month (local time)
+// into a statement; charges from other months or organizations are ignored
+func BuildStatement(org Organization, year int, month time.Month, charges []TripCharge) Statement {
+	st := Statement{
+		OrganizationID:   org.ID,
+		OrganizationName: org.Name,
+		Year:             year,
+		Month:            month,
+		Currency:         org.Currency,
+		GeneratedAt:      time.Now().UTC(),
+	}
+
+	members := make(map[string]*Subtotal)
+	costCenters := make(map[string]*Subtotal)
+	seen := make(map[string]bool)
+
+	for _, c := range charges {
+		local := refdata.InAddisAbaba(c.CompletedAt)
+		if c.OrganizationID != org.ID || local.Year() != year || local.Month() != month || seen[c.TripID] {
+			continue
+		}
+		seen[c.TripID] = true
+
+		st.Lines = append(st.Lines, StatementLine{
+			TripID:      c.TripID,
+			UserID:      c.UserID,
+			CostCenter:  c.CostCenter,
+			Amount:      c.Amount,
+			CompletedAt: c.CompletedAt,
+		})
+		st.Total += c.Amount
+		addSubtotal(members, c.UserID, c.Amount)
+		addSubtotal(costCenters, c.CostCenter, c.Amount)
+	}
+
+	sort.Slice(st.Lines, func(i, j int) bool { return st.Lines[i].CompletedAt.Before(st.Lines[j].CompletedAt) })
+	st.TripCount = len(st.Lines)
+	st.Total = round2(st.Total)
+	st.ByMember = sortedSubtotals(members)
+	st.ByCostCenter = sortedSubtotals(costCenters)
+	return st
+}
+
+func addSubtotal(m map[string]*Subtotal, key string, amount float64) {
+	s, ok := m[key]
+	if !ok {
+		s = &Subtotal{Key: key}
+		m[key] = s
+	}
+	s.TripCount++
+	s.Amount += amount
+}
+
+func sortedSubtotals(m map[string]*Subtotal) []Subtotal {
+	out := make([]Subtotal, 0, len(m))
+	for _, s := range m {
+		s.Amount = round2(s.Amount)
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Amount != out[j].Amount {
+			return out[i].Amount > out[j].Amount
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,104 @@
+package error
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AppError is a domain error carrying an ErrorCode
+type AppError struct {
+	Code    ErrorCode
+	Message string
+	// Service is the upstream service that produced the error, if any
+	Service string
+	Err     error
+}
+
+// New creates an AppError
+func New(code ErrorCode, message string) *AppError {
+	return &AppError{Code: code, Message: message}
+}
+
+// Wrap creates an AppError around a cause
+func Wrap(code ErrorCode, message string, err error) *AppError {
+	return &AppError{Code: code, Message: message, Err: err}
+}
+
+// Error implements error
+func (e *AppError) Error() string {
+	msg := e.Message
+	if e.Service != "" {
+		msg = e.Service + ": " + msg
+	}
+	if e.Err != nil && e.Err.Error() != e.Message {
+		return fmt.Sprintf("%s: %s: %v", e.CodeName(), msg, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.CodeName(), msg)
+}
+
+// Unwrap returns the cause
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// CodeName returns the string name of the error code
+func (e *AppError) CodeName() string {
+	if name, ok := ErrorCodeNames[e.Code]; ok {
+		return name
+	}
+	return ErrorCodeNames[ErrorCodeUnknown]
+}
+
+// As returns err as an AppError when it is or wraps one
+func As(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+var grpcCodes = map[codes.Code]ErrorCode{
+	codes.InvalidArgument:    ErrorCodeValidation,
+	codes.OutOfRange:         ErrorCodeValidation,
+	codes.FailedPrecondition: ErrorCodeValidation,
+	codes.NotFound:           ErrorCodeNotFound,
+	codes.Unauthenticated:    ErrorCodeUnauthorized,
+	codes.PermissionDenied:   ErrorCodeForbidden,
+	codes.AlreadyExists:      ErrorCodeConflict,
+	codes.Aborted:            ErrorCodeConflict,
+	codes.DeadlineExceeded:   ErrorCodeTimeout,
+	codes.ResourceExhausted:  ErrorCodeRateLimit,
+	codes.Unavailable:        ErrorCodeServiceUnavailable,
+	codes.Internal:           ErrorCodeInternal,
+	codes.DataLoss:           ErrorCodeInternal,
+	codes.Unimplemented:      ErrorCodeInternal,
+}
+
+// FromGRPC converts a gRPC status error from service into an AppError.
+// Existing AppErrors are returned unchanged.
+func FromGRPC(service string, err error) *AppError {
+	if err == nil {
+		return nil
+	}
+	if appErr, ok := As(err); ok {
+		return appErr
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &AppError{Code: ErrorCodeTimeout, Message: err.Error(), Service: service, Err: err}
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return &AppError{Code: ErrorCodeUnknown, Message: err.Error(), Service: service, Err: err}
+	}
+	code, ok := grpcCodes[st.Code()]
+	if !ok {
+		code = ErrorCodeUnknown
+	}
+	return &AppError{Code: code, Message: st.Message(), Service: service, Err: err}
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dapr/go-sdk/client"
+	"github.com/google/uuid"
+	common "github.com/mihirk-khode/motocabz-common"
+)
+
+// Event priorities carried in the "priority" metadata key
+const (
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// MetadataPriority is the metadata key holding the event priority
+const MetadataPriority = "priority"
+
+// BaseEvent is the envelope shared by all domain events
+type BaseEvent struct {
+	ID            string            `json:"id"`
+	Type          string            `json:"type"`
+	AggregateID   string            `json:"aggregateId"`
+	AggregateType string            `json:"aggregateType"`
+	Version       int               `json:"version"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Source        string            `json:"source,omitempty"`
+	Data          json.RawMessage   `json:"data,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// NewEvent creates an event with a generated ID and the given payload
+func NewEvent(eventType, aggregateType, aggregateID string, data interface{}) (BaseEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return BaseEvent{}, fmt.Errorf("failed to marshal %s event data: %w", eventType, err)
+	}
+
+	return BaseEvent{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		AggregateID:   aggregateID,
+		AggregateType: aggregateType,
+		Version:       1,
+		Timestamp:     time.Now().UTC(),
+		Data:          payload,
+		Metadata:      map[string]string{},
+	}, nil
+}
+
+// Decode unmarshals the event payload into v
+func (e BaseEvent) Decode(v interface{}) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// Priority returns the event priority, defaulting to normal
+func (e BaseEvent) Priority() string {
+	if p := e.Metadata[MetadataPriority]; p != "" {
+		return p
+	}
+	return PriorityNormal
+}
+
+// Publisher publishes events to a topic
+type Publisher interface {
+	Publish(ctx context.Context, topic string, event BaseEvent) error
+}
+
+// DaprPublisher publishes events through a Dapr pub/sub component
+type DaprPublisher struct {
+	client     client.Client
+	pubsubName string
+	source     string
+}
+
+// NewDaprPublisher creates a new Dapr publisher; source is stamped on every event
+func NewDaprPublisher(daprClient client.Client, pubsubName, source string) *DaprPublisher {
+	if pubsubName == "" {
+		pubsubName = common.DaprPubsubName
+	}
+	return &DaprPublisher{
+		client:     daprClient,
+		pubsubName: pubsubName,
+		source:     source,
+	}
+}
+
+// Publish sends event to topic, forwarding event metadata to the broker
+func (p *DaprPublisher) Publish(ctx context.Context, topic string, event BaseEvent) error {
+	if event.Source == "" {
+		event.Source = p.source
+	}
+
+	opts := []client.PublishEventOption{client.PublishEventWithContentType("application/json")}
+	if len(event.Metadata) > 0 {
+		opts = append(opts, client.PublishEventWithMetadata(event.Metadata))
+	}
+
+	if err := p.client.PublishEvent(ctx, p.pubsubName, topic, event, opts...); err != nil {
+		return fmt.Errorf("failed to publish %s event to %s: %w", event.Type, topic, err)
+	}
+	return nil
+}
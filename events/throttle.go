@@ -0,0 +1,161 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultThrottlePrefix namespaces the keys used by Throttler
+const DefaultThrottlePrefix = "events:throttle:"
+
+// FlushFunc receives the latest pending value of a throttled key
+type FlushFunc func(ctx context.Context, key string, value []byte) error
+
+// Throttler suppresses duplicate or high-frequency events across replicas using Redis
+type Throttler struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewThrottler creates a new Redis-backed throttler. The prefix is wrapped in
+// a {hash tag}, since ThrottleLatest updates a key's gate together with the
+// shared pending set; on Redis Cluster one throttler lives in a single slot.
+func NewThrottler(client redis.UniversalClient, prefix string) *Throttler {
+	if prefix == "" {
+		prefix = DefaultThrottlePrefix
+	}
+	return &Throttler{
+		client: client,
+		prefix: "{" + strings.TrimSuffix(prefix, ":") + "}:",
+	}
+}
+
+func (t *Throttler) gateKey(key string) string   { return t.prefix + "gate:" + key }
+func (t *Throttler) latestKey(key string) string { return t.prefix + "latest:" + key }
+func (t *Throttler) pendingKey() string          { return t.prefix + "pending" }
+
+// Debounce reports whether an event for key should be emitted. Only the first
+// event within window is let through; duplicates arriving before the window
+// expires are suppressed.
+func (t *Throttler) Debounce(ctx context.Context, key string, window time.Duration) (bool, error) {
+	ok, err := t.client.SetNX(ctx, t.gateKey(key), time.Now().UnixMilli(), window).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to debounce %s: %w", key, err)
+	}
+	return ok, nil
+}
+
+// throttleLatestScript opens the gate or stores value as the pending latest.
+// KEYS[1] gate, KEYS[2] latest, KEYS[3] pending set; ARGV interval (ms), value, now (ms), member
+var throttleLatestScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[3], 'NX', 'PX', ARGV[1]) then
+  redis.call('DEL', KEYS[2])
+  redis.call('ZREM', KEYS[3], ARGV[4])
+  return 1
+end
+redis.call('HSET', KEYS[2], 'v', ARGV[2], 'i', ARGV[1])
+redis.call('PEXPIRE', KEYS[2], ARGV[1] * 4)
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then ttl = 0 end
+redis.call('ZADD', KEYS[3], 'NX', tonumber(ARGV[3]) + ttl, ARGV[4])
+return 0
+`)
+
+// ThrottleLatest limits key to one event per interval while never losing the
+// most recent value. It returns true when the caller should forward value
+// right away; otherwise value replaces any pending one and is delivered by
+// RunFlusher once the interval elapses.
+func (t *Throttler) ThrottleLatest(ctx context.Context, key string, interval time.Duration, value []byte) (bool, error) {
+	res, err := throttleLatestScript.Run(ctx, t.client,
+		[]string{t.gateKey(key), t.latestKey(key), t.pendingKey()},
+		interval.Milliseconds(), value, time.Now().UnixMilli(), key).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to throttle %s: %w", key, err)
+	}
+	return res == 1, nil
+}
+
+// FlushDue delivers pending values whose interval has elapsed and returns the
+// number delivered. Claims are made with ZREM so concurrent flushers on other
+// replicas never deliver the same value twice.
+func (t *Throttler) FlushDue(ctx context.Context, batchSize int64, fn FlushFunc) (int, error) {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	now := time.Now().UnixMilli()
+	due, err := t.client.ZRangeByScore(ctx, t.pendingKey(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now, 10),
+		Count: batchSize,
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pending throttled events: %w", err)
+	}
+
+	delivered := 0
+	for _, key := range due {
+		claimed, err := t.client.ZRem(ctx, t.pendingKey(), key).Result()
+		if err != nil {
+			return delivered, fmt.Errorf("failed to claim throttled event %s: %w", key, err)
+		}
+		if claimed == 0 {
+			continue // another replica took it
+		}
+
+		var data *redis.MapStringStringCmd
+		if _, err := t.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			data = pipe.HGetAll(ctx, t.latestKey(key))
+			pipe.Del(ctx, t.latestKey(key))
+			return nil
+		}); err != nil {
+			return delivered, fmt.Errorf("failed to load latest value for %s: %w", key, err)
+		}
+
+		fields := data.Val()
+		value, ok := fields["v"]
+		if !ok {
+			continue
+		}
+
+		// Re-arm the gate so the next event within the interval is throttled again
+		if interval, err := strconv.ParseInt(fields["i"], 10, 64); err == nil && interval > 0 {
+			t.client.Set(ctx, t.gateKey(key), now, time.Duration(interval)*time.Millisecond)
+		}
+
+		if err := fn(ctx, key, []byte(value)); err != nil {
+			log.Printf("Failed to flush throttled event %s: %v", key, err)
+			continue
+		}
+		delivered++
+	}
+
+	return delivered, nil
+}
+
+// RunFlusher calls FlushDue every pollInterval until ctx is cancelled
+func (t *Throttler) RunFlusher(ctx context.Context, pollInterval time.Duration, fn FlushFunc) error {
+	if pollInterval <= 0 {
+		pollInterval = 100 * time.Millisecond
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := t.FlushDue(ctx, 0, fn); err != nil {
+				log.Printf("Throttle flusher error: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package fareconfig
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ClassFare is the pricing table for one vehicle class
+type ClassFare struct {
+	VehicleClass    string  `json:"vehicleClass"`
+	BaseFare        float64 `json:"baseFare"`
+	PerKm           float64 `json:"perKm"`
+	PerMinute       float64 `json:"perMinute"`
+	MinimumFare     float64 `json:"minimumFare"`
+	CancellationFee float64 `json:"cancellationFee"`
+	// PerStop is charged for each intermediate stop of a multi-stop trip
+	PerStop float64 `json:"perStop,omitempty"`
+}
+
+// FareTable holds the pricing tables of all vehicle classes at a given version
+type FareTable struct {
+	Version   int64                `json:"version"`
+	Currency  string               `json:"currency"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+	Classes   map[string]ClassFare `json:"classes"`
+}
+
+// ChangeEvent describes a fare table update
+type ChangeEvent struct {
+	Previous *FareTable `json:"previous,omitempty"`
+	Current  *FareTable `json:"current"`
+}
+
+// ErrUnknownVehicleClass is returned when a class has no fare configured
+var ErrUnknownVehicleClass = errors.New("no fare configured for vehicle class")
+
+// Class returns the fare for a vehicle class
+func (t *FareTable) Class(vehicleClass string) (ClassFare, error) {
+	if t == nil {
+		return ClassFare{}, fmt.Errorf("%w: %s", ErrUnknownVehicleClass, vehicleClass)
+	}
+	fare, ok := t.Classes[vehicleClass]
+	if !ok {
+		return ClassFare{}, fmt.Errorf("%w: %s", ErrUnknownVehicleClass, vehicleClass)
+	}
+	return fare, nil
+}
+
+// Validate checks that every class has non-negative prices
+func (t *FareTable) Validate() error {
+	if t == nil || len(t.Classes) == 0 {
+		return errors.New("fare table has no vehicle classes")
+	}
+	for class, fare := range t.Classes {
+		if fare.BaseFare < 0 || fare.PerKm < 0 || fare.PerMinute < 0 || fare.MinimumFare < 0 || fare.CancellationFee < 0 || fare.PerStop < 0 {
+			return fmt.Errorf("fare for vehicle class %s has negative prices", class)
+		}
+	}
+	return nil
+}
+
+// Calculate returns the fare for a trip of the given distance and duration,
+// never less than the minimum fare and rounded to two decimals
+func (f ClassFare) Calculate(distanceKm, durationMinutes float64) float64 {
+	fare := f.BaseFare + f.PerKm*distanceKm + f.PerMinute*durationMinutes
+	if fare < f.MinimumFare {
+		fare = f.MinimumFare
+	}
+	return math.Round(fare*100) / 100
+}
+
+// CalculateWithStops returns the fare of a multi-stop trip: the distance and
+// duration fare plus PerStop for each intermediate stop
+func (f ClassFare) CalculateWithStops(distanceKm, durationMinutes float64, stops int) float64 {
+	fare := f.Calculate(distanceKm, durationMinutes) + f.PerStop*float64(stops)
+	return math.Round(fare*100) / 100
+}
@@ -0,0 +1,119 @@
+package fareconfig
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/middleware"
+)
+
+// Manager keeps the latest fare table in memory and notifies subscribers of changes
+type Manager struct {
+	source Source
+
+	mu        sync.RWMutex
+	current   *FareTable
+	listeners []func(ChangeEvent)
+}
+
+// NewManager creates a new fare config manager
+func NewManager(source Source) *Manager {
+	return &Manager{
+		source: source,
+	}
+}
+
+// Start loads the initial table and applies updates until ctx is cancelled
+func (m *Manager) Start(ctx context.Context) error {
+	table, err := m.source.Load(ctx)
+	if err != nil {
+		return err
+	}
+	m.apply(table)
+
+	go func() {
+		for ctx.Err() == nil {
+			if err := m.source.Watch(ctx, m.apply); err != nil && ctx.Err() == nil {
+				log.Printf("Fare config watch stopped, retrying: %v", err)
+				time.Sleep(time.Second)
+			}
+		}
+	}()
+	return nil
+}
+
+// Reload fetches the table from the source immediately
+func (m *Manager) Reload(ctx context.Context) error {
+	table, err := m.source.Load(ctx)
+	if err != nil {
+		return err
+	}
+	m.apply(table)
+	return nil
+}
+
+// OnChange registers a callback for fare table updates
+func (m *Manager) OnChange(fn func(ChangeEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, fn)
+}
+
+// Current returns the active fare table
+func (m *Manager) Current() *FareTable {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.current
+}
+
+// Fare returns the active fare for a vehicle class
+func (m *Manager) Fare(vehicleClass string) (ClassFare, error) {
+	return m.Current().Class(vehicleClass)
+}
+
+// apply installs table unless it is older than the current version
+func (m *Manager) apply(table *FareTable) {
+	m.mu.Lock()
+	previous := m.current
+	if previous != nil && table.Version != 0 && table.Version <= previous.Version {
+		m.mu.Unlock()
+		return
+	}
+	m.current = table
+	listeners := append([]func(ChangeEvent){}, m.listeners...)
+	m.mu.Unlock()
+
+	log.Printf("Fare table updated to version %d", table.Version)
+	event := ChangeEvent{Previous: previous, Current: table}
+	for _, fn := range listeners {
+		fn(event)
+	}
+}
+
+// Handler serves the active fare table to apps. Responses carry the version as
+// an ETag so clients can revalidate cheaply, and may be cached for maxAge.
+func (m *Manager) Handler(maxAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		table := m.Current()
+		if table == nil {
+			middleware.WriteJSON(w, http.StatusServiceUnavailable,
+				common.RsErr(http.StatusServiceUnavailable, "fare configuration not loaded", nil))
+			return
+		}
+
+		etag := `"` + strconv.FormatInt(table.Version, 10) + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		middleware.WriteJSON(w, http.StatusOK, common.RsOK(table, "fare configuration"))
+	})
+}
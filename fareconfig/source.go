@@ -0,0 +1,212 @@
+package fareconfig
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/dapr/go-sdk/client"
+	"github.com/redis/go-redis/v9"
+)
+
+// Default keys used to store the fare table
+const (
+	DefaultConfigKey    = "fareconfig"
+	DefaultRedisKey     = "fareconfig:table"
+	DefaultRedisChannel = "fareconfig:updated"
+)
+
+// maxPublishAttempts bounds the retries of Publish under concurrent writers
+const maxPublishAttempts = 5
+
+// Source loads the fare table and notifies about updates
+type Source interface {
+	Load(ctx context.Context) (*FareTable, error)
+	// Watch calls onChange for every update until ctx is cancelled
+	Watch(ctx context.Context, onChange func(*FareTable)) error
+}
+
+// RedisSource reads the fare table from a Redis key and watches a pub/sub channel
+type RedisSource struct {
+	client  redis.UniversalClient
+	key     string
+	channel string
+}
+
+// NewRedisSource creates a new Redis fare table source
+func NewRedisSource(client redis.UniversalClient, key, channel string) *RedisSource {
+	if key == "" {
+		key = DefaultRedisKey
+	}
+	if channel == "" {
+		channel = DefaultRedisChannel
+	}
+	return &RedisSource{
+		client:  client,
+		key:     key,
+		channel: channel,
+	}
+}
+
+// Load reads the current fare table
+func (s *RedisSource) Load(ctx context.Context) (*FareTable, error) {
+	data, err := s.client.Get(ctx, s.key).Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fare table from redis: %w", err)
+	}
+	return decodeTable(data, 0)
+}
+
+// Watch reloads the table whenever an update is published on the channel
+func (s *RedisSource) Watch(ctx context.Context, onChange func(*FareTable)) error {
+	sub := s.client.Subscribe(ctx, s.channel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-ch:
+			if !ok {
+				return errors.New("fare config subscription closed")
+			}
+			table, err := s.Load(ctx)
+			if err != nil {
+				log.Printf("Failed to reload fare table: %v", err)
+				continue
+			}
+			onChange(table)
+		}
+	}
+}
+
+// Publish stores table under the next version and notifies watchers. The
+// version is derived from the stored table inside a WATCH on the single
+// table key, so concurrent publishers cannot overwrite a newer table and the
+// update works on Redis Cluster.
+func (s *RedisSource) Publish(ctx context.Context, table *FareTable) (*FareTable, error) {
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < maxPublishAttempts; attempt++ {
+		var updated FareTable
+		err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+			var current FareTable
+			data, err := tx.Get(ctx, s.key).Bytes()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				return err
+			}
+			if err == nil {
+				if err := json.Unmarshal(data, &current); err != nil {
+					return fmt.Errorf("failed to decode stored fare table: %w", err)
+				}
+			}
+
+			updated = *table
+			updated.Version = current.Version + 1
+			updated.UpdatedAt = time.Now().UTC()
+			data, err = json.Marshal(updated)
+			if err != nil {
+				return fmt.Errorf("failed to marshal fare table: %w", err)
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, s.key, data, 0)
+				return nil
+			})
+			return err
+		}, s.key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to publish fare table: %w", err)
+		}
+
+		if err := s.client.Publish(ctx, s.channel, updated.Version).Err(); err != nil {
+			return nil, fmt.Errorf("failed to notify fare table update: %w", err)
+		}
+		return &updated, nil
+	}
+	return nil, errors.New("failed to publish fare table: too much contention")
+}
+
+// DaprSource reads the fare table from a Dapr configuration store
+type DaprSource struct {
+	client    client.Client
+	storeName string
+	key       string
+}
+
+// NewDaprSource creates a new Dapr configuration store source
+func NewDaprSource(daprClient client.Client, storeName, key string) *DaprSource {
+	if key == "" {
+		key = DefaultConfigKey
+	}
+	return &DaprSource{
+		client:    daprClient,
+		storeName: storeName,
+		key:       key,
+	}
+}
+
+// Load reads the current fare table
+func (s *DaprSource) Load(ctx context.Context) (*FareTable, error) {
+	item, err := s.client.GetConfigurationItem(ctx, s.storeName, s.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fare table from %s: %w", s.storeName, err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("fare table %s not found in %s", s.key, s.storeName)
+	}
+	return decodeItem(item)
+}
+
+// Watch subscribes to configuration updates of the fare table key
+func (s *DaprSource) Watch(ctx context.Context, onChange func(*FareTable)) error {
+	_, err := s.client.SubscribeConfigurationItems(ctx, s.storeName, []string{s.key},
+		func(id string, items map[string]*client.ConfigurationItem) {
+			item, ok := items[s.key]
+			if !ok {
+				return
+			}
+			table, err := decodeItem(item)
+			if err != nil {
+				log.Printf("Failed to decode fare table update: %v", err)
+				return
+			}
+			onChange(table)
+		})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to fare table updates: %w", err)
+	}
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// decodeItem decodes a configuration item, using the store version when the
+// payload does not carry one
+func decodeItem(item *client.ConfigurationItem) (*FareTable, error) {
+	version, _ := strconv.ParseInt(item.Version, 10, 64)
+	return decodeTable([]byte(item.Value), version)
+}
+
+func decodeTable(data []byte, fallbackVersion int64) (*FareTable, error) {
+	var table FareTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to decode fare table: %w", err)
+	}
+	if table.Version == 0 {
+		table.Version = fallbackVersion
+	}
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
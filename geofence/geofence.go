@@ -0,0 +1,107 @@
+package geofence
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/mihirk-khode/motocabz-common/location"
+)
+
+// Zone types
+const (
+	ZoneTypeAirportQueue = "airport_queue"
+	ZoneTypeSurge        = "surge"
+	ZoneTypeRestricted   = "restricted"
+	ZoneTypeService      = "service"
+)
+
+// Zone errors
+var (
+	ErrInvalidZone  = errors.New("geofence: invalid zone")
+	ErrZoneNotFound = errors.New("geofence: zone not found")
+)
+
+// Circle is a circular zone shape
+type Circle struct {
+	Center   location.Location `json:"center"`
+	RadiusKm float64           `json:"radiusKm"`
+}
+
+// Zone is a named area such as an airport queue, surge zone or restricted area.
+// Exactly one of Circle or Polygon is set; polygon vertices are in order and
+// the ring is closed implicitly.
+type Zone struct {
+	ID       string              `json:"id"`
+	Name     string              `json:"name"`
+	Type     string              `json:"type"`
+	Circle   *Circle             `json:"circle,omitempty"`
+	Polygon  []location.Location `json:"polygon,omitempty"`
+	Metadata map[string]string   `json:"metadata,omitempty"`
+}
+
+// NewCircleZone creates a circular zone
+func NewCircleZone(id, name, zoneType string, center location.Location, radiusKm float64) Zone {
+	return Zone{ID: id, Name: name, Type: zoneType, Circle: &Circle{Center: center, RadiusKm: radiusKm}}
+}
+
+// NewPolygonZone creates a polygon zone
+func NewPolygonZone(id, name, zoneType string, vertices []location.Location) Zone {
+	return Zone{ID: id, Name: name, Type: zoneType, Polygon: vertices}
+}
+
+// Validate checks that the zone has an ID and exactly one valid shape
+func (z Zone) Validate() error {
+	if z.ID == "" {
+		return fmt.Errorf("%w: ID is required", ErrInvalidZone)
+	}
+	switch {
+	case z.Circle != nil && len(z.Polygon) > 0:
+		return fmt.Errorf("%w: %s has both a circle and a polygon", ErrInvalidZone, z.ID)
+	case z.Circle != nil:
+		if !z.Circle.Center.IsValid() || z.Circle.RadiusKm <= 0 {
+			return fmt.Errorf("%w: %s needs a valid center and a positive radius", ErrInvalidZone, z.ID)
+		}
+	case len(z.Polygon) >= 3:
+		for _, v := range z.Polygon {
+			if !v.IsValid() {
+				return fmt.Errorf("%w: %s has invalid vertex %s", ErrInvalidZone, z.ID, v)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s needs a circle or a polygon of at least 3 vertices", ErrInvalidZone, z.ID)
+	}
+	return nil
+}
+
+// Contains reports whether loc lies inside the zone
+func (z Zone) Contains(loc location.Location) bool {
+	if z.Circle != nil {
+		return z.Circle.Center.DistanceKm(loc) <= z.Circle.RadiusKm
+	}
+	return PointInPolygon(loc, z.Polygon)
+}
+
+// Bounds returns the rectangle enclosing the zone
+func (z Zone) Bounds() location.Bounds {
+	if z.Circle != nil {
+		return location.BoundsAround(z.Circle.Center, z.Circle.RadiusKm)
+	}
+	b := location.Bounds{
+		SouthWest: location.Location{Latitude: math.Inf(1), Longitude: math.Inf(1)},
+		NorthEast: location.Location{Latitude: math.Inf(-1), Longitude: math.Inf(-1)},
+	}
+	for _, v := range z.Polygon {
+		b.SouthWest.Latitude = math.Min(b.SouthWest.Latitude, v.Latitude)
+		b.SouthWest.Longitude = math.Min(b.SouthWest.Longitude, v.Longitude)
+		b.NorthEast.Latitude = math.Max(b.NorthEast.Latitude, v.Latitude)
+		b.NorthEast.Longitude = math.Max(b.NorthEast.Longitude, v.Longitude)
+	}
+	return b
+}
+
+// PointInPolygon reports whether loc lies inside the polygon; see
+// location.RingContains
+func PointInPolygon(loc location.Location, polygon []location.Location) bool {
+	return location.RingContains(polygon, loc)
+}
@@ -0,0 +1,247 @@
+package geofence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/location"
+	"github.com/redis/go-redis/v9"
+)
+
+// Config configures a Store
+type Config struct {
+	KeyPrefix string
+	// RefreshInterval is how long zones are cached in-process before being
+	// reloaded from Redis
+	RefreshInterval time.Duration
+}
+
+// Transition lists the zones a driver entered and exited in one update
+type Transition struct {
+	Entered []string `json:"entered,omitempty"`
+	Exited  []string `json:"exited,omitempty"`
+}
+
+// Store keeps zones in Redis and indexes which drivers are inside each zone
+type Store struct {
+	client redis.UniversalClient
+	config Config
+
+	mu       sync.RWMutex
+	zones    []Zone
+	loadedAt time.Time
+}
+
+// NewStore creates a Redis-backed zone store
+func NewStore(client redis.UniversalClient, config Config) *Store {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "geofence:"
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = 30 * time.Second
+	}
+	return &Store{
+		client: client,
+		config: config,
+	}
+}
+
+func (s *Store) zonesKey() string {
+	return s.config.KeyPrefix + "zones"
+}
+
+func (s *Store) zoneDriversKey(zoneID string) string {
+	return s.config.KeyPrefix + "zone:" + zoneID + ":drivers"
+}
+
+func (s *Store) driverZonesKey(driverID string) string {
+	return s.config.KeyPrefix + "driver:" + driverID + ":zones"
+}
+
+// SaveZone creates or replaces a zone
+func (s *Store) SaveZone(ctx context.Context, zone Zone) error {
+	if err := zone.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(zone)
+	if err != nil {
+		return fmt.Errorf("failed to encode zone %s: %w", zone.ID, err)
+	}
+	if err := s.client.HSet(ctx, s.zonesKey(), zone.ID, data).Err(); err != nil {
+		return fmt.Errorf("failed to save zone %s: %w", zone.ID, err)
+	}
+	s.invalidate()
+	return nil
+}
+
+// DeleteZone removes a zone and its driver index
+func (s *Store) DeleteZone(ctx context.Context, zoneID string) error {
+	drivers, err := s.client.SMembers(ctx, s.zoneDriversKey(zoneID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete zone %s: %w", zoneID, err)
+	}
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, s.zonesKey(), zoneID)
+		pipe.Del(ctx, s.zoneDriversKey(zoneID))
+		for _, driverID := range drivers {
+			pipe.SRem(ctx, s.driverZonesKey(driverID), zoneID)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete zone %s: %w", zoneID, err)
+	}
+	s.invalidate()
+	return nil
+}
+
+// GetZone returns a zone by ID or ErrZoneNotFound
+func (s *Store) GetZone(ctx context.Context, zoneID string) (Zone, error) {
+	zones, err := s.Zones(ctx)
+	if err != nil {
+		return Zone{}, err
+	}
+	for _, zone := range zones {
+		if zone.ID == zoneID {
+			return zone, nil
+		}
+	}
+	return Zone{}, ErrZoneNotFound
+}
+
+func (s *Store) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// Zones returns every zone sorted by ID, from the in-process cache when fresh
+func (s *Store) Zones(ctx context.Context) ([]Zone, error) {
+	s.mu.RLock()
+	if time.Since(s.loadedAt) < s.config.RefreshInterval {
+		zones := s.zones
+		s.mu.RUnlock()
+		return zones, nil
+	}
+	s.mu.RUnlock()
+
+	raw, err := s.client.HGetAll(ctx, s.zonesKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load zones: %w", err)
+	}
+	zones := make([]Zone, 0, len(raw))
+	for id, data := range raw {
+		var zone Zone
+		if err := json.Unmarshal([]byte(data), &zone); err != nil {
+			log.Printf("⚠️ Skipping malformed zone %s: %v", id, err)
+			continue
+		}
+		zones = append(zones, zone)
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].ID < zones[j].ID })
+
+	s.mu.Lock()
+	s.zones, s.loadedAt = zones, time.Now()
+	s.mu.Unlock()
+	return zones, nil
+}
+
+// ZonesContaining returns the zones loc lies in, e.g. to find which zone a
+// driver or rider is in
+func (s *Store) ZonesContaining(ctx context.Context, loc location.Location) ([]Zone, error) {
+	zones, err := s.Zones(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matches []Zone
+	for _, zone := range zones {
+		if zone.Bounds().Contains(loc) && zone.Contains(loc) {
+			matches = append(matches, zone)
+		}
+	}
+	return matches, nil
+}
+
+// UpdateDriver re-indexes a driver at loc and reports the zones it entered
+// and exited since its previous update
+func (s *Store) UpdateDriver(ctx context.Context, driverID string, loc location.Location) (Transition, error) {
+	zones, err := s.ZonesContaining(ctx, loc)
+	if err != nil {
+		return Transition{}, err
+	}
+	current := make(map[string]bool, len(zones))
+	for _, zone := range zones {
+		current[zone.ID] = true
+	}
+
+	previous, err := s.client.SMembers(ctx, s.driverZonesKey(driverID)).Result()
+	if err != nil {
+		return Transition{}, fmt.Errorf("failed to load zones of driver %s: %w", driverID, err)
+	}
+
+	var transition Transition
+	for _, zoneID := range previous {
+		if !current[zoneID] {
+			transition.Exited = append(transition.Exited, zoneID)
+		}
+		delete(current, zoneID)
+	}
+	for _, zone := range zones {
+		if current[zone.ID] {
+			transition.Entered = append(transition.Entered, zone.ID)
+		}
+	}
+	if len(transition.Entered) == 0 && len(transition.Exited) == 0 {
+		return transition, nil
+	}
+
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, zoneID := range transition.Exited {
+			pipe.SRem(ctx, s.zoneDriversKey(zoneID), driverID)
+			pipe.SRem(ctx, s.driverZonesKey(driverID), zoneID)
+		}
+		for _, zoneID := range transition.Entered {
+			pipe.SAdd(ctx, s.zoneDriversKey(zoneID), driverID)
+			pipe.SAdd(ctx, s.driverZonesKey(driverID), zoneID)
+		}
+		return nil
+	})
+	if err != nil {
+		return Transition{}, fmt.Errorf("failed to index zones of driver %s: %w", driverID, err)
+	}
+	return transition, nil
+}
+
+// RemoveDriver drops a driver from every zone, e.g. when it goes offline
+func (s *Store) RemoveDriver(ctx context.Context, driverID string) error {
+	zoneIDs, err := s.client.SMembers(ctx, s.driverZonesKey(driverID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to remove driver %s from zones: %w", driverID, err)
+	}
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, zoneID := range zoneIDs {
+			pipe.SRem(ctx, s.zoneDriversKey(zoneID), driverID)
+		}
+		pipe.Del(ctx, s.driverZonesKey(driverID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove driver %s from zones: %w", driverID, err)
+	}
+	return nil
+}
+
+// DriversInZone returns the IDs of drivers currently indexed inside a zone
+func (s *Store) DriversInZone(ctx context.Context, zoneID string) ([]string, error) {
+	drivers, err := s.client.SMembers(ctx, s.zoneDriversKey(zoneID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drivers in zone %s: %w", zoneID, err)
+	}
+	sort.Strings(drivers)
+	return drivers, nil
+}
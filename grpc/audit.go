@@ -0,0 +1,130 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metric names recorded by the audit interceptors
+const (
+	MetricPayloadBytes      = "grpc_payload_bytes"
+	MetricDeadlineRemaining = "grpc_deadline_remaining_seconds"
+)
+
+// defaultMaxMsgSize is the gRPC default message limit
+const defaultMaxMsgSize = 4 << 20
+
+// AuditConfig configures payload size and deadline auditing
+type AuditConfig struct {
+	// MaxRecvMsgSize and MaxSendMsgSize are the limits payloads are compared
+	// against; the gRPC default of 4MB when zero
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+	// WarnRatio logs a warning when a payload exceeds this fraction of its limit
+	WarnRatio float64
+	// Metrics receives size and deadline histograms; the process-wide provider when nil
+	Metrics metrics.Provider
+	// Logger receives warnings; nil disables them
+	Logger Logger
+}
+
+// auditor measures payloads for one side of a call
+type auditor struct {
+	side     string
+	recvMax  int
+	sendMax  int
+	ratio    float64
+	provider metrics.Provider
+	logger   Logger
+}
+
+func newAuditor(side string, cfg AuditConfig) *auditor {
+	a := &auditor{
+		side:     side,
+		recvMax:  cfg.MaxRecvMsgSize,
+		sendMax:  cfg.MaxSendMsgSize,
+		ratio:    cfg.WarnRatio,
+		provider: metrics.OrDefault(cfg.Metrics),
+		logger:   cfg.Logger,
+	}
+	if a.recvMax <= 0 {
+		a.recvMax = defaultMaxMsgSize
+	}
+	if a.sendMax <= 0 {
+		a.sendMax = defaultMaxMsgSize
+	}
+	if a.ratio <= 0 || a.ratio > 1 {
+		a.ratio = 0.8
+	}
+	if a.logger == nil {
+		a.logger = NopLogger
+	}
+	return a
+}
+
+// payload records the size of msg; direction is request or response
+func (a *auditor) payload(method, direction string, msg interface{}, limit int) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return
+	}
+	size := proto.Size(m)
+	a.provider.ObserveHistogram(MetricPayloadBytes, float64(size), metrics.Labels{
+		"side":      a.side,
+		"method":    method,
+		"direction": direction,
+	})
+	if float64(size) >= float64(limit)*a.ratio {
+		a.logger.Log(LogLevelWarn, "grpc payload close to message size limit",
+			F("side", a.side),
+			F("method", method),
+			F("direction", direction),
+			F("bytes", size),
+			F("limit", limit),
+		)
+	}
+}
+
+// deadline records the time left before the call deadline, if any
+func (a *auditor) deadline(ctx context.Context, method string) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	a.provider.ObserveHistogram(MetricDeadlineRemaining, time.Until(deadline).Seconds(), metrics.Labels{
+		"side":   a.side,
+		"method": method,
+	})
+}
+
+// UnaryClientAuditInterceptor audits outgoing request and incoming response sizes
+func UnaryClientAuditInterceptor(cfg AuditConfig) grpc.UnaryClientInterceptor {
+	a := newAuditor("client", cfg)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		a.deadline(ctx, method)
+		a.payload(method, "request", req, a.sendMax)
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			a.payload(method, "response", reply, a.recvMax)
+		}
+		return err
+	}
+}
+
+// UnaryServerAuditInterceptor audits incoming request and outgoing response sizes
+func UnaryServerAuditInterceptor(cfg AuditConfig) grpc.UnaryServerInterceptor {
+	a := newAuditor("server", cfg)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		a.deadline(ctx, info.FullMethod)
+		a.payload(info.FullMethod, "request", req, a.recvMax)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			a.payload(info.FullMethod, "response", resp, a.sendMax)
+		}
+		return resp, err
+	}
+}
@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/grpcmd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Token is a bearer token with its expiry
+type Token struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+// TokenProvider supplies the service identity token used when no caller
+// token is available
+type TokenProvider interface {
+	Token(ctx context.Context) (Token, error)
+	// Invalidate drops a cached token after the server rejected it
+	Invalidate()
+}
+
+// TokenFetchFunc fetches a fresh service token
+type TokenFetchFunc func(ctx context.Context) (Token, error)
+
+// CachingTokenProvider caches a fetched token until shortly before it expires
+type CachingTokenProvider struct {
+	fetch         TokenFetchFunc
+	refreshBefore time.Duration
+
+	mu    sync.Mutex
+	token Token
+}
+
+// NewCachingTokenProvider creates a provider refreshing refreshBefore ahead of expiry
+func NewCachingTokenProvider(fetch TokenFetchFunc, refreshBefore time.Duration) *CachingTokenProvider {
+	if refreshBefore <= 0 {
+		refreshBefore = time.Minute
+	}
+	return &CachingTokenProvider{fetch: fetch, refreshBefore: refreshBefore}
+}
+
+// Token returns the cached token, fetching a new one when missing or expiring
+func (p *CachingTokenProvider) Token(ctx context.Context) (Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token.Value != "" && (p.token.ExpiresAt.IsZero() || time.Until(p.token.ExpiresAt) > p.refreshBefore) {
+		return p.token, nil
+	}
+
+	token, err := p.fetch(ctx)
+	if err != nil {
+		// Keep using a still valid token if the refresh failed
+		if p.token.Value != "" && time.Now().Before(p.token.ExpiresAt) {
+			return p.token, nil
+		}
+		return Token{}, fmt.Errorf("failed to fetch service token: %w", err)
+	}
+	p.token = token
+	return token, nil
+}
+
+// Invalidate drops the cached token
+func (p *CachingTokenProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.token = Token{}
+}
+
+// AuthConfig configures authorization on outgoing calls
+type AuthConfig struct {
+	// PropagateCaller forwards the caller's token from WithCallerToken or the
+	// incoming authorization metadata
+	PropagateCaller bool
+	// Provider supplies a service token when there is no caller token
+	Provider TokenProvider
+}
+
+type callerTokenKey struct{}
+
+// WithCallerToken attaches the end user's token to forward on outgoing calls
+func WithCallerToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, callerTokenKey{}, strings.TrimPrefix(token, "Bearer "))
+}
+
+// CallerToken returns the token set by WithCallerToken, or the bearer token of
+// the incoming request
+func CallerToken(ctx context.Context) string {
+	if token, ok := ctx.Value(callerTokenKey{}).(string); ok && token != "" {
+		return token
+	}
+	header := grpcmd.Get(ctx, grpcmd.KeyAuthorization)
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return token
+	}
+	return ""
+}
+
+// authorize sets the authorization header unless the caller already did.
+// It reports whether a service token was used.
+func (a AuthConfig) authorize(ctx context.Context) (context.Context, bool, error) {
+	if grpcmd.GetOutgoing(ctx, grpcmd.KeyAuthorization) != "" {
+		return ctx, false, nil
+	}
+	if a.PropagateCaller {
+		if token := CallerToken(ctx); token != "" {
+			return grpcmd.Set(ctx, grpcmd.KeyAuthorization, "Bearer "+token), false, nil
+		}
+	}
+	if a.Provider == nil {
+		return ctx, false, nil
+	}
+	token, err := a.Provider.Token(ctx)
+	if err != nil {
+		return ctx, false, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return grpcmd.Set(ctx, grpcmd.KeyAuthorization, "Bearer "+token.Value), true, nil
+}
+
+// unaryAuthInterceptor injects the token and retries once with a fresh
+// service token when the server rejects the cached one
+func unaryAuthInterceptor(auth AuthConfig) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		authCtx, serviceToken, err := auth.authorize(ctx)
+		if err != nil {
+			return err
+		}
+		err = invoker(authCtx, method, req, reply, cc, opts...)
+		if !serviceToken || status.Code(err) != codes.Unauthenticated {
+			return err
+		}
+
+		auth.Provider.Invalidate()
+		if authCtx, _, err = auth.authorize(ctx); err != nil {
+			return err
+		}
+		return invoker(authCtx, method, req, reply, cc, opts...)
+	}
+}
+
+// streamAuthInterceptor injects the token on new streams
+func streamAuthInterceptor(auth AuthConfig) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		authCtx, _, err := auth.authorize(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(authCtx, desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// Load balancing policies
+const (
+	LoadBalancingPickFirst  = "pick_first"
+	LoadBalancingRoundRobin = "round_robin"
+)
+
+// staticScheme resolves ServiceConfig.Endpoints
+const staticScheme = "static"
+
+// hasScheme reports whether host is a full gRPC target such as dns:///trip-service:50051
+func hasScheme(host string) bool {
+	return strings.Contains(host, ":///")
+}
+
+// Target returns the dial target of the service. Static endpoints and hosts
+// with a resolver scheme are dialed directly; otherwise calls go through the
+// local sidecar port.
+func (s ServiceConfig) Target() string {
+	switch {
+	case len(s.Endpoints) > 0:
+		return staticScheme + ":///" + s.Name
+	case hasScheme(s.Host):
+		return s.Host
+	default:
+		return fmt.Sprintf("localhost:%s", s.Port)
+	}
+}
+
+// hasAddress reports whether the config has something to dial or proxy to
+func (s ServiceConfig) hasAddress() bool {
+	return s.Port != "" || len(s.Endpoints) > 0 || hasScheme(s.Host) || s.Transport == TransportDapr
+}
+
+// balancingPolicy returns the configured policy, defaulting to round robin
+// for targets that resolve to multiple addresses
+func (s ServiceConfig) balancingPolicy() string {
+	if s.LoadBalancing != "" {
+		return s.LoadBalancing
+	}
+	if len(s.Endpoints) > 0 || hasScheme(s.Host) {
+		return LoadBalancingRoundRobin
+	}
+	return LoadBalancingPickFirst
+}
+
+// balancerDialOptions returns the resolver and load balancing dial options
+func (s ServiceConfig) balancerDialOptions() []grpc.DialOption {
+	opts := []grpc.DialOption{
+		grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]}`, s.balancingPolicy())),
+	}
+
+	if len(s.Endpoints) > 0 {
+		addrs := make([]resolver.Address, len(s.Endpoints))
+		for i, endpoint := range s.Endpoints {
+			addrs[i] = resolver.Address{Addr: endpoint}
+		}
+		r := manual.NewBuilderWithScheme(staticScheme)
+		r.InitialState(resolver.State{Addresses: addrs})
+		opts = append(opts, grpc.WithResolvers(r))
+	}
+	return opts
+}
@@ -0,0 +1,256 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+// String returns the state name
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// MarshalText renders the state by name in JSON
+func (s CircuitState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// BreakerConfig configures the per-service circuit breaker
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit; 0 disables the breaker
+	FailureThreshold int
+	// CoolDown is how long the circuit stays open before allowing probes
+	CoolDown time.Duration
+	// HalfOpenMaxCalls is the number of concurrent probe calls while half-open
+	HalfOpenMaxCalls int
+	// SuccessThreshold is the number of successful probes that closes the circuit
+	SuccessThreshold int
+}
+
+// DefaultBreakerConfig returns the breaker settings used by DefaultOptions
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,
+		CoolDown:         30 * time.Second,
+		HalfOpenMaxCalls: 1,
+		SuccessThreshold: 2,
+	}
+}
+
+// ErrCircuitOpen is returned while a circuit is open
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerSnapshot is a point-in-time view of a breaker
+type BreakerSnapshot struct {
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	OpenedAt            time.Time    `json:"openedAt,omitempty"`
+}
+
+// CircuitBreaker provides circuit breaker functionality
+type CircuitBreaker struct {
+	config BreakerConfig
+
+	mu           sync.Mutex
+	state        CircuitState
+	failureCount int
+	successCount int
+	probes       int
+	openedAt     time.Time
+	now          func() time.Time
+}
+
+// NewCircuitBreaker creates a new circuit breaker that opens after threshold
+// consecutive failures and probes again after timeout
+func NewCircuitBreaker(threshold int, timeout time.Duration) *CircuitBreaker {
+	return NewCircuitBreakerWithConfig(BreakerConfig{
+		FailureThreshold: threshold,
+		CoolDown:         timeout,
+	})
+}
+
+// NewCircuitBreakerWithConfig creates a new circuit breaker from a config
+func NewCircuitBreakerWithConfig(config BreakerConfig) *CircuitBreaker {
+	if config.CoolDown <= 0 {
+		config.CoolDown = 30 * time.Second
+	}
+	if config.HalfOpenMaxCalls <= 0 {
+		config.HalfOpenMaxCalls = 1
+	}
+	if config.SuccessThreshold <= 0 {
+		config.SuccessThreshold = 1
+	}
+	return &CircuitBreaker{
+		config: config,
+		state:  CircuitClosed,
+		now:    time.Now,
+	}
+}
+
+// Execute executes an operation with circuit breaker protection
+func (cb *CircuitBreaker) Execute(operation func() error) error {
+	if err := cb.Allow(); err != nil {
+		return status.Error(codes.Unavailable, "Circuit breaker is open")
+	}
+	err := operation()
+	cb.record(err != nil)
+	return err
+}
+
+// Allow reports whether a call may proceed; allowed callers must report the
+// outcome with Record
+func (cb *CircuitBreaker) Allow() error {
+	if cb == nil || cb.config.FailureThreshold <= 0 {
+		return nil
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.config.CoolDown {
+			return ErrCircuitOpen
+		}
+		cb.state, cb.successCount, cb.probes = CircuitHalfOpen, 0, 0
+		fallthrough
+	case CircuitHalfOpen:
+		if cb.probes >= cb.config.HalfOpenMaxCalls {
+			return ErrCircuitOpen
+		}
+		cb.probes++
+	}
+	return nil
+}
+
+// Record reports the outcome of an allowed gRPC call. Only errors that point
+// at an unhealthy service count as failures, not business errors such as NotFound.
+func (cb *CircuitBreaker) Record(err error) {
+	cb.record(isBreakerFailure(err))
+}
+
+func (cb *CircuitBreaker) record(failed bool) {
+	if cb == nil || cb.config.FailureThreshold <= 0 {
+		return
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitHalfOpen:
+		cb.probes--
+		if failed {
+			cb.trip()
+			return
+		}
+		cb.successCount++
+		if cb.successCount >= cb.config.SuccessThreshold {
+			cb.state, cb.failureCount = CircuitClosed, 0
+		}
+	case CircuitClosed:
+		if !failed {
+			cb.failureCount = 0
+			return
+		}
+		cb.failureCount++
+		if cb.failureCount >= cb.config.FailureThreshold {
+			cb.trip()
+		}
+	}
+}
+
+func (cb *CircuitBreaker) trip() {
+	cb.state = CircuitOpen
+	cb.openedAt = cb.now()
+	cb.probes = 0
+}
+
+// GetState returns the current circuit breaker state
+func (cb *CircuitBreaker) GetState() CircuitState {
+	return cb.Snapshot().State
+}
+
+// Snapshot returns the current breaker state
+func (cb *CircuitBreaker) Snapshot() BreakerSnapshot {
+	if cb == nil {
+		return BreakerSnapshot{State: CircuitClosed}
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	state := cb.state
+	if state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.config.CoolDown {
+		state = CircuitHalfOpen
+	}
+	return BreakerSnapshot{
+		State:               state,
+		ConsecutiveFailures: cb.failureCount,
+		OpenedAt:            cb.openedAt,
+	}
+}
+
+// isBreakerFailure reports whether err indicates an unhealthy service rather
+// than a caller or business error
+func isBreakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+func circuitOpenError(serviceName string) error {
+	return status.Errorf(codes.Unavailable, "%s: %v", serviceName, ErrCircuitOpen)
+}
+
+// unaryBreakerInterceptor rejects calls while the circuit is open
+func unaryBreakerInterceptor(serviceName string, breaker *CircuitBreaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := breaker.Allow(); err != nil {
+			return circuitOpenError(serviceName)
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		breaker.Record(err)
+		return err
+	}
+}
+
+// streamBreakerInterceptor rejects new streams while the circuit is open.
+// Only stream establishment counts towards the breaker.
+func streamBreakerInterceptor(serviceName string, breaker *CircuitBreaker) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := breaker.Allow(); err != nil {
+			return nil, circuitOpenError(serviceName)
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		breaker.Record(err)
+		return stream, err
+	}
+}
@@ -1,72 +1,369 @@
 package grpc
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dapr/go-sdk/client"
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
+// Options configures a GRPCClient
+type Options struct {
+	// TLS is the default transport security; ServiceConfig.TLS overrides it per service
+	TLS TLSConfig
+	// Dial holds the default dial settings; ServiceConfig.Dial overrides them per service
+	Dial DialConfig
+	// Transport is the default transport; ServiceConfig.Transport overrides it
+	Transport Transport
+	// Timeouts sets default deadlines on outgoing calls
+	Timeouts TimeoutPolicy
+	// Pool configures idle eviction, maximum connection age and draining
+	Pool PoolConfig
+	// Hedging sends duplicate attempts of slow idempotent reads
+	Hedging HedgingPolicy
+	// Auth configures the authorization header on outgoing calls
+	Auth AuthConfig
+	// Breaker configures the per-service circuit breaker
+	Breaker BreakerConfig
+	// Retry configures retries of unary calls; it is off by default because
+	// retries replay non-idempotent RPCs, see DefaultRetryPolicy to opt in
+	Retry RetryPolicy
+	// Logger receives connection and per-call entries; calls are logged at
+	// debug level and failures at warn
+	Logger Logger
+	// EnableMetrics records call, retry and connection metrics
+	EnableMetrics bool
+	// Metrics receives metrics when enabled; the process-wide provider when nil
+	Metrics metrics.Provider
+	// AuditPayloads records payload sizes and remaining deadlines, warning when
+	// a payload nears the service's message size limit
+	AuditPayloads bool
+	// Registry resolves service configurations; DefaultRegistry when nil
+	Registry *Registry
+}
+
+// DefaultOptions returns the options used by NewGRPCClient
+func DefaultOptions() Options {
+	return Options{
+		TLS:       TLSConfig{Mode: TLSModeInsecure},
+		Transport: TransportDirect,
+		Dial:      DefaultDialConfig(),
+		Pool:      DefaultPoolConfig(),
+		Auth:      AuthConfig{PropagateCaller: true},
+		Breaker:   DefaultBreakerConfig(),
+		Logger:    NewStdLogger(LogLevelInfo),
+	}
+}
+
+// ConnectionInfo describes a pooled connection
+type ConnectionInfo struct {
+	ServiceName string          `json:"serviceName"`
+	Target      string          `json:"target"`
+	State       string          `json:"state"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	LastUsed    time.Time       `json:"lastUsed"`
+	InFlight    int64           `json:"inFlight"`
+	Breaker     BreakerSnapshot `json:"breaker"`
+}
+
+// serviceConn is a pooled connection with its bookkeeping
+type serviceConn struct {
+	conn      *grpc.ClientConn
+	target    string
+	createdAt time.Time
+	lastUsed  atomicTime
+	inflight  atomic.Int64
+	breaker   *CircuitBreaker
+	retrier   *Retrier
+}
+
+// atomicTime guards a time for concurrent reads and writes
+type atomicTime struct {
+	mu sync.RWMutex
+	t  time.Time
+}
+
+func (a *atomicTime) Store(t time.Time) {
+	a.mu.Lock()
+	a.t = t
+	a.mu.Unlock()
+}
+
+func (a *atomicTime) Load() time.Time {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.t
+}
+
 // GRPCClient manages gRPC connections for service-to-service communication
 type GRPCClient struct {
 	daprClient client.Client
-	conns      map[string]*grpc.ClientConn
+	options    Options
+	metrics    *clientMetrics
+
+	mu    sync.Mutex
+	conns map[string]*serviceConn
+
+	stopOnce sync.Once
+	stop     chan struct{}
 }
 
 // NewGRPCClient creates a new gRPC client with Dapr integration
 func NewGRPCClient() (*GRPCClient, error) {
+	return NewGRPCClientWithOptions(DefaultOptions())
+}
+
+// NewGRPCClientWithOptions creates a new gRPC client with the given options
+func NewGRPCClientWithOptions(opts Options) (*GRPCClient, error) {
 	daprClient, err := client.NewClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Dapr client: %w", err)
 	}
 
-	return &GRPCClient{
+	if opts.Registry == nil {
+		opts.Registry = DefaultRegistry
+	}
+	if opts.Logger == nil {
+		opts.Logger = NopLogger
+	}
+	if opts.Pool.DrainTimeout <= 0 {
+		opts.Pool.DrainTimeout = 30 * time.Second
+	}
+
+	c := &GRPCClient{
 		daprClient: daprClient,
-		conns:      make(map[string]*grpc.ClientConn),
-	}, nil
+		options:    opts,
+		conns:      make(map[string]*serviceConn),
+		stop:       make(chan struct{}),
+	}
+	if opts.Pool.SweepInterval > 0 && (opts.Pool.IdleTimeout > 0 || opts.Pool.MaxConnectionAge > 0) {
+		go c.runSweeper(c.stop)
+	}
+	if opts.EnableMetrics {
+		c.metrics = &clientMetrics{provider: metrics.OrDefault(opts.Metrics)}
+	}
+	opts.Registry.OnChange(c.handleServiceChange)
+	return c, nil
+}
+
+// handleServiceChange drops the pooled connection of a changed or removed
+// service so the next call dials the new target
+func (c *GRPCClient) handleServiceChange(change ServiceChange) {
+	if change.Type == ServiceAdded {
+		return
+	}
+
+	c.mu.Lock()
+	sc, exists := c.conns[change.Name]
+	delete(c.conns, change.Name)
+	c.reportPoolSize()
+	c.mu.Unlock()
+
+	if !exists {
+		return
+	}
+	c.options.Logger.Log(LogLevelInfo, "service changed, reconnecting on next call",
+		F("service", change.Name), F("change", change.Type))
+	c.drainAsync(change.Name, sc)
 }
 
-// GetServiceConnection returns a gRPC connection to the specified service
+// GetServiceConnection returns the pooled gRPC connection to the specified
+// service. The connection is closed when the service's registry entry
+// changes or the pool evicts it; use ServiceConn for long-lived stubs.
 func (c *GRPCClient) GetServiceConnection(serviceName string) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	// Check if we already have a connection
-	if conn, exists := c.conns[serviceName]; exists {
-		return conn, nil
+	if sc, exists := c.conns[serviceName]; exists {
+		return sc.conn, nil
 	}
 
 	// Get service configuration
-	config, exists := GetServiceConfig(serviceName)
+	config, exists := c.options.Registry.Get(serviceName)
 	if !exists {
 		return nil, fmt.Errorf("service %s not found in configuration", serviceName)
 	}
 
-	// Create connection using Dapr service invocation
-	// In Dapr, we use the service name directly for service-to-service calls
-	target := fmt.Sprintf("localhost:%s", config.Port)
+	transport := config.transport(c.options.Transport)
+	target := config.Target()
+
+	tlsConfig := c.options.TLS
+	if transport == TransportDapr {
+		// The sidecar is local and handles mTLS to the target app
+		target = daprTarget()
+		tlsConfig = TLSConfig{Mode: TLSModeInsecure}
+	}
+	if config.TLS != nil {
+		tlsConfig = *config.TLS
+	}
+	creds, err := tlsConfig.Credentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS for %s: %w", serviceName, err)
+	}
+
+	sc := &serviceConn{
+		target:    target,
+		createdAt: time.Now(),
+		breaker:   NewCircuitBreakerWithConfig(c.options.Breaker),
+		retrier:   NewRetrier(c.options.Retry),
+	}
 
-	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	unary := []grpc.UnaryClientInterceptor{
+		unaryLoggingInterceptor(serviceName, target, c.options.Logger),
+		unaryTimeoutInterceptor(config, c.options.Timeouts),
+		sc.unaryTrackingInterceptor(),
+		unaryBreakerInterceptor(serviceName, sc.breaker),
+		unaryRetryInterceptor(sc.retrier),
+	}
+	if c.options.AuditPayloads {
+		dial := c.options.Dial.Merge(config.Dial)
+		unary = append(unary, UnaryClientAuditInterceptor(AuditConfig{
+			MaxRecvMsgSize: dial.MaxRecvMsgSize,
+			MaxSendMsgSize: dial.MaxSendMsgSize,
+			Metrics:        c.options.Metrics,
+			Logger:         c.options.Logger,
+		}))
+	}
+	if c.options.Hedging.enabled() {
+		unary = append(unary, unaryHedgingInterceptor(serviceName, c.options.Hedging, c.metrics))
+	}
+	unary = append(unary, unaryAuthInterceptor(c.options.Auth))
+	stream := []grpc.StreamClientInterceptor{
+		streamLoggingInterceptor(serviceName, target, c.options.Logger),
+		streamTimeoutInterceptor(c.options.Timeouts),
+		sc.streamTrackingInterceptor(),
+		streamBreakerInterceptor(serviceName, sc.breaker),
+		streamAuthInterceptor(c.options.Auth),
+	}
+	if transport == TransportDapr {
+		unary = append(unary, unaryDaprInterceptor(config.appID()))
+		stream = append(stream, streamDaprInterceptor(config.appID()))
+	}
+	if c.metrics != nil {
+		unary = append([]grpc.UnaryClientInterceptor{unaryMetricsInterceptor(serviceName, c.metrics)}, unary...)
+		stream = append([]grpc.StreamClientInterceptor{streamMetricsInterceptor(serviceName, c.metrics)}, stream...)
+	}
+
+	dialOpts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(stream...),
+	}, c.options.Dial.Merge(config.Dial).DialOptions()...)
+	if transport == TransportDirect {
+		dialOpts = append(dialOpts, config.balancerDialOptions()...)
+	}
+
+	conn, err := grpc.Dial(target, dialOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to %s: %w", serviceName, err)
 	}
+	sc.conn = conn
+	sc.lastUsed.Store(sc.createdAt)
 
 	// Cache the connection
-	c.conns[serviceName] = conn
+	c.conns[serviceName] = sc
+	c.reportPoolSize()
+	if c.metrics != nil {
+		go c.metrics.watchConnection(serviceName, conn)
+	}
 
-	log.Printf("✅ Connected to %s service on %s", serviceName, config.Port)
+	c.options.Logger.Log(LogLevelInfo, "connected", F("service", serviceName), F("target", target), F("transport", transport))
 	return conn, nil
 }
 
+func (sc *serviceConn) unaryTrackingInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		sc.lastUsed.Store(time.Now())
+		sc.inflight.Add(1)
+		defer sc.inflight.Add(-1)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func (sc *serviceConn) streamTrackingInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		sc.lastUsed.Store(time.Now())
+		sc.inflight.Add(1)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			sc.inflight.Add(-1)
+			return nil, err
+		}
+		return &trackedStream{ClientStream: stream, done: func() {
+			sc.inflight.Add(-1)
+			sc.lastUsed.Store(time.Now())
+		}}, nil
+	}
+}
+
+func (sc *serviceConn) info(serviceName string) ConnectionInfo {
+	return ConnectionInfo{
+		ServiceName: serviceName,
+		Target:      sc.target,
+		State:       sc.conn.GetState().String(),
+		CreatedAt:   sc.createdAt,
+		LastUsed:    sc.lastUsed.Load(),
+		InFlight:    sc.inflight.Load(),
+		Breaker:     sc.breaker.Snapshot(),
+	}
+}
+
+// reportPoolSize records the number of pooled connections; callers hold c.mu
+func (c *GRPCClient) reportPoolSize() {
+	if c.metrics != nil {
+		c.metrics.poolSize(len(c.conns))
+	}
+}
+
+// GetConnectionInfo returns the state of the pooled connection to a service
+func (c *GRPCClient) GetConnectionInfo(serviceName string) (ConnectionInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sc, ok := c.conns[serviceName]
+	if !ok {
+		return ConnectionInfo{}, false
+	}
+	return sc.info(serviceName), true
+}
+
+// GetAllConnectionInfo returns the state of every pooled connection, sorted by service
+func (c *GRPCClient) GetAllConnectionInfo() []ConnectionInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	infos := make([]ConnectionInfo, 0, len(c.conns))
+	for name, sc := range c.conns {
+		infos = append(infos, sc.info(name))
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ServiceName < infos[j].ServiceName })
+	return infos
+}
+
 // Close closes all connections
 func (c *GRPCClient) Close() error {
+	c.stopOnce.Do(func() { close(c.stop) })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	var lastErr error
 
-	for serviceName, conn := range c.conns {
-		if err := conn.Close(); err != nil {
-			log.Printf("Error closing connection to %s: %v", serviceName, err)
+	for serviceName, sc := range c.conns {
+		if err := sc.conn.Close(); err != nil {
+			c.options.Logger.Log(LogLevelWarn, "failed to close connection", F("service", serviceName), F("error", err))
 			lastErr = err
 		}
 	}
+	c.conns = make(map[string]*serviceConn)
+	c.reportPoolSize()
 
 	if c.daprClient != nil {
 		c.daprClient.Close()
@@ -1,16 +1,38 @@
 package grpc
 
+import "time"
+
 type ServiceConfig struct {
 	Name string
 	Host string
 	Port string
+	// TLS overrides Options.TLS for this service when set
+	TLS *TLSConfig
+	// Endpoints are static host:port addresses balanced across instead of
+	// Host/Port, for deployments without service discovery
+	Endpoints []string
+	// LoadBalancing is pick_first or round_robin; round_robin is the default
+	// for Endpoints and for Host targets with a resolver scheme (dns:///...)
+	LoadBalancing string
+	// Transport overrides Options.Transport for this service
+	Transport Transport
+	// AppID is the Dapr app ID used with TransportDapr; Name when empty
+	AppID string
+	// Timeout is the default deadline of unary calls to this service
+	Timeout time.Duration
+	// Dial overrides non-zero fields of Options.Dial for this service
+	Dial *DialConfig
 }
 
+// Services are the built-in service configurations that seed DefaultRegistry.
+// Use RegisterService to add or change services at runtime.
 var Services = map[string]ServiceConfig{
 	"payment-service": {
 		Name: "payment-service",
 		Host: "",
 		Port: "50055", // Payment service gRPC port (from Kubernetes service)
+		// Payment providers can be slow to confirm charges
+		Timeout: 10 * time.Second,
 	},
 	"trip-service": {
 		Name: "trip-service",
@@ -26,6 +48,8 @@ var Services = map[string]ServiceConfig{
 		Name: "driver-service",
 		Host: "k8s-motocabz-driverse-ffd6064118-2130482738.eu-north-1.elb.amazonaws.com",
 		Port: "50052", // Driver service gRPC port (from Kubernetes service)
+		// Document uploads exceed the default 4MB message limit
+		Dial: &DialConfig{MaxRecvMsgSize: 16 << 20, MaxSendMsgSize: 16 << 20},
 	},
 	"rider-service": {
 		Name: "rider-service",
@@ -35,8 +59,7 @@ var Services = map[string]ServiceConfig{
 }
 
 // GetServiceConfig returns the ServiceConfig for a given service name.
-// Returns the config and true if found, or an empty config and false if not found.
+// Returns the config and true if found in DefaultRegistry, or an empty config and false if not found.
 func GetServiceConfig(serviceName string) (ServiceConfig, bool) {
-	cfg, ok := Services[serviceName]
-	return cfg, ok
+	return DefaultRegistry.Get(serviceName)
 }
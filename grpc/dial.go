@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// KeepaliveConfig configures client keepalive pings
+type KeepaliveConfig struct {
+	// Time is the idle period after which the client pings the server
+	Time time.Duration
+	// Timeout is how long to wait for a ping ack before closing the connection
+	Timeout time.Duration
+	// PermitWithoutStream sends pings even with no active calls
+	PermitWithoutStream bool
+}
+
+// DialConfig holds dial settings; zero fields inherit from the defaults
+type DialConfig struct {
+	// MaxRecvMsgSize and MaxSendMsgSize limit message sizes in bytes
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+	Keepalive      *KeepaliveConfig
+	// Authority overrides the :authority header sent to the server
+	Authority string
+}
+
+// DefaultDialConfig returns the dial settings used by DefaultOptions. The
+// keepalive matches the grpc-go server's default enforcement policy (pings no
+// more often than every 5 minutes, only during calls); pinging faster makes
+// servers answer with GOAWAY "too_many_pings" and drop the connection.
+func DefaultDialConfig() DialConfig {
+	return DialConfig{
+		MaxRecvMsgSize: 4 << 20,
+		MaxSendMsgSize: 4 << 20,
+		Keepalive: &KeepaliveConfig{
+			Time:    5 * time.Minute,
+			Timeout: 20 * time.Second,
+		},
+	}
+}
+
+// Merge returns d with the non-zero fields of override applied
+func (d DialConfig) Merge(override *DialConfig) DialConfig {
+	if override == nil {
+		return d
+	}
+	if override.MaxRecvMsgSize > 0 {
+		d.MaxRecvMsgSize = override.MaxRecvMsgSize
+	}
+	if override.MaxSendMsgSize > 0 {
+		d.MaxSendMsgSize = override.MaxSendMsgSize
+	}
+	if override.Keepalive != nil {
+		d.Keepalive = override.Keepalive
+	}
+	if override.Authority != "" {
+		d.Authority = override.Authority
+	}
+	return d
+}
+
+// DialOptions converts the config to grpc dial options
+func (d DialConfig) DialOptions() []grpc.DialOption {
+	var opts []grpc.DialOption
+
+	var callOpts []grpc.CallOption
+	if d.MaxRecvMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallRecvMsgSize(d.MaxRecvMsgSize))
+	}
+	if d.MaxSendMsgSize > 0 {
+		callOpts = append(callOpts, grpc.MaxCallSendMsgSize(d.MaxSendMsgSize))
+	}
+	if len(callOpts) > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(callOpts...))
+	}
+
+	if d.Keepalive != nil {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                d.Keepalive.Time,
+			Timeout:             d.Keepalive.Timeout,
+			PermitWithoutStream: d.Keepalive.PermitWithoutStream,
+		}))
+	}
+	if d.Authority != "" {
+		opts = append(opts, grpc.WithAuthority(d.Authority))
+	}
+	return opts
+}
@@ -156,71 +156,6 @@ func (eh *GRPCErrorHandler) RetryOperation(operation func() error, maxRetries in
 	return fmt.Errorf("operation failed after %d retries: %w", maxRetries, lastErr)
 }
 
-// CircuitBreaker provides circuit breaker functionality
-type CircuitBreaker struct {
-	failureCount    int
-	successCount    int
-	lastFailureTime time.Time
-	state           CircuitState
-	threshold       int
-	timeout         time.Duration
-}
-
-type CircuitState int
-
-const (
-	CircuitClosed CircuitState = iota
-	CircuitOpen
-	CircuitHalfOpen
-)
-
-// NewCircuitBreaker creates a new circuit breaker
-func NewCircuitBreaker(threshold int, timeout time.Duration) *CircuitBreaker {
-	return &CircuitBreaker{
-		threshold: threshold,
-		timeout:   timeout,
-		state:     CircuitClosed,
-	}
-}
-
-// Execute executes an operation with circuit breaker protection
-func (cb *CircuitBreaker) Execute(operation func() error) error {
-	if cb.state == CircuitOpen {
-		if time.Since(cb.lastFailureTime) > cb.timeout {
-			cb.state = CircuitHalfOpen
-		} else {
-			return status.Error(codes.Unavailable, "Circuit breaker is open")
-		}
-	}
-
-	err := operation()
-
-	if err != nil {
-		cb.failureCount++
-		cb.lastFailureTime = time.Now()
-
-		if cb.failureCount >= cb.threshold {
-			cb.state = CircuitOpen
-		}
-
-		return err
-	}
-
-	cb.successCount++
-	cb.failureCount = 0
-
-	if cb.state == CircuitHalfOpen {
-		cb.state = CircuitClosed
-	}
-
-	return nil
-}
-
-// GetState returns the current circuit breaker state
-func (cb *CircuitBreaker) GetState() CircuitState {
-	return cb.state
-}
-
 // MetricsCollector collects gRPC service metrics
 type MetricsCollector struct {
 	serviceName     string
@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// HedgingPolicy configures hedged requests for idempotent unary methods.
+// A hedge is a duplicate attempt sent when the first one is slow; the first
+// success wins and the others are cancelled.
+type HedgingPolicy struct {
+	// Methods lists the full method names safe to send more than once
+	Methods []string
+	// Delay is how long to wait for an attempt before sending the next
+	Delay time.Duration
+	// MaxAttempts includes the first attempt; defaults to 2
+	MaxAttempts int
+}
+
+// enabled reports whether the policy hedges any method
+func (p HedgingPolicy) enabled() bool {
+	return len(p.Methods) > 0 && p.Delay > 0
+}
+
+func (p HedgingPolicy) hedged(method string) bool {
+	for _, m := range p.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+type hedgeResult struct {
+	attempt int
+	reply   proto.Message
+	err     error
+}
+
+// unaryHedgingInterceptor sends up to MaxAttempts staggered copies of
+// idempotent calls. m may be nil when metrics are disabled.
+func unaryHedgingInterceptor(serviceName string, policy HedgingPolicy, m *clientMetrics) grpc.UnaryClientInterceptor {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 2 {
+		maxAttempts = 2
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		out, ok := reply.(proto.Message)
+		if !ok || !policy.hedged(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		results := make(chan hedgeResult, maxAttempts)
+		send := func(attempt int) {
+			attemptReply := proto.Clone(out)
+			proto.Reset(attemptReply)
+			go func() {
+				err := invoker(ctx, method, req, attemptReply, cc, opts...)
+				results <- hedgeResult{attempt: attempt, reply: attemptReply, err: err}
+			}()
+		}
+
+		send(1)
+		sent, pending := 1, 1
+		timer := time.NewTimer(policy.Delay)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-timer.C:
+				if sent < maxAttempts {
+					sent++
+					pending++
+					send(sent)
+					if m != nil {
+						m.hedge(serviceName, method)
+					}
+					timer.Reset(policy.Delay)
+				}
+			case result := <-results:
+				pending--
+				if result.err == nil {
+					proto.Reset(out)
+					proto.Merge(out, result.reply)
+					if m != nil && result.attempt > 1 {
+						m.hedgeWon(serviceName, method)
+					}
+					return nil
+				}
+				// Failures are left to the retry policy once no attempt is still running
+				if pending == 0 {
+					return result.err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package grpc
+
+import (
+	"context"
+
+	apperr "github.com/mihirk-khode/motocabz-common/error"
+	"google.golang.org/grpc"
+)
+
+// Invoke resolves the connection to service and runs call with it. Retries,
+// circuit breaking and deadlines come from the client's interceptors; any
+// failure is returned as an *error.AppError.
+func Invoke[T any](ctx context.Context, c *GRPCClient, service string, call func(ctx context.Context, conn *grpc.ClientConn) (T, error)) (T, error) {
+	var zero T
+
+	conn, err := c.GetServiceConnection(service)
+	if err != nil {
+		return zero, &apperr.AppError{
+			Code:    apperr.ErrorCodeServiceUnavailable,
+			Message: err.Error(),
+			Service: service,
+			Err:     err,
+		}
+	}
+
+	result, err := call(ctx, conn)
+	if err != nil {
+		return zero, apperr.FromGRPC(service, err)
+	}
+	return result, nil
+}
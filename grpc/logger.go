@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// LogLevel is the severity of a log entry
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String returns the level name
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// Field is a key/value pair attached to a log entry
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F creates a log field
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Logger receives structured log entries from GRPCClient
+type Logger interface {
+	Log(level LogLevel, msg string, fields ...Field)
+}
+
+// LoggerFunc adapts a function to a Logger
+type LoggerFunc func(level LogLevel, msg string, fields ...Field)
+
+// Log calls f
+func (f LoggerFunc) Log(level LogLevel, msg string, fields ...Field) {
+	f(level, msg, fields...)
+}
+
+// NopLogger discards all entries
+var NopLogger Logger = LoggerFunc(func(LogLevel, string, ...Field) {})
+
+// stdLogger writes logfmt-style lines through the standard logger
+type stdLogger struct {
+	min LogLevel
+}
+
+// NewStdLogger creates a logger writing entries at or above min to the standard logger
+func NewStdLogger(min LogLevel) Logger {
+	return stdLogger{min: min}
+}
+
+// Log writes the entry if it meets the minimum level
+func (l stdLogger) Log(level LogLevel, msg string, fields ...Field) {
+	if level < l.min {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "level=%s msg=%q", level, msg)
+	for _, f := range fields {
+		fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+	}
+	log.Print(b.String())
+}
+
+// callLevel logs successful calls at debug and failures at warn
+func callLevel(err error) LogLevel {
+	if err == nil {
+		return LogLevelDebug
+	}
+	return LogLevelWarn
+}
+
+// unaryLoggingInterceptor logs one entry per unary call, including retries
+func unaryLoggingInterceptor(serviceName, target string, logger Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logger.Log(callLevel(err), "grpc call",
+			F("service", serviceName),
+			F("target", target),
+			F("method", method),
+			F("code", status.Code(err).String()),
+			F("duration", time.Since(start)),
+		)
+		return err
+	}
+}
+
+// streamLoggingInterceptor logs stream establishment
+func streamLoggingInterceptor(serviceName, target string, logger Logger) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		logger.Log(callLevel(err), "grpc stream",
+			F("service", serviceName),
+			F("target", target),
+			F("method", method),
+			F("code", status.Code(err).String()),
+			F("duration", time.Since(start)),
+		)
+		return stream, err
+	}
+}
@@ -0,0 +1,107 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// Metric names recorded by GRPCClient when Options.EnableMetrics is set
+const (
+	MetricClientCalls           = "grpc_client_calls_total"
+	MetricClientCallDuration    = "grpc_client_call_duration_seconds"
+	MetricClientRetries         = "grpc_client_retries_total"
+	MetricClientHedges          = "grpc_client_hedges_total"
+	MetricClientHedgesWon       = "grpc_client_hedges_won_total"
+	MetricClientConnectionState = "grpc_client_connection_state"
+	MetricClientPoolSize        = "grpc_client_pool_size"
+)
+
+var connectionStates = []connectivity.State{
+	connectivity.Idle,
+	connectivity.Connecting,
+	connectivity.Ready,
+	connectivity.TransientFailure,
+	connectivity.Shutdown,
+}
+
+// clientMetrics records call and connection metrics for GRPCClient
+type clientMetrics struct {
+	provider metrics.Provider
+}
+
+func (m *clientMetrics) call(serviceName, method string, err error, duration time.Duration) {
+	m.provider.IncCounter(MetricClientCalls, 1, metrics.Labels{
+		"service": serviceName,
+		"method":  method,
+		"code":    status.Code(err).String(),
+	})
+	m.provider.ObserveHistogram(MetricClientCallDuration, duration.Seconds(), metrics.Labels{
+		"service": serviceName,
+		"method":  method,
+	})
+}
+
+func (m *clientMetrics) retry(serviceName, method string) {
+	m.provider.IncCounter(MetricClientRetries, 1, metrics.Labels{"service": serviceName, "method": method})
+}
+
+func (m *clientMetrics) hedge(serviceName, method string) {
+	m.provider.IncCounter(MetricClientHedges, 1, metrics.Labels{"service": serviceName, "method": method})
+}
+
+func (m *clientMetrics) hedgeWon(serviceName, method string) {
+	m.provider.IncCounter(MetricClientHedgesWon, 1, metrics.Labels{"service": serviceName, "method": method})
+}
+
+func (m *clientMetrics) poolSize(size int) {
+	m.provider.SetGauge(MetricClientPoolSize, float64(size), nil)
+}
+
+// connectionState sets the gauge of the current state to 1 and the others to 0
+func (m *clientMetrics) connectionState(serviceName string, current connectivity.State) {
+	for _, state := range connectionStates {
+		value := 0.0
+		if state == current {
+			value = 1
+		}
+		m.provider.SetGauge(MetricClientConnectionState, value, metrics.Labels{
+			"service": serviceName,
+			"state":   state.String(),
+		})
+	}
+}
+
+// watchConnection reports state changes until the connection shuts down
+func (m *clientMetrics) watchConnection(serviceName string, conn *grpc.ClientConn) {
+	for {
+		state := conn.GetState()
+		m.connectionState(serviceName, state)
+		if state == connectivity.Shutdown {
+			return
+		}
+		conn.WaitForStateChange(context.Background(), state)
+	}
+}
+
+func unaryMetricsInterceptor(serviceName string, m *clientMetrics) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(withRetryHook(ctx, func() { m.retry(serviceName, method) }), method, req, reply, cc, opts...)
+		m.call(serviceName, method, err, time.Since(start))
+		return err
+	}
+}
+
+func streamMetricsInterceptor(serviceName string, m *clientMetrics) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		m.call(serviceName, method, err, time.Since(start))
+		return stream, err
+	}
+}
@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// PoolConfig configures connection lifetime in GRPCClient. Eviction closes
+// the *grpc.ClientConn returned by GetServiceConnection, so enable it only
+// when callers build stubs on ServiceConn, which survives eviction.
+type PoolConfig struct {
+	// IdleTimeout closes connections with no calls for this long; 0 disables
+	IdleTimeout time.Duration
+	// MaxConnectionAge replaces connections older than this with a fresh dial,
+	// draining the old one; 0 disables
+	MaxConnectionAge time.Duration
+	// SweepInterval is how often idle and aged connections are checked
+	SweepInterval time.Duration
+	// DrainTimeout bounds the wait for in-flight calls when draining
+	DrainTimeout time.Duration
+}
+
+// DefaultPoolConfig returns the pool settings used by DefaultOptions;
+// eviction is off, set IdleTimeout or MaxConnectionAge to opt in
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		SweepInterval: time.Minute,
+		DrainTimeout:  30 * time.Second,
+	}
+}
+
+// ServiceConn returns a stable connection to serviceName for building stubs.
+// Every call goes through the currently pooled connection, so stubs created
+// once at startup keep working across eviction, ageing and registry changes.
+func (c *GRPCClient) ServiceConn(serviceName string) grpc.ClientConnInterface {
+	return &serviceHandle{client: c, service: serviceName}
+}
+
+// serviceHandle resolves the pooled connection per call
+type serviceHandle struct {
+	client  *GRPCClient
+	service string
+}
+
+// Invoke implements grpc.ClientConnInterface
+func (h *serviceHandle) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	for attempt := 0; ; attempt++ {
+		conn, err := h.client.GetServiceConnection(h.service)
+		if err != nil {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		err = conn.Invoke(ctx, method, args, reply, opts...)
+		if attempt == 0 && closedUnderneath(conn, err) {
+			continue
+		}
+		return err
+	}
+}
+
+// NewStream implements grpc.ClientConnInterface
+func (h *serviceHandle) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	for attempt := 0; ; attempt++ {
+		conn, err := h.client.GetServiceConnection(h.service)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+		stream, err := conn.NewStream(ctx, desc, method, opts...)
+		if attempt == 0 && closedUnderneath(conn, err) {
+			continue
+		}
+		return stream, err
+	}
+}
+
+// closedUnderneath reports whether a call failed because its connection was
+// evicted between lookup and use; such calls never reached the server
+func closedUnderneath(conn *grpc.ClientConn, err error) bool {
+	return status.Code(err) == codes.Canceled && conn.GetState() == connectivity.Shutdown
+}
+
+// drainPollInterval is how often a draining connection checks for in-flight calls
+const drainPollInterval = 50 * time.Millisecond
+
+// trackedStream marks the stream finished once it has ended
+type trackedStream struct {
+	grpc.ClientStream
+	once sync.Once
+	done func()
+}
+
+func (s *trackedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(s.done)
+	}
+	return err
+}
+
+// DrainService stops handing out the connection to a service, waits for
+// in-flight calls to finish or ctx to end, then closes it. The next call
+// dials a new connection.
+func (c *GRPCClient) DrainService(ctx context.Context, serviceName string) error {
+	c.mu.Lock()
+	sc, exists := c.conns[serviceName]
+	delete(c.conns, serviceName)
+	c.reportPoolSize()
+	c.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("no connection to %s", serviceName)
+	}
+	return c.drain(ctx, serviceName, sc)
+}
+
+func (c *GRPCClient) drain(ctx context.Context, serviceName string, sc *serviceConn) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	var waitErr error
+	for sc.inflight.Load() > 0 && waitErr == nil {
+		select {
+		case <-ctx.Done():
+			waitErr = ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	if err := sc.conn.Close(); err != nil {
+		c.options.Logger.Log(LogLevelWarn, "failed to close connection", F("service", serviceName), F("error", err))
+	}
+	if waitErr != nil {
+		return fmt.Errorf("closed %s with %d calls in flight: %w", serviceName, sc.inflight.Load(), waitErr)
+	}
+	return nil
+}
+
+// drainAsync drains sc in the background within the configured drain timeout
+func (c *GRPCClient) drainAsync(serviceName string, sc *serviceConn) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), c.options.Pool.DrainTimeout)
+		defer cancel()
+		if err := c.drain(ctx, serviceName, sc); err != nil {
+			c.options.Logger.Log(LogLevelWarn, "connection drain timed out", F("service", serviceName), F("error", err))
+		}
+	}()
+}
+
+// runSweeper evicts idle and aged connections until stop is closed
+func (c *GRPCClient) runSweeper(stop <-chan struct{}) {
+	ticker := time.NewTicker(c.options.Pool.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			c.sweep(now)
+		}
+	}
+}
+
+func (c *GRPCClient) sweep(now time.Time) {
+	pool := c.options.Pool
+
+	c.mu.Lock()
+	evicted := make(map[string]*serviceConn)
+	for name, sc := range c.conns {
+		idle := pool.IdleTimeout > 0 && sc.inflight.Load() == 0 && now.Sub(sc.lastUsed.Load()) > pool.IdleTimeout
+		aged := pool.MaxConnectionAge > 0 && now.Sub(sc.createdAt) > pool.MaxConnectionAge
+		if idle || aged {
+			evicted[name] = sc
+			delete(c.conns, name)
+		}
+	}
+	if len(evicted) > 0 {
+		c.reportPoolSize()
+	}
+	c.mu.Unlock()
+
+	for name, sc := range evicted {
+		c.options.Logger.Log(LogLevelDebug, "evicting connection", F("service", name), F("age", now.Sub(sc.createdAt)))
+		c.drainAsync(name, sc)
+	}
+}
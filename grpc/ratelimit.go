@@ -0,0 +1,377 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/grpcmd"
+	rediscommon "github.com/mihirk-khode/motocabz-common/redis"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryAfterHeader is the trailer carrying the retry-after hint in seconds
+const RetryAfterHeader = "retry-after"
+
+// RateLimit describes a token bucket: Rate tokens per second up to Burst
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitResult is the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// RateLimiter decides whether a request identified by key may proceed
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
+
+// RateLimitConfig configures the rate limiting interceptors
+type RateLimitConfig struct {
+	Limiter RateLimiter
+	// Default applies to methods without an entry in Methods; a zero Rate disables limiting
+	Default RateLimit
+	// Methods overrides the limit per full method name, e.g. "/trip.TripService/CreateTrip"
+	Methods map[string]RateLimit
+	// Global caps all methods and all callers together in one process-wide
+	// bucket, never scoped by CallerKey; it is checked last so calls rejected
+	// by a narrower limit don't drain it, and a zero Rate disables it
+	Global RateLimit
+	// PerCallerGlobal caps each caller across all methods; it is scoped by
+	// CallerKey and a zero Rate disables it
+	PerCallerGlobal RateLimit
+	// CallerKey is an incoming metadata key (e.g. grpcmd.KeyUserID) whose value
+	// gives each caller its own PerCallerGlobal and per-method buckets; calls
+	// without it share one bucket
+	CallerKey string
+	// FailOpen lets requests through when the limiter itself errors (e.g. Redis down)
+	FailOpen bool
+}
+
+func (c RateLimitConfig) limitFor(method string) RateLimit {
+	if limit, ok := c.Methods[method]; ok {
+		return limit
+	}
+	return c.Default
+}
+
+// bucketKey scopes key to the caller when CallerKey is set
+func (c RateLimitConfig) bucketKey(ctx context.Context, key string) string {
+	if c.CallerKey == "" {
+		return key
+	}
+	if caller := grpcmd.Get(ctx, c.CallerKey); caller != "" {
+		return key + ":" + caller
+	}
+	return key
+}
+
+func (c RateLimitConfig) check(ctx context.Context, method string) error {
+	if c.Limiter == nil {
+		return nil
+	}
+	// Narrowest bucket first: one caller hammering a method only spends its
+	// own tokens and never the shared global capacity
+	if err := c.take(ctx, c.bucketKey(ctx, method), c.limitFor(method), fmt.Sprintf("rate limit exceeded for %s", method)); err != nil {
+		return err
+	}
+	if err := c.take(ctx, c.bucketKey(ctx, "caller"), c.PerCallerGlobal, "rate limit exceeded"); err != nil {
+		return err
+	}
+	return c.take(ctx, "*", c.Global, "rate limit exceeded")
+}
+
+// take takes a token from the bucket for key, returning ResourceExhausted when empty
+func (c RateLimitConfig) take(ctx context.Context, key string, limit RateLimit, msg string) error {
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	result, err := c.Limiter.Allow(ctx, key, limit)
+	if err != nil {
+		if c.FailOpen {
+			return nil
+		}
+		return status.Errorf(codes.Unavailable, "rate limiter unavailable: %v", err)
+	}
+	if !result.Allowed {
+		return ResourceExhaustedError(ctx, msg, result.RetryAfter)
+	}
+	return nil
+}
+
+// UnaryRateLimitInterceptor rejects unary calls exceeding their rate limit
+func UnaryRateLimitInterceptor(cfg RateLimitConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := cfg.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor rejects new streams exceeding their rate limit
+func StreamRateLimitInterceptor(cfg RateLimitConfig) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := cfg.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// ConcurrencyConfig configures per-method in-flight request caps
+type ConcurrencyConfig struct {
+	// Default caps methods without an entry in Methods; zero means unlimited
+	Default int
+	// Methods overrides the cap per full method name
+	Methods map[string]int
+	// RetryAfter is the hint returned to rejected callers
+	RetryAfter time.Duration
+}
+
+// ConcurrencyLimiter caps the number of in-flight calls per method
+type ConcurrencyLimiter struct {
+	config ConcurrencyConfig
+	mu     sync.Mutex
+	slots  map[string]chan struct{}
+}
+
+// NewConcurrencyLimiter creates a new concurrency limiter
+func NewConcurrencyLimiter(config ConcurrencyConfig) *ConcurrencyLimiter {
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = 100 * time.Millisecond
+	}
+	return &ConcurrencyLimiter{
+		config: config,
+		slots:  make(map[string]chan struct{}),
+	}
+}
+
+// acquire reserves a slot for method, returning a release func or false when full
+func (cl *ConcurrencyLimiter) acquire(method string) (func(), bool) {
+	limit, ok := cl.config.Methods[method]
+	if !ok {
+		limit = cl.config.Default
+	}
+	if limit <= 0 {
+		return func() {}, true
+	}
+
+	cl.mu.Lock()
+	sem, exists := cl.slots[method]
+	if !exists {
+		sem = make(chan struct{}, limit)
+		cl.slots[method] = sem
+	}
+	cl.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, true
+	default:
+		return nil, false
+	}
+}
+
+// InFlight returns the number of in-flight calls for method
+func (cl *ConcurrencyLimiter) InFlight(method string) int {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if sem, ok := cl.slots[method]; ok {
+		return len(sem)
+	}
+	return 0
+}
+
+// UnaryServerInterceptor rejects unary calls once the method's cap is reached
+func (cl *ConcurrencyLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		release, ok := cl.acquire(info.FullMethod)
+		if !ok {
+			return nil, ResourceExhaustedError(ctx, fmt.Sprintf("too many concurrent requests for %s", info.FullMethod), cl.config.RetryAfter)
+		}
+		defer release()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects new streams once the method's cap is reached
+func (cl *ConcurrencyLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		release, ok := cl.acquire(info.FullMethod)
+		if !ok {
+			return ResourceExhaustedError(ss.Context(), fmt.Sprintf("too many concurrent streams for %s", info.FullMethod), cl.config.RetryAfter)
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
+
+// ResourceExhaustedError builds a ResourceExhausted status carrying RetryInfo
+// details and sets the retry-after trailer on the server call
+func ResourceExhaustedError(ctx context.Context, msg string, retryAfter time.Duration) error {
+	st := status.New(codes.ResourceExhausted, msg)
+	if retryAfter <= 0 {
+		return st.Err()
+	}
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterHeader, strconv.Itoa(seconds)))
+
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// RetryAfterFromError extracts the RetryInfo delay from a gRPC error
+func RetryAfterFromError(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.RetryDelay != nil {
+			return info.RetryDelay.AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
+// localSweepInterval is how often LocalRateLimiter drops refilled buckets
+const localSweepInterval = time.Minute
+
+// LocalRateLimiter is an in-process token bucket limiter. Buckets that have
+// refilled completely are dropped, since a fresh bucket behaves the same, so
+// per-caller keys don't accumulate forever.
+type LocalRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens   float64
+	lastFill time.Time
+	// fullAt is when the bucket will be back at burst if left untouched
+	fullAt time.Time
+}
+
+// NewLocalRateLimiter creates a new in-process rate limiter
+func NewLocalRateLimiter() *LocalRateLimiter {
+	return &LocalRateLimiter{
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// sweep drops buckets that have refilled; callers must hold l.mu
+func (l *LocalRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < localSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if !now.Before(bucket.fullAt) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Len returns the number of buckets currently tracked
+func (l *LocalRateLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+// Allow takes a token from the bucket identified by key
+func (l *LocalRateLimiter) Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.sweep(now)
+	bucket, exists := l.buckets[key]
+	if !exists {
+		bucket = &tokenBucket{tokens: burst, lastFill: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(burst, bucket.tokens+now.Sub(bucket.lastFill).Seconds()*limit.Rate)
+	bucket.lastFill = now
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+	bucket.fullAt = now.Add(time.Duration((burst - bucket.tokens) / limit.Rate * float64(time.Second)))
+
+	if allowed {
+		return RateLimitResult{Allowed: true, Remaining: int(bucket.tokens)}, nil
+	}
+	wait := time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+	return RateLimitResult{Allowed: false, RetryAfter: wait}, nil
+}
+
+// RedisRateLimiter is a token bucket limiter shared by all replicas through
+// Redis; it uses the token bucket of redis.RateLimiter
+type RedisRateLimiter struct {
+	limiter *rediscommon.RateLimiter
+}
+
+// NewRedisRateLimiter creates a distributed rate limiter; keys are stored under prefix
+func NewRedisRateLimiter(client redis.UniversalClient, prefix string) *RedisRateLimiter {
+	if prefix == "" {
+		prefix = "ratelimit:grpc:"
+	}
+	return &RedisRateLimiter{
+		limiter: rediscommon.NewRateLimiter(client, prefix),
+	}
+}
+
+// Allow takes a token from the shared bucket identified by key. A bucket of
+// Burst tokens refilling at Rate per second refills completely in Burst/Rate
+// seconds, which is the window of the equivalent redis.Limit.
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	burst := limit.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	res, err := l.limiter.Allow(ctx, key, rediscommon.Limit{
+		Algorithm: rediscommon.AlgorithmTokenBucket,
+		Limit:     int64(burst),
+		Window:    time.Duration(float64(burst) / limit.Rate * float64(time.Second)),
+	})
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	return RateLimitResult{
+		Allowed:    res.Allowed,
+		Remaining:  int(res.Remaining),
+		RetryAfter: res.RetryAfter,
+	}, nil
+}
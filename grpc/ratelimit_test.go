@@ -0,0 +1,86 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimitRejectedCallDoesNotDrainGlobal(t *testing.T) {
+	ctx := context.Background()
+	cfg := RateLimitConfig{
+		Limiter: NewLocalRateLimiter(),
+		Methods: map[string]RateLimit{"/svc/Hot": {Rate: 0.001, Burst: 1}},
+		Default: RateLimit{Rate: 0.001, Burst: 10},
+		Global:  RateLimit{Rate: 0.001, Burst: 2},
+	}
+
+	if err := cfg.check(ctx, "/svc/Hot"); err != nil {
+		t.Fatalf("first call rejected: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := cfg.check(ctx, "/svc/Hot"); status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("call %d: got %v, want ResourceExhausted", i, err)
+		}
+	}
+	if err := cfg.check(ctx, "/svc/Other"); err != nil {
+		t.Errorf("global bucket drained by rejected calls: %v", err)
+	}
+}
+
+func TestLocalRateLimiterDropsRefilledBuckets(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewLocalRateLimiter()
+	fast := RateLimit{Rate: 1000, Burst: 1}
+	slow := RateLimit{Rate: 0.001, Burst: 1}
+
+	for _, key := range []string{"a", "b", "c"} {
+		if _, err := limiter.Allow(ctx, key, fast); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := limiter.Allow(ctx, "slow", slow); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	limiter.mu.Lock()
+	limiter.lastSweep = time.Now().Add(-localSweepInterval)
+	limiter.mu.Unlock()
+
+	if _, err := limiter.Allow(ctx, "slow", slow); err != nil {
+		t.Fatal(err)
+	}
+	if n := limiter.Len(); n != 1 {
+		t.Errorf("Len = %d, want only the draining bucket kept", n)
+	}
+}
+
+func TestRedisRateLimiterSharesBucketAcrossReplicas(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	replicaA := NewRedisRateLimiter(client, "")
+	replicaB := NewRedisRateLimiter(client, "")
+	limit := RateLimit{Rate: 1, Burst: 2}
+
+	for i, limiter := range []*RedisRateLimiter{replicaA, replicaB} {
+		if res, err := limiter.Allow(ctx, "/trip.TripService/CreateTrip", limit); err != nil || !res.Allowed {
+			t.Fatalf("call %d = %+v, %v; want allowed", i, res, err)
+		}
+	}
+	res, err := replicaA.Allow(ctx, "/trip.TripService/CreateTrip", limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed || res.RetryAfter <= 0 || res.RetryAfter > time.Second {
+		t.Errorf("third call = %+v, want denied with a retry hint up to 1s", res)
+	}
+}
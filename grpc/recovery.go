@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"log"
+
+	"github.com/mihirk-khode/motocabz-common/crashreport"
+	"github.com/mihirk-khode/motocabz-common/grpcmd"
+	"github.com/mihirk-khode/motocabz-common/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PanicHandler is notified of every recovered panic, e.g. to page on-call
+type PanicHandler func(ctx context.Context, report crashreport.Report)
+
+// UnaryRecoveryInterceptor converts handler panics into codes.Internal,
+// forwards them to the crash reporter and then to handlers
+func UnaryRecoveryInterceptor(handlers ...PanicHandler) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				reportPanic(ctx, info.FullMethod, r, handlers)
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecoveryInterceptor is the streaming counterpart of UnaryRecoveryInterceptor
+func StreamRecoveryInterceptor(handlers ...PanicHandler) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				reportPanic(ss.Context(), info.FullMethod, r, handlers)
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func reportPanic(ctx context.Context, method string, recovered interface{}, handlers []PanicHandler) {
+	report := crashreport.NewReport(crashreport.SourceGRPC, method, recovered)
+	report.RequestID = grpcmd.RequestID(ctx)
+	report.TraceID = middleware.TraceIDFromHeader(grpcmd.Get(ctx, grpcmd.KeyTraceParent))
+	crashreport.Capture(ctx, report)
+
+	for _, handle := range handlers {
+		notifyPanic(ctx, handle, report)
+	}
+}
+
+// notifyPanic runs a handler, shielding the interceptor from a panicking handler
+func notifyPanic(ctx context.Context, handle PanicHandler, report crashreport.Report) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("⚠️ Panic handler for %s panicked: %v", report.Operation, r)
+		}
+	}()
+	handle(ctx, report)
+}
@@ -0,0 +1,201 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/mihirk-khode/motocabz-common/util/configwatch"
+)
+
+// EnvGRPCServices holds a JSON array of ServiceConfig merged into the registry
+const EnvGRPCServices = "GRPC_SERVICES"
+
+// ServiceChangeType describes how a registered service changed
+type ServiceChangeType string
+
+const (
+	ServiceAdded   ServiceChangeType = "added"
+	ServiceUpdated ServiceChangeType = "updated"
+	ServiceRemoved ServiceChangeType = "removed"
+)
+
+// ServiceChange is delivered to registry listeners
+type ServiceChange struct {
+	Type   ServiceChangeType
+	Name   string
+	Config ServiceConfig
+}
+
+// Registry holds service configurations that can change at runtime
+type Registry struct {
+	mu        sync.RWMutex
+	services  map[string]ServiceConfig
+	listeners []func(ServiceChange)
+}
+
+// NewRegistry creates a registry seeded with the given services
+func NewRegistry(seed map[string]ServiceConfig) *Registry {
+	r := &Registry{services: make(map[string]ServiceConfig, len(seed))}
+	for name, cfg := range seed {
+		if cfg.Name == "" {
+			cfg.Name = name
+		}
+		r.services[name] = cfg
+	}
+	return r
+}
+
+// DefaultRegistry is the process-wide registry, seeded from Services
+var DefaultRegistry = NewRegistry(Services)
+
+// Get returns the configuration of a service
+func (r *Registry) Get(name string) (ServiceConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cfg, ok := r.services[name]
+	return cfg, ok
+}
+
+// Names returns the registered service names, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.services))
+	for name := range r.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Register adds or replaces a service configuration
+func (r *Registry) Register(cfg ServiceConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("service name is required")
+	}
+	if !cfg.hasAddress() {
+		return fmt.Errorf("service %s: port, endpoints or a target host is required", cfg.Name)
+	}
+
+	r.mu.Lock()
+	old, exists := r.services[cfg.Name]
+	if exists && sameServiceConfig(old, cfg) {
+		r.mu.Unlock()
+		return nil
+	}
+	r.services[cfg.Name] = cfg
+	listeners := r.listeners
+	r.mu.Unlock()
+
+	change := ServiceChange{Type: ServiceAdded, Name: cfg.Name, Config: cfg}
+	if exists {
+		change.Type = ServiceUpdated
+	}
+	notify(listeners, change)
+	return nil
+}
+
+// Unregister removes a service and reports whether it was registered
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	old, exists := r.services[name]
+	if !exists {
+		r.mu.Unlock()
+		return false
+	}
+	delete(r.services, name)
+	listeners := r.listeners
+	r.mu.Unlock()
+
+	notify(listeners, ServiceChange{Type: ServiceRemoved, Name: name, Config: old})
+	return true
+}
+
+// OnChange registers a callback invoked after each registry change
+func (r *Registry) OnChange(fn func(ServiceChange)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// LoadJSON registers every service in a JSON array of ServiceConfig
+func (r *Registry) LoadJSON(data []byte) error {
+	var configs []ServiceConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("failed to decode service configs: %w", err)
+	}
+	return r.apply(configs)
+}
+
+// LoadFromEnv registers the services listed in GRPC_SERVICES, if set
+func (r *Registry) LoadFromEnv() error {
+	raw := os.Getenv(EnvGRPCServices)
+	if raw == "" {
+		return nil
+	}
+	if err := r.LoadJSON([]byte(raw)); err != nil {
+		return fmt.Errorf("invalid %s: %w", EnvGRPCServices, err)
+	}
+	return nil
+}
+
+// Watch binds key on a configuration watcher to the registry. Services in
+// the value are registered as they change; removals are left to Unregister
+// so a partial config update cannot drop a live service.
+func (r *Registry) Watch(w *configwatch.Watcher, key string) {
+	value := configwatch.Bind(w, key, validateServiceConfigs)
+	value.OnChange(func(_, configs []ServiceConfig) {
+		if err := r.apply(configs); err != nil {
+			log.Printf("⚠️ Failed to apply service configs from %s: %v", key, err)
+		}
+	})
+}
+
+func (r *Registry) apply(configs []ServiceConfig) error {
+	if err := validateServiceConfigs(configs); err != nil {
+		return err
+	}
+	for _, cfg := range configs {
+		if err := r.Register(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateServiceConfigs(configs []ServiceConfig) error {
+	for i, cfg := range configs {
+		if cfg.Name == "" {
+			return fmt.Errorf("service %d: name is required", i)
+		}
+		if !cfg.hasAddress() {
+			return fmt.Errorf("service %s: port, endpoints or a target host is required", cfg.Name)
+		}
+	}
+	return nil
+}
+
+func sameServiceConfig(a, b ServiceConfig) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func notify(listeners []func(ServiceChange), change ServiceChange) {
+	for _, fn := range listeners {
+		fn(change)
+	}
+}
+
+// RegisterService adds or replaces a service in the default registry
+func RegisterService(cfg ServiceConfig) error {
+	return DefaultRegistry.Register(cfg)
+}
+
+// UnregisterService removes a service from the default registry
+func UnregisterService(name string) bool {
+	return DefaultRegistry.Unregister(name)
+}
@@ -0,0 +1,198 @@
+package grpc
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy configures retries of failed unary calls
+type RetryPolicy struct {
+	// MaxAttempts includes the first call; 1 or less disables retries
+	MaxAttempts int
+	// InitialBackoff and MaxBackoff bound the exponential delay between attempts
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter randomises each delay by up to this fraction, in [0, 1]
+	Jitter float64
+	// MaxElapsed stops retrying once this much time has passed since the first attempt
+	MaxElapsed time.Duration
+	// RetryableCodes are the status codes worth retrying
+	RetryableCodes []codes.Code
+	// BudgetRatio is the number of retries earned by each call, e.g. 0.1 allows
+	// retries on about 10% of traffic; 0 disables the budget
+	BudgetRatio float64
+	// BudgetBurst is the number of retries available before any are earned
+	BudgetBurst float64
+}
+
+// DefaultRetryPolicy returns a recommended retry policy. DefaultOptions leaves
+// retries off; set Options.Retry to this only for clients whose unary calls
+// are safe to repeat.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxElapsed:     10 * time.Second,
+		RetryableCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted},
+		BudgetRatio:    0.1,
+		BudgetBurst:    10,
+	}
+}
+
+// Retryable reports whether err has one of the retryable codes
+func (p RetryPolicy) Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	code := status.Code(err)
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the delay before the given retry, starting at 1
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		delay *= multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// RetryBudget caps retries to a fraction of calls so retries cannot
+// multiply load on a struggling service
+type RetryBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	burst  float64
+	tokens float64
+}
+
+// NewRetryBudget creates a budget earning ratio retries per call, holding at most burst
+func NewRetryBudget(ratio, burst float64) *RetryBudget {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RetryBudget{ratio: ratio, burst: burst, tokens: burst}
+}
+
+// Deposit records a call
+func (b *RetryBudget) Deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += b.ratio
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// Withdraw reports whether a retry may proceed, spending one token
+func (b *RetryBudget) Withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+type retryHookKey struct{}
+
+// withRetryHook attaches a callback that Retrier.Do invokes before each retry
+func withRetryHook(ctx context.Context, fn func()) context.Context {
+	return context.WithValue(ctx, retryHookKey{}, fn)
+}
+
+// Retrier runs operations under a retry policy and budget
+type Retrier struct {
+	policy RetryPolicy
+	budget *RetryBudget
+}
+
+// NewRetrier creates a retrier with its own budget
+func NewRetrier(policy RetryPolicy) *Retrier {
+	r := &Retrier{policy: policy}
+	if policy.BudgetRatio > 0 {
+		r.budget = NewRetryBudget(policy.BudgetRatio, policy.BudgetBurst)
+	}
+	return r
+}
+
+// Do runs op until it succeeds, returns a non-retryable error, or the policy
+// or budget is exhausted
+func (r *Retrier) Do(ctx context.Context, op func(ctx context.Context) error) error {
+	r.budget.Deposit()
+
+	start := time.Now()
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = op(ctx)
+		if err == nil || !r.policy.Retryable(err) || attempt >= r.policy.MaxAttempts {
+			return err
+		}
+
+		delay := r.policy.Backoff(attempt)
+		if r.policy.MaxElapsed > 0 && time.Since(start)+delay > r.policy.MaxElapsed {
+			return err
+		}
+		if !r.budget.Withdraw() {
+			return err
+		}
+
+		if hook, ok := ctx.Value(retryHookKey{}).(func()); ok {
+			hook()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// unaryRetryInterceptor retries unary calls; streams are never retried
+func unaryRetryInterceptor(retrier *Retrier) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return retrier.Do(ctx, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/grpcmd"
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Metric names recorded by server interceptors
+const (
+	MetricServerCalls        = "grpc_server_calls_total"
+	MetricServerCallDuration = "grpc_server_call_duration_seconds"
+)
+
+// ServerAuthFunc authenticates an incoming call and may enrich its context
+type ServerAuthFunc func(ctx context.Context, fullMethod string) (context.Context, error)
+
+// ServerConfig selects the interceptors composed by NewServerOptions
+type ServerConfig struct {
+	// Tracing ensures a request ID and trace context on every call
+	Tracing bool
+	// Recovery converts handler panics into codes.Internal
+	Recovery bool
+	// OnPanic is notified of recovered panics when Recovery is enabled
+	OnPanic PanicHandler
+	// Validation rejects requests whose Validate method fails
+	Validation bool
+	// Auth authenticates calls; nil disables authentication
+	Auth ServerAuthFunc
+	// AuthExempt lists full method names that skip Auth, e.g. health checks
+	AuthExempt []string
+	// Logger logs every call; nil disables logging
+	Logger Logger
+	// Metrics records call counters and latency; nil disables metrics
+	Metrics metrics.Provider
+	// Audit records payload sizes and remaining deadlines; nil disables it
+	Audit *AuditConfig
+	// RateLimit and Concurrency are applied after recovery when set
+	RateLimit   *RateLimitConfig
+	Concurrency *ConcurrencyLimiter
+	// Unary and Stream are appended after the built-in interceptors
+	Unary  []grpc.UnaryServerInterceptor
+	Stream []grpc.StreamServerInterceptor
+}
+
+// DefaultServerConfig enables tracing, recovery and validation
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Tracing:    true,
+		Recovery:   true,
+		Validation: true,
+	}
+}
+
+// NewServerOptions composes the configured interceptors in a fixed order:
+// tracing, metrics, logging, payload audit, recovery, rate limiting, concurrency, auth,
+// validation, then any extra interceptors.
+func NewServerOptions(cfg ServerConfig) []grpc.ServerOption {
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+
+	if cfg.Tracing {
+		unary = append(unary, grpcmd.UnaryServerInterceptor())
+		stream = append(stream, grpcmd.StreamServerInterceptor())
+	}
+	if cfg.Metrics != nil {
+		unary = append(unary, unaryServerMetricsInterceptor(cfg.Metrics))
+		stream = append(stream, streamServerMetricsInterceptor(cfg.Metrics))
+	}
+	if cfg.Logger != nil {
+		unary = append(unary, unaryServerLoggingInterceptor(cfg.Logger))
+		stream = append(stream, streamServerLoggingInterceptor(cfg.Logger))
+	}
+	if cfg.Audit != nil {
+		unary = append(unary, UnaryServerAuditInterceptor(*cfg.Audit))
+	}
+	if cfg.Recovery {
+		var handlers []PanicHandler
+		if cfg.OnPanic != nil {
+			handlers = append(handlers, cfg.OnPanic)
+		}
+		unary = append(unary, UnaryRecoveryInterceptor(handlers...))
+		stream = append(stream, StreamRecoveryInterceptor(handlers...))
+	}
+	if cfg.RateLimit != nil {
+		unary = append(unary, UnaryRateLimitInterceptor(*cfg.RateLimit))
+		stream = append(stream, StreamRateLimitInterceptor(*cfg.RateLimit))
+	}
+	if cfg.Concurrency != nil {
+		unary = append(unary, cfg.Concurrency.UnaryServerInterceptor())
+		stream = append(stream, cfg.Concurrency.StreamServerInterceptor())
+	}
+	if cfg.Auth != nil {
+		unary = append(unary, unaryServerAuthInterceptor(cfg.Auth, cfg.AuthExempt))
+		stream = append(stream, streamServerAuthInterceptor(cfg.Auth, cfg.AuthExempt))
+	}
+	if cfg.Validation {
+		unary = append(unary, UnaryValidationInterceptor())
+	}
+	unary = append(unary, cfg.Unary...)
+	stream = append(stream, cfg.Stream...)
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+}
+
+// validator is implemented by requests that can check themselves
+type validator interface {
+	Validate() error
+}
+
+// UnaryValidationInterceptor rejects requests whose Validate method fails
+// with codes.InvalidArgument
+func UnaryValidationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if v, ok := req.(validator); ok {
+			if err := v.Validate(); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authExempt(method string, exempt []string) bool {
+	for _, m := range exempt {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func unaryServerAuthInterceptor(auth ServerAuthFunc, exempt []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if authExempt(info.FullMethod, exempt) {
+			return handler(ctx, req)
+		}
+		ctx, err := auth(ctx, info.FullMethod)
+		if err != nil {
+			return nil, authError(err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func streamServerAuthInterceptor(auth ServerAuthFunc, exempt []string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if authExempt(info.FullMethod, exempt) {
+			return handler(srv, ss)
+		}
+		ctx, err := auth(ss.Context(), info.FullMethod)
+		if err != nil {
+			return authError(err)
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authError keeps status errors from the auth func and maps others to Unauthenticated
+func authError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Unauthenticated, err.Error())
+}
+
+func unaryServerLoggingInterceptor(logger Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logger.Log(callLevel(err), "grpc request",
+			F("method", info.FullMethod),
+			F("code", status.Code(err).String()),
+			F("duration", time.Since(start)),
+			F("requestId", grpcmd.RequestID(ctx)),
+		)
+		return resp, err
+	}
+}
+
+func streamServerLoggingInterceptor(logger Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logger.Log(callLevel(err), "grpc stream",
+			F("method", info.FullMethod),
+			F("code", status.Code(err).String()),
+			F("duration", time.Since(start)),
+			F("requestId", grpcmd.RequestID(ss.Context())),
+		)
+		return err
+	}
+}
+
+func recordServerCall(provider metrics.Provider, method string, err error, duration time.Duration) {
+	provider.IncCounter(MetricServerCalls, 1, metrics.Labels{"method": method, "code": status.Code(err).String()})
+	provider.ObserveHistogram(MetricServerCallDuration, duration.Seconds(), metrics.Labels{"method": method})
+}
+
+func unaryServerMetricsInterceptor(provider metrics.Provider) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		recordServerCall(provider, info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+func streamServerMetricsInterceptor(provider metrics.Provider) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		recordServerCall(provider, info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+// serverStream overrides the context of a server stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
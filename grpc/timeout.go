@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+	"google.golang.org/grpc"
+)
+
+// TimeoutPolicy sets default deadlines on outgoing calls. The most specific
+// timeout wins: Methods, then ServiceConfig.Timeout, then Default. A caller
+// deadline that is already shorter is kept.
+type TimeoutPolicy struct {
+	// Default applies to unary calls; the shared request timeout when zero
+	Default time.Duration
+	// Methods maps full method names (/package.Service/Method) to timeouts.
+	// Streams only get a deadline when listed here.
+	Methods map[string]time.Duration
+}
+
+// unaryTimeout returns the timeout for a unary call
+func (p TimeoutPolicy) unaryTimeout(service ServiceConfig, method string) time.Duration {
+	if timeout, ok := p.Methods[method]; ok {
+		return timeout
+	}
+	if service.Timeout > 0 {
+		return service.Timeout
+	}
+	if p.Default > 0 {
+		return p.Default
+	}
+	return common.GetTimeouts().RequestTimeout
+}
+
+// withTimeout applies timeout unless ctx already has an earlier deadline
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func unaryTimeoutInterceptor(service ServiceConfig, policy TimeoutPolicy) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, cancel := withTimeout(ctx, policy.unaryTimeout(service, method))
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// streamTimeoutInterceptor bounds streams listed in TimeoutPolicy.Methods;
+// the deadline covers the whole stream, not just its setup
+func streamTimeoutInterceptor(policy TimeoutPolicy) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		timeout, ok := policy.Methods[method]
+		if !ok {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		ctx, cancel := withTimeout(ctx, timeout)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		return &trackedStream{ClientStream: stream, done: cancel}, nil
+	}
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/grpcmd"
+	"google.golang.org/grpc"
+)
+
+// Transport selects how calls reach a service
+type Transport string
+
+const (
+	// TransportDirect dials the service target directly
+	TransportDirect Transport = "direct"
+	// TransportDapr proxies calls through the local Dapr sidecar, which
+	// provides mTLS, retries and service discovery
+	TransportDapr Transport = "dapr"
+)
+
+// keyDaprAppID routes a proxied call to the target app
+const keyDaprAppID = "dapr-app-id"
+
+// defaultDaprGRPCPort is the sidecar gRPC port when DAPR_GRPC_PORT is unset
+const defaultDaprGRPCPort = "50001"
+
+// transport returns the service transport, falling back to the client default
+func (s ServiceConfig) transport(fallback Transport) Transport {
+	if s.Transport != "" {
+		return s.Transport
+	}
+	if fallback != "" {
+		return fallback
+	}
+	return TransportDirect
+}
+
+// appID returns the Dapr app ID of the service
+func (s ServiceConfig) appID() string {
+	if s.AppID != "" {
+		return s.AppID
+	}
+	return s.Name
+}
+
+// daprTarget returns the address of the local sidecar
+func daprTarget() string {
+	port := os.Getenv(common.EnvDaprGRPCPort)
+	if port == "" {
+		port = defaultDaprGRPCPort
+	}
+	return fmt.Sprintf("localhost:%s", port)
+}
+
+func unaryDaprInterceptor(appID string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(grpcmd.Set(ctx, keyDaprAppID, appID), method, req, reply, cc, opts...)
+	}
+}
+
+func streamDaprInterceptor(appID string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(grpcmd.Set(ctx, keyDaprAppID, appID), desc, cc, method, opts...)
+	}
+}
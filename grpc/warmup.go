@@ -0,0 +1,153 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+// WarmUpConfig configures InitializeAllConnections
+type WarmUpConfig struct {
+	// Services to connect; every registered service when empty
+	Services []string
+	// Optional services are reported but do not fail initialization
+	Optional []string
+	// Concurrency caps parallel dials
+	Concurrency int
+	// Timeout bounds the wait for each connection to become ready
+	Timeout time.Duration
+}
+
+// ServiceWarmUpResult is the outcome of warming up one service
+type ServiceWarmUpResult struct {
+	Service  string        `json:"service"`
+	Target   string        `json:"target,omitempty"`
+	Required bool          `json:"required"`
+	Ready    bool          `json:"ready"`
+	State    string        `json:"state,omitempty"`
+	Latency  time.Duration `json:"latency"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// InitializationReport summarises a warm-up run
+type InitializationReport struct {
+	Results  []ServiceWarmUpResult `json:"results"`
+	Duration time.Duration         `json:"duration"`
+}
+
+// Ready reports whether every required service is ready
+func (r InitializationReport) Ready() bool {
+	return len(r.FailedRequired()) == 0
+}
+
+// FailedRequired returns the required services that are not ready
+func (r InitializationReport) FailedRequired() []ServiceWarmUpResult {
+	var failed []ServiceWarmUpResult
+	for _, result := range r.Results {
+		if result.Required && !result.Ready {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// InitializeAllConnections dials services with bounded concurrency and waits
+// for each to become ready. It returns an error when a required service is
+// not ready; the report is complete either way.
+func (c *GRPCClient) InitializeAllConnections(ctx context.Context, cfg WarmUpConfig) (InitializationReport, error) {
+	if len(cfg.Services) == 0 {
+		cfg.Services = c.options.Registry.Names()
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 4
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	optional := make(map[string]bool, len(cfg.Optional))
+	for _, name := range cfg.Optional {
+		optional[name] = true
+	}
+
+	start := time.Now()
+	results := make([]ServiceWarmUpResult, len(cfg.Services))
+	sem := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, name := range cfg.Services {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = c.warmUp(ctx, name, cfg.Timeout)
+			results[i].Required = !optional[name]
+		}(i, name)
+	}
+	wg.Wait()
+
+	report := InitializationReport{Results: results, Duration: time.Since(start)}
+	for _, result := range results {
+		level := LogLevelInfo
+		if !result.Ready {
+			level = LogLevelWarn
+		}
+		c.options.Logger.Log(level, "service warm-up",
+			F("service", result.Service),
+			F("ready", result.Ready),
+			F("required", result.Required),
+			F("state", result.State),
+			F("latency", result.Latency),
+		)
+	}
+
+	if failed := report.FailedRequired(); len(failed) > 0 {
+		names := make([]string, len(failed))
+		for i, result := range failed {
+			names[i] = fmt.Sprintf("%s (%s)", result.Service, result.Error)
+		}
+		return report, fmt.Errorf("required services not ready: %s", strings.Join(names, ", "))
+	}
+	return report, nil
+}
+
+// warmUp dials a service and waits until it is ready or timeout elapses
+func (c *GRPCClient) warmUp(ctx context.Context, serviceName string, timeout time.Duration) ServiceWarmUpResult {
+	start := time.Now()
+	result := ServiceWarmUpResult{Service: serviceName}
+
+	conn, err := c.GetServiceConnection(serviceName)
+	if err != nil {
+		result.Latency = time.Since(start)
+		result.Error = err.Error()
+		return result
+	}
+	if info, ok := c.GetConnectionInfo(serviceName); ok {
+		result.Target = info.Target
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn.Connect()
+	state := conn.GetState()
+	for state != connectivity.Ready && state != connectivity.Shutdown {
+		if !conn.WaitForStateChange(ctx, state) {
+			break
+		}
+		state = conn.GetState()
+	}
+
+	result.Latency = time.Since(start)
+	result.State = state.String()
+	result.Ready = state == connectivity.Ready
+	if !result.Ready {
+		result.Error = fmt.Sprintf("connection %s after %v", strings.ToLower(state.String()), timeout)
+	}
+	return result
+}
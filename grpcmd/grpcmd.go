@@ -0,0 +1,185 @@
+package grpcmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Canonical metadata keys shared by all services. gRPC metadata keys are
+// lower-case; always use these constants instead of string literals.
+const (
+	KeyRequestID   = "x-request-id"
+	KeyUserID      = "x-user-id"
+	KeyUserRole    = "x-user-role"
+	KeyTenant      = "x-tenant"
+	KeyLocale      = "x-locale"
+	KeyTraceParent = "traceparent"
+	KeyTraceState  = "tracestate"
+	// KeyAuthorization carries bearer tokens; it is never copied by Propagate
+	KeyAuthorization = "authorization"
+)
+
+// PropagatedKeys are copied from incoming server metadata to outgoing client calls
+var PropagatedKeys = []string{
+	KeyRequestID,
+	KeyUserID,
+	KeyUserRole,
+	KeyTenant,
+	KeyLocale,
+	KeyTraceParent,
+	KeyTraceState,
+}
+
+// Get returns the first incoming metadata value for key
+func Get(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	return first(md, key)
+}
+
+// GetOutgoing returns the first outgoing metadata value for key
+func GetOutgoing(ctx context.Context, key string) string {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return ""
+	}
+	return first(md, key)
+}
+
+// Set attaches key=value to the outgoing metadata, replacing any previous value
+func Set(ctx context.Context, key, value string) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	md.Set(strings.ToLower(key), value)
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// RequestID returns the incoming request ID
+func RequestID(ctx context.Context) string { return Get(ctx, KeyRequestID) }
+
+// UserID returns the incoming user ID
+func UserID(ctx context.Context) string { return Get(ctx, KeyUserID) }
+
+// UserRole returns the incoming user role
+func UserRole(ctx context.Context) string { return Get(ctx, KeyUserRole) }
+
+// Tenant returns the incoming tenant
+func Tenant(ctx context.Context) string { return Get(ctx, KeyTenant) }
+
+// Locale returns the incoming locale
+func Locale(ctx context.Context) string { return Get(ctx, KeyLocale) }
+
+// WithRequestID sets the outgoing request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return Set(ctx, KeyRequestID, id)
+}
+
+// WithUser sets the outgoing user ID and role
+func WithUser(ctx context.Context, userID, role string) context.Context {
+	ctx = Set(ctx, KeyUserID, userID)
+	return Set(ctx, KeyUserRole, role)
+}
+
+// WithTenant sets the outgoing tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return Set(ctx, KeyTenant, tenant)
+}
+
+// WithLocale sets the outgoing locale
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return Set(ctx, KeyLocale, locale)
+}
+
+// Propagate copies well-known incoming keys to the outgoing metadata.
+// Values already set on the outgoing context take precedence.
+func Propagate(ctx context.Context) context.Context {
+	incoming, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+	outgoing = outgoing.Copy()
+
+	changed := false
+	for _, key := range PropagatedKeys {
+		if len(outgoing.Get(key)) > 0 {
+			continue
+		}
+		if values := incoming.Get(key); len(values) > 0 {
+			outgoing.Set(key, values...)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return ctx
+	}
+	return metadata.NewOutgoingContext(ctx, outgoing)
+}
+
+// UnaryClientInterceptor propagates well-known metadata on outgoing unary calls
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(Propagate(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor propagates well-known metadata on outgoing streams
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(Propagate(ctx), desc, cc, method, opts...)
+	}
+}
+
+// UnaryServerInterceptor guarantees every incoming call carries a request ID
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(ensureRequestID(ctx), req)
+	}
+}
+
+// StreamServerInterceptor guarantees every incoming stream carries a request ID
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ensureRequestID(ss.Context())
+		if ctx == ss.Context() {
+			return handler(srv, ss)
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// ensureRequestID adds a generated request ID to the incoming metadata when missing
+func ensureRequestID(ctx context.Context) context.Context {
+	if RequestID(ctx) != "" {
+		return ctx
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Set(KeyRequestID, uuid.NewString())
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+// contextStream overrides the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
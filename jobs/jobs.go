@@ -0,0 +1,429 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+// Metric names emitted by the queue
+const (
+	MetricJobsEnqueued  = "jobs_enqueued_total"
+	MetricJobsProcessed = "jobs_processed_total"
+	MetricJobDuration   = "jobs_duration_seconds"
+	MetricQueueDepth    = "jobs_queue_depth"
+)
+
+// Job outcomes reported in the status label
+const (
+	StatusSucceeded = "succeeded"
+	StatusRetried   = "retried"
+	StatusDead      = "dead"
+)
+
+// Job is a unit of work stored in Redis
+type Job struct {
+	ID          string          `json:"id"`
+	Queue       string          `json:"queue"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	EnqueuedAt  time.Time       `json:"enqueuedAt"`
+	LastError   string          `json:"lastError,omitempty"`
+}
+
+// Decode unmarshals the job payload into v
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// Handler processes a job; returning an error schedules a retry
+type Handler func(ctx context.Context, job *Job) error
+
+// Config configures a job queue
+type Config struct {
+	Queue       string
+	Concurrency int
+	MaxAttempts int
+	// BaseBackoff and MaxBackoff bound the exponential retry delay
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// VisibilityTimeout is how long a job may run before it is handed to another worker
+	VisibilityTimeout time.Duration
+	PollInterval      time.Duration
+	KeyPrefix         string
+	Metrics           metrics.Provider
+}
+
+// Stats reports the number of jobs in each state
+type Stats struct {
+	Ready      int64 `json:"ready"`
+	Delayed    int64 `json:"delayed"`
+	Processing int64 `json:"processing"`
+	Dead       int64 `json:"dead"`
+}
+
+// Queue is a Redis backed job queue with delayed jobs, retries and a dead-letter list
+type Queue struct {
+	client   redis.UniversalClient
+	config   Config
+	metrics  metrics.Provider
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+// ErrNoHandler is recorded on jobs whose type has no registered handler
+var ErrNoHandler = errors.New("no handler registered for job type")
+
+// popScript moves the next ready job into the processing set with a visibility deadline
+var popScript = redis.NewScript(`
+local job = redis.call('RPOP', KEYS[1])
+if not job then return false end
+redis.call('ZADD', KEYS[2], ARGV[1], job)
+return job
+`)
+
+// promoteScript moves due members of a sorted set onto the ready list
+var promoteScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, job in ipairs(due) do
+  redis.call('ZREM', KEYS[1], job)
+  redis.call('LPUSH', KEYS[2], job)
+end
+return #due
+`)
+
+// NewQueue creates a new job queue
+func NewQueue(client redis.UniversalClient, config Config) *Queue {
+	if config.Queue == "" {
+		config.Queue = "default"
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = 10 * time.Minute
+	}
+	if config.VisibilityTimeout <= 0 {
+		config.VisibilityTimeout = 5 * time.Minute
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 500 * time.Millisecond
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "jobs:"
+	}
+
+	return &Queue{
+		client:   client,
+		config:   config,
+		metrics:  metrics.OrDefault(config.Metrics),
+		handlers: make(map[string]Handler),
+	}
+}
+
+// key hash-tags the queue name so the scripts and transactions moving jobs
+// between its lists and sets stay in one Redis Cluster slot
+func (q *Queue) key(suffix string) string {
+	return "{" + q.config.KeyPrefix + q.config.Queue + "}:" + suffix
+}
+
+func (q *Queue) readyKey() string      { return q.key("ready") }
+func (q *Queue) delayedKey() string    { return q.key("delayed") }
+func (q *Queue) processingKey() string { return q.key("processing") }
+func (q *Queue) deadKey() string       { return q.key("dead") }
+
+// Handle registers the handler for a job type
+func (q *Queue) Handle(jobType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Enqueue adds a job for immediate processing
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}) (*Job, error) {
+	return q.EnqueueIn(ctx, 0, jobType, payload)
+}
+
+// EnqueueIn adds a job that becomes ready after delay
+func (q *Queue) EnqueueIn(ctx context.Context, delay time.Duration, jobType string, payload interface{}) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	job := &Job{
+		ID:          uuid.NewString(),
+		Queue:       q.config.Queue,
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: q.config.MaxAttempts,
+		EnqueuedAt:  time.Now().UTC(),
+	}
+
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	if delay > 0 {
+		err = q.client.ZAdd(ctx, q.delayedKey(), redis.Z{
+			Score:  float64(time.Now().Add(delay).UnixMilli()),
+			Member: raw,
+		}).Err()
+	} else {
+		err = q.client.LPush(ctx, q.readyKey(), raw).Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue %s job: %w", jobType, err)
+	}
+
+	q.metrics.IncCounter(MetricJobsEnqueued, 1, metrics.Labels{"queue": q.config.Queue, "type": jobType})
+	return job, nil
+}
+
+// Run starts the worker pool and blocks until ctx is cancelled
+func (q *Queue) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		q.runPromoter(ctx)
+	}()
+
+	for i := 0; i < q.config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.runWorker(ctx)
+		}()
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+// runPromoter moves due delayed jobs and timed-out processing jobs to the ready list
+func (q *Queue) runPromoter(ctx context.Context) {
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			for _, source := range []string{q.delayedKey(), q.processingKey()} {
+				if err := promoteScript.Run(ctx, q.client, []string{source, q.readyKey()}, now, 100).Err(); err != nil && ctx.Err() == nil {
+					log.Printf("Job queue %s: failed to promote jobs from %s: %v", q.config.Queue, source, err)
+				}
+			}
+			if stats, err := q.Stats(ctx); err == nil {
+				q.reportDepth(stats)
+			}
+		}
+	}
+}
+
+func (q *Queue) runWorker(ctx context.Context) {
+	for ctx.Err() == nil {
+		deadline := time.Now().Add(q.config.VisibilityTimeout).UnixMilli()
+		raw, err := popScript.Run(ctx, q.client, []string{q.readyKey(), q.processingKey()}, deadline).Text()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) && ctx.Err() == nil {
+				log.Printf("Job queue %s: failed to fetch job: %v", q.config.Queue, err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(q.config.PollInterval):
+			}
+			continue
+		}
+
+		q.process(ctx, raw)
+	}
+}
+
+func (q *Queue) process(ctx context.Context, raw string) {
+	var job Job
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		log.Printf("Job queue %s: dropping malformed job: %v", q.config.Queue, err)
+		q.client.ZRem(ctx, q.processingKey(), raw)
+		return
+	}
+
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	start := time.Now()
+	var err error
+	if !ok {
+		err = fmt.Errorf("%w: %s", ErrNoHandler, job.Type)
+	} else {
+		err = q.safeHandle(ctx, handler, &job)
+	}
+	q.metrics.ObserveHistogram(MetricJobDuration, time.Since(start).Seconds(), metrics.Labels{"queue": job.Queue, "type": job.Type})
+
+	if err == nil {
+		q.client.ZRem(ctx, q.processingKey(), raw)
+		q.metrics.IncCounter(MetricJobsProcessed, 1, metrics.Labels{"queue": job.Queue, "type": job.Type, "status": StatusSucceeded})
+		return
+	}
+
+	job.Attempts++
+	job.LastError = err.Error()
+	if err := q.retryOrBury(ctx, raw, &job); err != nil {
+		log.Printf("Job queue %s: failed to reschedule job %s: %v", q.config.Queue, job.ID, err)
+	}
+}
+
+// safeHandle runs handler, converting a panic into an error
+func (q *Queue) safeHandle(ctx context.Context, handler Handler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// retryOrBury schedules the job for another attempt or moves it to the dead-letter list
+func (q *Queue) retryOrBury(ctx context.Context, raw string, job *Job) error {
+	updated, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	status := StatusRetried
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, q.processingKey(), raw)
+		if job.Attempts >= job.MaxAttempts {
+			status = StatusDead
+			pipe.LPush(ctx, q.deadKey(), updated)
+			return nil
+		}
+		pipe.ZAdd(ctx, q.delayedKey(), redis.Z{
+			Score:  float64(time.Now().Add(q.backoff(job.Attempts)).UnixMilli()),
+			Member: updated,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Job queue %s: job %s (%s) failed attempt %d/%d: %s",
+		q.config.Queue, job.ID, job.Type, job.Attempts, job.MaxAttempts, job.LastError)
+	q.metrics.IncCounter(MetricJobsProcessed, 1, metrics.Labels{"queue": job.Queue, "type": job.Type, "status": status})
+	return nil
+}
+
+// backoff returns the exponential delay with jitter for the given attempt
+func (q *Queue) backoff(attempt int) time.Duration {
+	delay := q.config.BaseBackoff << uint(attempt-1)
+	if delay <= 0 || delay > q.config.MaxBackoff {
+		delay = q.config.MaxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	return delay/2 + jitter
+}
+
+// Stats returns the number of jobs in each state
+func (q *Queue) Stats(ctx context.Context) (Stats, error) {
+	var ready, delayed, processing, dead *redis.IntCmd
+	_, err := q.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		ready = pipe.LLen(ctx, q.readyKey())
+		delayed = pipe.ZCard(ctx, q.delayedKey())
+		processing = pipe.ZCard(ctx, q.processingKey())
+		dead = pipe.LLen(ctx, q.deadKey())
+		return nil
+	})
+	if err != nil {
+		return Stats{}, fmt.Errorf("failed to get stats for queue %s: %w", q.config.Queue, err)
+	}
+
+	return Stats{
+		Ready:      ready.Val(),
+		Delayed:    delayed.Val(),
+		Processing: processing.Val(),
+		Dead:       dead.Val(),
+	}, nil
+}
+
+func (q *Queue) reportDepth(stats Stats) {
+	for state, depth := range map[string]int64{
+		"ready":      stats.Ready,
+		"delayed":    stats.Delayed,
+		"processing": stats.Processing,
+		"dead":       stats.Dead,
+	} {
+		q.metrics.SetGauge(MetricQueueDepth, float64(depth), metrics.Labels{"queue": q.config.Queue, "state": state})
+	}
+}
+
+// DeadLetters returns up to limit jobs from the dead-letter list
+func (q *Queue) DeadLetters(ctx context.Context, limit int64) ([]*Job, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	raws, err := q.client.LRange(ctx, q.deadKey(), 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters for %s: %w", q.config.Queue, err)
+	}
+
+	jobs := make([]*Job, 0, len(raws))
+	for _, raw := range raws {
+		var job Job
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// RequeueDeadLetters moves up to limit dead jobs back to the ready list with a fresh attempt budget
+func (q *Queue) RequeueDeadLetters(ctx context.Context, limit int) (int, error) {
+	moved := 0
+	for moved < limit {
+		raw, err := q.client.RPop(ctx, q.deadKey()).Result()
+		if errors.Is(err, redis.Nil) {
+			break
+		}
+		if err != nil {
+			return moved, fmt.Errorf("failed to requeue dead letters for %s: %w", q.config.Queue, err)
+		}
+
+		var job Job
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			continue
+		}
+		job.Attempts = 0
+		updated, _ := json.Marshal(job)
+		if err := q.client.LPush(ctx, q.readyKey(), updated).Err(); err != nil {
+			return moved, fmt.Errorf("failed to requeue job %s: %w", job.ID, err)
+		}
+		moved++
+	}
+	return moved, nil
+}
@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// runOnce pops the next ready job and processes it like a worker would
+func runOnce(t *testing.T, q *Queue) {
+	t.Helper()
+	ctx := context.Background()
+	deadline := time.Now().Add(q.config.VisibilityTimeout).UnixMilli()
+	raw, err := popScript.Run(ctx, q.client, []string{q.readyKey(), q.processingKey()}, deadline).Text()
+	if err != nil {
+		t.Fatalf("pop: %v", err)
+	}
+	q.process(ctx, raw)
+}
+
+func TestFailingJobIsRetriedThenBuried(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	q := NewQueue(client, Config{MaxAttempts: 2, BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	q.Handle("send_receipt", func(ctx context.Context, job *Job) error {
+		return errors.New("smtp unavailable")
+	})
+	if _, err := q.Enqueue(ctx, "send_receipt", map[string]string{"tripId": "trip-1"}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	runOnce(t, q)
+	if stats, _ := q.Stats(ctx); stats.Delayed != 1 || stats.Processing != 0 {
+		t.Fatalf("after first failure: %+v, want one delayed job", stats)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	now := time.Now().UnixMilli()
+	if err := promoteScript.Run(ctx, client, []string{q.delayedKey(), q.readyKey()}, now, 100).Err(); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	runOnce(t, q)
+
+	stats, _ := q.Stats(ctx)
+	if stats.Dead != 1 || stats.Delayed != 0 || stats.Processing != 0 {
+		t.Fatalf("after last attempt: %+v, want one dead job", stats)
+	}
+	dead, err := q.DeadLetters(ctx, 10)
+	if err != nil || len(dead) != 1 || dead[0].Attempts != 2 || dead[0].LastError != "smtp unavailable" {
+		t.Errorf("DeadLetters = %+v, %v", dead, err)
+	}
+
+	for _, key := range mr.Keys() {
+		if !strings.HasPrefix(key, "{jobs:default}:") {
+			t.Errorf("key %q is not hash-tagged by queue", key)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Config configures a leader election
+type Config struct {
+	// Name identifies the job being elected for, e.g. "stale-driver-sweeper"
+	Name string
+	// ID identifies this candidate, typically the pod name
+	ID string
+	// LeaseDuration is how long leadership lasts without renewal
+	LeaseDuration time.Duration
+	// RenewInterval is how often the leader renews its lease
+	RenewInterval time.Duration
+	// RetryInterval is how often followers try to acquire the lease
+	RetryInterval time.Duration
+	KeyPrefix     string
+}
+
+// Callbacks are invoked on leadership transitions
+type Callbacks struct {
+	// OnStartedLeading runs when leadership is acquired; ctx is cancelled when it is lost
+	OnStartedLeading func(ctx context.Context)
+	// OnStoppedLeading runs after leadership is lost or released
+	OnStoppedLeading func()
+	// OnNewLeader runs when a different leader is observed
+	OnNewLeader func(id string)
+}
+
+// Elector runs a Redis lease based leader election
+type Elector struct {
+	client    redis.UniversalClient
+	config    Config
+	callbacks Callbacks
+
+	isLeader   int32
+	mu         sync.Mutex
+	lastLeader string
+}
+
+var renewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// NewElector creates a new leader elector
+func NewElector(client redis.UniversalClient, config Config, callbacks Callbacks) (*Elector, error) {
+	if config.Name == "" || config.ID == "" {
+		return nil, errors.New("leader election requires a name and candidate ID")
+	}
+	if config.LeaseDuration <= 0 {
+		config.LeaseDuration = 15 * time.Second
+	}
+	if config.RenewInterval <= 0 {
+		config.RenewInterval = config.LeaseDuration / 3
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = config.RenewInterval
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "leader:"
+	}
+
+	return &Elector{
+		client:    client,
+		config:    config,
+		callbacks: callbacks,
+	}, nil
+}
+
+func (e *Elector) key() string {
+	return e.config.KeyPrefix + e.config.Name
+}
+
+// IsLeader reports whether this candidate currently holds the lease
+func (e *Elector) IsLeader() bool {
+	return atomic.LoadInt32(&e.isLeader) == 1
+}
+
+// Leader returns the ID of the current leader, or "" if there is none
+func (e *Elector) Leader(ctx context.Context) (string, error) {
+	id, err := e.client.Get(ctx, e.key()).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get leader for %s: %w", e.config.Name, err)
+	}
+	return id, nil
+}
+
+// Run campaigns for leadership until ctx is cancelled, releasing the lease on exit
+func (e *Elector) Run(ctx context.Context) error {
+	for {
+		acquired, err := e.client.SetNX(ctx, e.key(), e.config.ID, e.config.LeaseDuration).Result()
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Leader election %s: failed to acquire lease: %v", e.config.Name, err)
+		}
+
+		if acquired {
+			e.lead(ctx)
+		} else {
+			e.observeLeader(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(e.config.RetryInterval):
+		}
+	}
+}
+
+// lead holds leadership, renewing the lease until renewal fails or ctx ends
+func (e *Elector) lead(ctx context.Context) {
+	atomic.StoreInt32(&e.isLeader, 1)
+	e.setLastLeader(e.config.ID)
+	log.Printf("Leader election %s: %s became leader", e.config.Name, e.config.ID)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	if e.callbacks.OnStartedLeading != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.callbacks.OnStartedLeading(leaderCtx)
+		}()
+	}
+
+	ticker := time.NewTicker(e.config.RenewInterval)
+	defer ticker.Stop()
+
+	for renewing := true; renewing; {
+		select {
+		case <-ctx.Done():
+			renewing = false
+		case <-ticker.C:
+			renewed, err := renewScript.Run(ctx, e.client, []string{e.key()},
+				e.config.ID, e.config.LeaseDuration.Milliseconds()).Int()
+			if err != nil || renewed == 0 {
+				log.Printf("Leader election %s: %s lost leadership (err=%v)", e.config.Name, e.config.ID, err)
+				renewing = false
+			}
+		}
+	}
+
+	cancel()
+	wg.Wait()
+	atomic.StoreInt32(&e.isLeader, 0)
+
+	releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer releaseCancel()
+	if err := releaseScript.Run(releaseCtx, e.client, []string{e.key()}, e.config.ID).Err(); err != nil {
+		log.Printf("Leader election %s: failed to release lease: %v", e.config.Name, err)
+	}
+
+	if e.callbacks.OnStoppedLeading != nil {
+		e.callbacks.OnStoppedLeading()
+	}
+}
+
+func (e *Elector) observeLeader(ctx context.Context) {
+	id, err := e.Leader(ctx)
+	if err != nil || id == "" {
+		return
+	}
+	if e.setLastLeader(id) && e.callbacks.OnNewLeader != nil {
+		e.callbacks.OnNewLeader(id)
+	}
+}
+
+// setLastLeader records id and reports whether it changed
+func (e *Elector) setLastLeader(id string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.lastLeader == id {
+		return false
+	}
+	e.lastLeader = id
+	return true
+}
+
+// RunWhenLeader runs job on exactly one replica at a time until ctx is cancelled
+func RunWhenLeader(ctx context.Context, client redis.UniversalClient, config Config, job func(ctx context.Context)) error {
+	elector, err := NewElector(client, config, Callbacks{OnStartedLeading: job})
+	if err != nil {
+		return err
+	}
+	return elector.Run(ctx)
+}
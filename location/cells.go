@@ -0,0 +1,113 @@
+package location
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrH3Unavailable is returned by the H3 helpers when no indexer is registered
+var ErrH3Unavailable = errors.New("location: no H3 indexer registered")
+
+// CellIndexer buckets locations into cells of a hierarchical grid
+type CellIndexer interface {
+	// Cell returns the cell containing loc at resolution
+	Cell(loc Location, resolution int) (string, error)
+	// KRing returns cell and every cell within k steps of it
+	KRing(cell string, k int) ([]string, error)
+}
+
+// GeohashIndexer is a CellIndexer over geohash cells, resolution being the
+// geohash precision
+type GeohashIndexer struct{}
+
+// Cell returns the geohash of loc
+func (GeohashIndexer) Cell(loc Location, resolution int) (string, error) {
+	return EncodeGeohash(loc, resolution), nil
+}
+
+// KRing returns the (2k+1)² block of geohash cells centred on cell
+func (GeohashIndexer) KRing(cell string, k int) ([]string, error) {
+	if _, err := GeohashBounds(cell); err != nil {
+		return nil, err
+	}
+	cells := []string{cell}
+	for dLat := -k; dLat <= k; dLat++ {
+		for dLng := -k; dLng <= k; dLng++ {
+			if dLat == 0 && dLng == 0 {
+				continue
+			}
+			neighbor, ok, err := geohashOffset(cell, dLat, dLng)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				cells = append(cells, neighbor)
+			}
+		}
+	}
+	return cells, nil
+}
+
+// geohashOffset steps one cell at a time so offsets beyond 1 stay exact
+func geohashOffset(cell string, dLat, dLng int) (string, bool, error) {
+	for dLat != 0 || dLng != 0 {
+		stepLat, stepLng := sign(dLat), sign(dLng)
+		next, ok, err := GeohashNeighbor(cell, stepLat, stepLng)
+		if err != nil || !ok {
+			return "", ok, err
+		}
+		cell, dLat, dLng = next, dLat-stepLat, dLng-stepLng
+	}
+	return cell, true, nil
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+var (
+	h3Mu      sync.RWMutex
+	h3Indexer CellIndexer
+)
+
+// RegisterH3 installs the H3 implementation used by H3Cell and H3KRing.
+// The library is not a dependency of this module (it needs cgo), so services
+// that want H3 wrap their binding of choice and register it at startup.
+func RegisterH3(indexer CellIndexer) {
+	h3Mu.Lock()
+	defer h3Mu.Unlock()
+	h3Indexer = indexer
+}
+
+func registeredH3() (CellIndexer, error) {
+	h3Mu.RLock()
+	defer h3Mu.RUnlock()
+	if h3Indexer == nil {
+		return nil, ErrH3Unavailable
+	}
+	return h3Indexer, nil
+}
+
+// H3Cell returns the H3 cell containing loc at resolution
+func H3Cell(loc Location, resolution int) (string, error) {
+	indexer, err := registeredH3()
+	if err != nil {
+		return "", err
+	}
+	return indexer.Cell(loc, resolution)
+}
+
+// H3KRing returns the H3 cells within k steps of cell
+func H3KRing(cell string, k int) ([]string, error) {
+	indexer, err := registeredH3()
+	if err != nil {
+		return nil, err
+	}
+	return indexer.KRing(cell, k)
+}
@@ -0,0 +1,121 @@
+package location
+
+import (
+	"math"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+)
+
+// TrafficWindow scales travel time during part of the day, e.g. rush hour.
+// Hours are local to the time passed in; a window may wrap past midnight.
+type TrafficWindow struct {
+	StartHour  int     `json:"startHour"`
+	EndHour    int     `json:"endHour"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+func (w TrafficWindow) contains(hour int) bool {
+	if w.StartHour <= w.EndHour {
+		return hour >= w.StartHour && hour < w.EndHour
+	}
+	return hour >= w.StartHour || hour < w.EndHour
+}
+
+// ETAConfig holds the assumptions behind ETA estimates
+type ETAConfig struct {
+	// SpeedsKmh is the average city speed per vehicle class
+	SpeedsKmh map[string]float64 `json:"speedsKmh"`
+	// DefaultSpeedKmh applies to unknown vehicle classes
+	DefaultSpeedKmh float64 `json:"defaultSpeedKmh"`
+	// RoadFactor converts straight-line to road distance
+	RoadFactor float64 `json:"roadFactor"`
+	// TimeOfDay applies the first matching window's multiplier
+	TimeOfDay []TrafficWindow `json:"timeOfDay"`
+	// MinETA is the floor for any estimate, covering pickup maneuvering
+	MinETA time.Duration `json:"minEta"`
+}
+
+// DefaultETAConfig returns typical city speeds with morning and evening peaks
+func DefaultETAConfig() ETAConfig {
+	return ETAConfig{
+		SpeedsKmh: map[string]float64{
+			common.VehicleClassEconomy: 25,
+			common.VehicleClassComfort: 25,
+			common.VehicleClassMinivan: 22,
+			common.VehicleClassLuxury:  25,
+		},
+		DefaultSpeedKmh: 25,
+		RoadFactor:      1.3,
+		TimeOfDay: []TrafficWindow{
+			{StartHour: 7, EndHour: 10, Multiplier: 1.4},
+			{StartHour: 16, EndHour: 20, Multiplier: 1.5},
+		},
+		MinETA: time.Minute,
+	}
+}
+
+// speed returns the average speed for a vehicle class
+func (c ETAConfig) speed(vehicleClass string) float64 {
+	if speed := c.SpeedsKmh[vehicleClass]; speed > 0 {
+		return speed
+	}
+	if c.DefaultSpeedKmh > 0 {
+		return c.DefaultSpeedKmh
+	}
+	return 25
+}
+
+// timeOfDayMultiplier returns the traffic multiplier in effect at t
+func (c ETAConfig) timeOfDayMultiplier(t time.Time) float64 {
+	hour := t.Hour()
+	for _, w := range c.TimeOfDay {
+		if w.contains(hour) && w.Multiplier > 0 {
+			return w.Multiplier
+		}
+	}
+	return 1
+}
+
+// Estimate returns the travel time from from to to at time at. trafficFactor
+// scales the result for live conditions; values <= 0 mean 1.
+func (c ETAConfig) Estimate(from, to Location, vehicleClass string, trafficFactor float64, at time.Time) time.Duration {
+	if trafficFactor <= 0 {
+		trafficFactor = 1
+	}
+	roadFactor := c.RoadFactor
+	if roadFactor <= 0 {
+		roadFactor = 1.3
+	}
+
+	distance := HaversineKm(from, to) * roadFactor
+	hours := distance / c.speed(vehicleClass) * trafficFactor * c.timeOfDayMultiplier(at)
+	eta := time.Duration(math.Round(hours * float64(time.Hour)))
+	if eta < c.MinETA {
+		eta = c.MinETA
+	}
+	return eta
+}
+
+// EstimateETA returns the travel time starting now with the default config
+func EstimateETA(from, to Location, vehicleClass string, trafficFactor float64) time.Duration {
+	return DefaultETAConfig().Estimate(from, to, vehicleClass, trafficFactor, time.Now())
+}
+
+// DriverETA is a driver's estimated time to reach a pickup
+type DriverETA struct {
+	DriverID string        `json:"driverId"`
+	ETA      time.Duration `json:"eta"`
+}
+
+// EstimateETAs returns the ETA of every driver to pickup, in input order
+func (c ETAConfig) EstimateETAs(drivers []DriverLocation, pickup Location, trafficFactor float64, at time.Time) []DriverETA {
+	etas := make([]DriverETA, len(drivers))
+	for i, d := range drivers {
+		etas[i] = DriverETA{
+			DriverID: d.DriverID,
+			ETA:      c.Estimate(d.Location, pickup, d.VehicleClass, trafficFactor, at),
+		}
+	}
+	return etas
+}
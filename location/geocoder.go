@@ -0,0 +1,262 @@
+package location
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/cache"
+)
+
+// ErrNoResults is returned when a geocoding lookup matches nothing
+var ErrNoResults = errors.New("location: no geocoding results")
+
+// Address is a geocoded place
+type Address struct {
+	Formatted   string   `json:"formatted"`
+	Street      string   `json:"street,omitempty"`
+	City        string   `json:"city,omitempty"`
+	Region      string   `json:"region,omitempty"`
+	PostalCode  string   `json:"postalCode,omitempty"`
+	Country     string   `json:"country,omitempty"`
+	CountryCode string   `json:"countryCode,omitempty"`
+	Location    Location `json:"location"`
+	// PlaceID is the provider's identifier for the place
+	PlaceID string `json:"placeId,omitempty"`
+}
+
+// Suggestion is an autocomplete candidate
+type Suggestion struct {
+	Description string `json:"description"`
+	PlaceID     string `json:"placeId,omitempty"`
+	// Location is set by providers that resolve suggestions up front
+	Location *Location `json:"location,omitempty"`
+}
+
+// Geocoder resolves addresses to coordinates and back
+type Geocoder interface {
+	// Geocode returns the places matching address, best match first
+	Geocode(ctx context.Context, address string) ([]Address, error)
+	// ReverseGeocode returns the address at loc
+	ReverseGeocode(ctx context.Context, loc Location) (Address, error)
+	// Autocomplete returns suggestions for partial input, biased towards
+	// near when it is set
+	Autocomplete(ctx context.Context, input string, near *Location) ([]Suggestion, error)
+}
+
+// CachingConfig configures a CachingGeocoder
+type CachingConfig struct {
+	KeyPrefix string
+	// TTL applies to geocode and reverse lookups; addresses rarely move
+	TTL time.Duration
+	// AutocompleteTTL is shorter since suggestions are per keystroke
+	AutocompleteTTL time.Duration
+	// NegativeTTL caches lookups with no results
+	NegativeTTL time.Duration
+	// ReversePrecision rounds coordinates for reverse lookups so nearby
+	// pings share a cache entry (4 ≈ 11 m)
+	ReversePrecision int
+}
+
+// DefaultCachingConfig returns the default geocoder cache settings
+func DefaultCachingConfig() CachingConfig {
+	return CachingConfig{
+		KeyPrefix:        "geocode",
+		TTL:              24 * time.Hour,
+		AutocompleteTTL:  10 * time.Minute,
+		NegativeTTL:      10 * time.Minute,
+		ReversePrecision: 4,
+	}
+}
+
+// CachingGeocoder caches another Geocoder's results
+type CachingGeocoder struct {
+	next         Geocoder
+	config       CachingConfig
+	forward      *cache.TypedCache[[]Address]
+	reverse      *cache.TypedCache[Address]
+	autocomplete *cache.TypedCache[[]Suggestion]
+}
+
+// NewCachingGeocoder wraps next with caching in store
+func NewCachingGeocoder(next Geocoder, store cache.Cache, config CachingConfig) *CachingGeocoder {
+	defaults := DefaultCachingConfig()
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaults.KeyPrefix
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.AutocompleteTTL <= 0 {
+		config.AutocompleteTTL = defaults.AutocompleteTTL
+	}
+	if config.ReversePrecision <= 0 {
+		config.ReversePrecision = defaults.ReversePrecision
+	}
+
+	typed := cache.TypedConfig{TTL: config.TTL, Jitter: 0.1, NegativeTTL: config.NegativeTTL}
+	short := cache.TypedConfig{TTL: config.AutocompleteTTL, Jitter: 0.1, NegativeTTL: config.NegativeTTL}
+	return &CachingGeocoder{
+		next:         next,
+		config:       config,
+		forward:      cache.NewTypedCache[[]Address](store, nil, typed),
+		reverse:      cache.NewTypedCache[Address](store, nil, typed),
+		autocomplete: cache.NewTypedCache[[]Suggestion](store, nil, short),
+	}
+}
+
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// toCacheErr maps ErrNoResults to cache.ErrNotFound so it is negatively cached
+func toCacheErr(err error) error {
+	if errors.Is(err, ErrNoResults) {
+		return cache.ErrNotFound
+	}
+	return err
+}
+
+func fromCacheErr(err error) error {
+	if errors.Is(err, cache.ErrNotFound) {
+		return ErrNoResults
+	}
+	return err
+}
+
+// Geocode returns cached results or asks the wrapped geocoder
+func (g *CachingGeocoder) Geocode(ctx context.Context, address string) ([]Address, error) {
+	key := fmt.Sprintf("%s:fwd:%s", g.config.KeyPrefix, normalizeQuery(address))
+	results, err := g.forward.GetOrLoad(ctx, key, func(ctx context.Context) ([]Address, error) {
+		results, err := g.next.Geocode(ctx, address)
+		return results, toCacheErr(err)
+	})
+	return results, fromCacheErr(err)
+}
+
+// ReverseGeocode returns a cached address or asks the wrapped geocoder
+func (g *CachingGeocoder) ReverseGeocode(ctx context.Context, loc Location) (Address, error) {
+	rounded := RoundToPrecision(loc, g.config.ReversePrecision)
+	key := fmt.Sprintf("%s:rev:%s", g.config.KeyPrefix, rounded)
+	address, err := g.reverse.GetOrLoad(ctx, key, func(ctx context.Context) (Address, error) {
+		address, err := g.next.ReverseGeocode(ctx, rounded)
+		return address, toCacheErr(err)
+	})
+	return address, fromCacheErr(err)
+}
+
+// Autocomplete returns cached suggestions or asks the wrapped geocoder.
+// The bias location is rounded to about a kilometre for the cache key.
+func (g *CachingGeocoder) Autocomplete(ctx context.Context, input string, near *Location) ([]Suggestion, error) {
+	key := fmt.Sprintf("%s:ac:%s", g.config.KeyPrefix, normalizeQuery(input))
+	if near != nil {
+		key += ":" + RoundToPrecision(*near, 2).String()
+	}
+	suggestions, err := g.autocomplete.GetOrLoad(ctx, key, func(ctx context.Context) ([]Suggestion, error) {
+		suggestions, err := g.next.Autocomplete(ctx, input, near)
+		return suggestions, toCacheErr(err)
+	})
+	return suggestions, fromCacheErr(err)
+}
+
+// RateLimitedGeocoder spaces calls to another Geocoder, e.g. to respect
+// Nominatim's one request per second usage policy. Limits are per process.
+type RateLimitedGeocoder struct {
+	next     Geocoder
+	interval time.Duration
+
+	mu       sync.Mutex
+	nextSlot time.Time
+}
+
+// NewRateLimitedGeocoder allows at most requestsPerSecond calls to next
+func NewRateLimitedGeocoder(next Geocoder, requestsPerSecond float64) *RateLimitedGeocoder {
+	if requestsPerSecond <= 0 {
+		requestsPerSecond = 1
+	}
+	return &RateLimitedGeocoder{
+		next:     next,
+		interval: time.Duration(float64(time.Second) / requestsPerSecond),
+	}
+}
+
+// wait reserves the next free slot and sleeps until it, or returns the
+// context's error if it is cancelled first
+func (g *RateLimitedGeocoder) wait(ctx context.Context) error {
+	g.mu.Lock()
+	now := time.Now()
+	slot := g.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	g.nextSlot = slot.Add(g.interval)
+	g.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("geocoder rate limit wait aborted: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Geocode waits for a slot and calls the wrapped geocoder
+func (g *RateLimitedGeocoder) Geocode(ctx context.Context, address string) ([]Address, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+	return g.next.Geocode(ctx, address)
+}
+
+// ReverseGeocode waits for a slot and calls the wrapped geocoder
+func (g *RateLimitedGeocoder) ReverseGeocode(ctx context.Context, loc Location) (Address, error) {
+	if err := g.wait(ctx); err != nil {
+		return Address{}, err
+	}
+	return g.next.ReverseGeocode(ctx, loc)
+}
+
+// Autocomplete waits for a slot and calls the wrapped geocoder
+func (g *RateLimitedGeocoder) Autocomplete(ctx context.Context, input string, near *Location) ([]Suggestion, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+	return g.next.Autocomplete(ctx, input, near)
+}
+
+// getJSON issues a GET request and decodes a JSON response into v
+func getJSON(ctx context.Context, client *http.Client, endpoint string, params url.Values, headers map[string]string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build geocoding request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, val := range headers {
+		req.Header.Set(k, val)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call geocoding provider: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("geocoding provider returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// GoogleGeocoderConfig configures the Google Maps Platform provider
+type GoogleGeocoderConfig struct {
+	APIKey string
+	// BaseURL defaults to https://maps.googleapis.com/maps/api
+	BaseURL string
+	// Language and Region bias results (e.g. "en", "et")
+	Language string
+	Region   string
+	// AutocompleteRadiusMeters bounds the location bias of autocomplete
+	AutocompleteRadiusMeters int
+	Timeout                  time.Duration
+}
+
+// GoogleGeocoder uses the Google Geocoding and Places Autocomplete APIs
+type GoogleGeocoder struct {
+	config GoogleGeocoderConfig
+	client *http.Client
+}
+
+// NewGoogleGeocoder creates a Google geocoding provider
+func NewGoogleGeocoder(config GoogleGeocoderConfig) (*GoogleGeocoder, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("google geocoder requires an API key")
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = "https://maps.googleapis.com/maps/api"
+	}
+	if config.AutocompleteRadiusMeters <= 0 {
+		config.AutocompleteRadiusMeters = 20000
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	return &GoogleGeocoder{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+type googleGeocodeResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		FormattedAddress string `json:"formatted_address"`
+		PlaceID          string `json:"place_id"`
+		Geometry         struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+		AddressComponents []struct {
+			LongName  string   `json:"long_name"`
+			ShortName string   `json:"short_name"`
+			Types     []string `json:"types"`
+		} `json:"address_components"`
+	} `json:"results"`
+}
+
+type googleAutocompleteResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Predictions  []struct {
+		Description string `json:"description"`
+		PlaceID     string `json:"place_id"`
+	} `json:"predictions"`
+}
+
+// googleStatusErr maps an API status to an error
+func googleStatusErr(status, message string) error {
+	switch status {
+	case "OK":
+		return nil
+	case "ZERO_RESULTS":
+		return ErrNoResults
+	}
+	return fmt.Errorf("google geocoding failed with status %s: %s", status, message)
+}
+
+func (g *GoogleGeocoder) params() url.Values {
+	params := url.Values{"key": {g.config.APIKey}}
+	if g.config.Language != "" {
+		params.Set("language", g.config.Language)
+	}
+	if g.config.Region != "" {
+		params.Set("region", g.config.Region)
+	}
+	return params
+}
+
+func (g *GoogleGeocoder) geocode(ctx context.Context, params url.Values) ([]Address, error) {
+	var resp googleGeocodeResponse
+	if err := getJSON(ctx, g.client, g.config.BaseURL+"/geocode/json", params, nil, &resp); err != nil {
+		return nil, err
+	}
+	if err := googleStatusErr(resp.Status, resp.ErrorMessage); err != nil {
+		return nil, err
+	}
+
+	addresses := make([]Address, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		a := Address{
+			Formatted: r.FormattedAddress,
+			PlaceID:   r.PlaceID,
+			Location:  Location{Latitude: r.Geometry.Location.Lat, Longitude: r.Geometry.Location.Lng},
+		}
+		var number, route string
+		for _, c := range r.AddressComponents {
+			for _, t := range c.Types {
+				switch t {
+				case "street_number":
+					number = c.LongName
+				case "route":
+					route = c.LongName
+				case "locality":
+					a.City = c.LongName
+				case "administrative_area_level_1":
+					a.Region = c.LongName
+				case "postal_code":
+					a.PostalCode = c.LongName
+				case "country":
+					a.Country, a.CountryCode = c.LongName, c.ShortName
+				}
+			}
+		}
+		a.Street = joinNonEmpty(" ", number, route)
+		addresses = append(addresses, a)
+	}
+	if len(addresses) == 0 {
+		return nil, ErrNoResults
+	}
+	return addresses, nil
+}
+
+// Geocode resolves an address
+func (g *GoogleGeocoder) Geocode(ctx context.Context, address string) ([]Address, error) {
+	params := g.params()
+	params.Set("address", address)
+	return g.geocode(ctx, params)
+}
+
+// ReverseGeocode returns the address at loc
+func (g *GoogleGeocoder) ReverseGeocode(ctx context.Context, loc Location) (Address, error) {
+	params := g.params()
+	params.Set("latlng", fmt.Sprintf("%f,%f", loc.Latitude, loc.Longitude))
+	addresses, err := g.geocode(ctx, params)
+	if err != nil {
+		return Address{}, err
+	}
+	return addresses[0], nil
+}
+
+// Autocomplete returns Places Autocomplete predictions; suggestions carry a
+// PlaceID but no location
+func (g *GoogleGeocoder) Autocomplete(ctx context.Context, input string, near *Location) ([]Suggestion, error) {
+	params := g.params()
+	params.Set("input", input)
+	if near != nil {
+		params.Set("location", fmt.Sprintf("%f,%f", near.Latitude, near.Longitude))
+		params.Set("radius", fmt.Sprint(g.config.AutocompleteRadiusMeters))
+	}
+
+	var resp googleAutocompleteResponse
+	if err := getJSON(ctx, g.client, g.config.BaseURL+"/place/autocomplete/json", params, nil, &resp); err != nil {
+		return nil, err
+	}
+	if err := googleStatusErr(resp.Status, resp.ErrorMessage); err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]Suggestion, 0, len(resp.Predictions))
+	for _, p := range resp.Predictions {
+		suggestions = append(suggestions, Suggestion{Description: p.Description, PlaceID: p.PlaceID})
+	}
+	return suggestions, nil
+}
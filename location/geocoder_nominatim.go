@@ -0,0 +1,194 @@
+package location
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NominatimConfig configures the OpenStreetMap Nominatim provider
+type NominatimConfig struct {
+	// BaseURL defaults to the public https://nominatim.openstreetmap.org
+	BaseURL string
+	// UserAgent identifies the application, as the usage policy requires
+	UserAgent string
+	// Email is sent with requests for large volumes, per the usage policy
+	Email string
+	// CountryCodes restricts results (e.g. "et")
+	CountryCodes string
+	Language     string
+	Limit        int
+	Timeout      time.Duration
+}
+
+// NominatimGeocoder uses the Nominatim search and reverse APIs. The public
+// instance allows one request per second; wrap it in a RateLimitedGeocoder.
+type NominatimGeocoder struct {
+	config NominatimConfig
+	client *http.Client
+}
+
+// NewNominatimGeocoder creates a Nominatim geocoding provider
+func NewNominatimGeocoder(config NominatimConfig) *NominatimGeocoder {
+	if config.BaseURL == "" {
+		config.BaseURL = "https://nominatim.openstreetmap.org"
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = "motocabz-common/1.0"
+	}
+	if config.Limit <= 0 {
+		config.Limit = 5
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	return &NominatimGeocoder{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+type nominatimPlace struct {
+	PlaceID     int64  `json:"place_id"`
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Error       string `json:"error"`
+	Address     struct {
+		HouseNumber string `json:"house_number"`
+		Road        string `json:"road"`
+		City        string `json:"city"`
+		Town        string `json:"town"`
+		Village     string `json:"village"`
+		State       string `json:"state"`
+		Postcode    string `json:"postcode"`
+		Country     string `json:"country"`
+		CountryCode string `json:"country_code"`
+	} `json:"address"`
+}
+
+func (p nominatimPlace) toAddress() (Address, error) {
+	lat, err := strconv.ParseFloat(p.Lat, 64)
+	if err != nil {
+		return Address{}, fmt.Errorf("failed to parse nominatim latitude %q: %w", p.Lat, err)
+	}
+	lng, err := strconv.ParseFloat(p.Lon, 64)
+	if err != nil {
+		return Address{}, fmt.Errorf("failed to parse nominatim longitude %q: %w", p.Lon, err)
+	}
+	city := p.Address.City
+	if city == "" {
+		city = p.Address.Town
+	}
+	if city == "" {
+		city = p.Address.Village
+	}
+	return Address{
+		Formatted:   p.DisplayName,
+		Street:      joinNonEmpty(" ", p.Address.HouseNumber, p.Address.Road),
+		City:        city,
+		Region:      p.Address.State,
+		PostalCode:  p.Address.Postcode,
+		Country:     p.Address.Country,
+		CountryCode: strings.ToUpper(p.Address.CountryCode),
+		Location:    Location{Latitude: lat, Longitude: lng},
+		PlaceID:     strconv.FormatInt(p.PlaceID, 10),
+	}, nil
+}
+
+func (n *NominatimGeocoder) params() url.Values {
+	params := url.Values{"format": {"jsonv2"}, "addressdetails": {"1"}}
+	if n.config.Email != "" {
+		params.Set("email", n.config.Email)
+	}
+	if n.config.Language != "" {
+		params.Set("accept-language", n.config.Language)
+	}
+	return params
+}
+
+func (n *NominatimGeocoder) headers() map[string]string {
+	return map[string]string{"User-Agent": n.config.UserAgent}
+}
+
+func (n *NominatimGeocoder) search(ctx context.Context, query string, near *Location) ([]Address, error) {
+	params := n.params()
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(n.config.Limit))
+	if n.config.CountryCodes != "" {
+		params.Set("countrycodes", n.config.CountryCodes)
+	}
+	if near != nil {
+		// bias, not bound, results to roughly 20 km around near
+		b := BoundsAround(*near, 20)
+		params.Set("viewbox", fmt.Sprintf("%f,%f,%f,%f", b.SouthWest.Longitude, b.NorthEast.Latitude, b.NorthEast.Longitude, b.SouthWest.Latitude))
+	}
+
+	var places []nominatimPlace
+	if err := getJSON(ctx, n.client, n.config.BaseURL+"/search", params, n.headers(), &places); err != nil {
+		return nil, err
+	}
+	addresses := make([]Address, 0, len(places))
+	for _, p := range places {
+		a, err := p.toAddress()
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, a)
+	}
+	if len(addresses) == 0 {
+		return nil, ErrNoResults
+	}
+	return addresses, nil
+}
+
+// Geocode resolves an address
+func (n *NominatimGeocoder) Geocode(ctx context.Context, address string) ([]Address, error) {
+	return n.search(ctx, address, nil)
+}
+
+// ReverseGeocode returns the address at loc
+func (n *NominatimGeocoder) ReverseGeocode(ctx context.Context, loc Location) (Address, error) {
+	params := n.params()
+	params.Set("lat", strconv.FormatFloat(loc.Latitude, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(loc.Longitude, 'f', -1, 64))
+
+	var place nominatimPlace
+	if err := getJSON(ctx, n.client, n.config.BaseURL+"/reverse", params, n.headers(), &place); err != nil {
+		return Address{}, err
+	}
+	if place.Error != "" {
+		return Address{}, ErrNoResults
+	}
+	return place.toAddress()
+}
+
+// Autocomplete runs a regular search since Nominatim has no autocomplete
+// endpoint; suggestions come with their location resolved
+func (n *NominatimGeocoder) Autocomplete(ctx context.Context, input string, near *Location) ([]Suggestion, error) {
+	addresses, err := n.search(ctx, input, near)
+	if err != nil {
+		return nil, err
+	}
+	suggestions := make([]Suggestion, 0, len(addresses))
+	for _, a := range addresses {
+		loc := a.Location
+		suggestions = append(suggestions, Suggestion{Description: a.Formatted, PlaceID: a.PlaceID, Location: &loc})
+	}
+	return suggestions, nil
+}
+
+// joinNonEmpty joins the non-empty parts with sep
+func joinNonEmpty(sep string, parts ...string) string {
+	kept := parts[:0:0]
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, sep)
+}
@@ -0,0 +1,199 @@
+package location
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// MaxGeohashPrecision is the longest supported geohash
+const MaxGeohashPrecision = 12
+
+// Bounds is a latitude/longitude rectangle
+type Bounds struct {
+	SouthWest Location `json:"southWest"`
+	NorthEast Location `json:"northEast"`
+}
+
+// Center returns the midpoint of the rectangle
+func (b Bounds) Center() Location {
+	return Location{
+		Latitude:  (b.SouthWest.Latitude + b.NorthEast.Latitude) / 2,
+		Longitude: (b.SouthWest.Longitude + b.NorthEast.Longitude) / 2,
+	}
+}
+
+// Contains reports whether loc lies inside the rectangle
+func (b Bounds) Contains(loc Location) bool {
+	return loc.Latitude >= b.SouthWest.Latitude && loc.Latitude <= b.NorthEast.Latitude &&
+		loc.Longitude >= b.SouthWest.Longitude && loc.Longitude <= b.NorthEast.Longitude
+}
+
+// BoundsAround returns the rectangle enclosing a circle of radiusKm around center
+func BoundsAround(center Location, radiusKm float64) Bounds {
+	dLat := toDegrees(radiusKm / EarthRadiusKm)
+	dLng := 180.0
+	if cos := math.Cos(toRadians(center.Latitude)); cos > 1e-9 {
+		dLng = math.Min(180, dLat/cos)
+	}
+	return Bounds{
+		SouthWest: Location{Latitude: math.Max(-90, center.Latitude-dLat), Longitude: math.Max(-180, center.Longitude-dLng)},
+		NorthEast: Location{Latitude: math.Min(90, center.Latitude+dLat), Longitude: math.Min(180, center.Longitude+dLng)},
+	}
+}
+
+// EncodeGeohash returns the geohash of loc with precision characters
+// (5 ≈ 4.9 km cells, 6 ≈ 1.2 km, 7 ≈ 150 m)
+func EncodeGeohash(loc Location, precision int) string {
+	if precision <= 0 || precision > MaxGeohashPrecision {
+		precision = MaxGeohashPrecision
+	}
+
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+	var b strings.Builder
+	bit, ch, even := 0, 0, true
+	for b.Len() < precision {
+		if even {
+			ch = ch<<1 | bisect(&lngRange, loc.Longitude)
+		} else {
+			ch = ch<<1 | bisect(&latRange, loc.Latitude)
+		}
+		even = !even
+		if bit++; bit == 5 {
+			b.WriteByte(geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return b.String()
+}
+
+// bisect halves r towards value and returns 1 for the upper half
+func bisect(r *[2]float64, value float64) int {
+	mid := (r[0] + r[1]) / 2
+	if value >= mid {
+		r[0] = mid
+		return 1
+	}
+	r[1] = mid
+	return 0
+}
+
+// GeohashBounds returns the cell covered by a geohash
+func GeohashBounds(hash string) (Bounds, error) {
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+	even := true
+	for _, c := range strings.ToLower(hash) {
+		idx := strings.IndexRune(geohashAlphabet, c)
+		if idx < 0 {
+			return Bounds{}, fmt.Errorf("invalid geohash %q", hash)
+		}
+		for mask := 16; mask > 0; mask >>= 1 {
+			r := &latRange
+			if even {
+				r = &lngRange
+			}
+			mid := (r[0] + r[1]) / 2
+			if idx&mask != 0 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			even = !even
+		}
+	}
+	return Bounds{
+		SouthWest: Location{Latitude: latRange[0], Longitude: lngRange[0]},
+		NorthEast: Location{Latitude: latRange[1], Longitude: lngRange[1]},
+	}, nil
+}
+
+// GeohashCellSize returns the height and width in degrees of a cell at precision
+func GeohashCellSize(precision int) (latDeg, lngDeg float64) {
+	bits := precision * 5
+	lngBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lngBits))
+}
+
+// GeohashesCovering returns every cell at precision intersecting bounds
+func GeohashesCovering(bounds Bounds, precision int) []string {
+	latStep, lngStep := GeohashCellSize(precision)
+	seen := make(map[string]bool)
+	var hashes []string
+	add := func(lat, lng float64) {
+		hash := EncodeGeohash(Location{Latitude: math.Min(lat, 90), Longitude: math.Min(lng, 180)}, precision)
+		if !seen[hash] {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+
+	for lat := bounds.SouthWest.Latitude; ; lat += latStep {
+		lat = math.Min(lat, bounds.NorthEast.Latitude)
+		for lng := bounds.SouthWest.Longitude; ; lng += lngStep {
+			lng = math.Min(lng, bounds.NorthEast.Longitude)
+			add(lat, lng)
+			if lng >= bounds.NorthEast.Longitude {
+				break
+			}
+		}
+		if lat >= bounds.NorthEast.Latitude {
+			break
+		}
+	}
+	return hashes
+}
+
+// DecodeGeohash returns the center of a geohash cell along with the cell's
+// half-height and half-width in degrees
+func DecodeGeohash(hash string) (center Location, latErr, lngErr float64, err error) {
+	bounds, err := GeohashBounds(hash)
+	if err != nil {
+		return Location{}, 0, 0, err
+	}
+	latErr = (bounds.NorthEast.Latitude - bounds.SouthWest.Latitude) / 2
+	lngErr = (bounds.NorthEast.Longitude - bounds.SouthWest.Longitude) / 2
+	return bounds.Center(), latErr, lngErr, nil
+}
+
+// GeohashNeighbor returns the adjacent cell of the same precision offset by
+// dLat rows and dLng columns (each -1, 0 or 1); longitude wraps at the
+// antimeridian and ok is false past a pole
+func GeohashNeighbor(hash string, dLat, dLng int) (neighbor string, ok bool, err error) {
+	center, latErr, lngErr, err := DecodeGeohash(hash)
+	if err != nil {
+		return "", false, err
+	}
+	lat := center.Latitude + float64(dLat)*2*latErr
+	if lat > 90 || lat < -90 {
+		return "", false, nil
+	}
+	lng := center.Longitude + float64(dLng)*2*lngErr
+	if lng >= 180 {
+		lng -= 360
+	} else if lng < -180 {
+		lng += 360
+	}
+	return EncodeGeohash(Location{Latitude: lat, Longitude: lng}, len(hash)), true, nil
+}
+
+// GeohashNeighbors returns the up to eight cells surrounding hash, clockwise
+// from north
+func GeohashNeighbors(hash string) ([]string, error) {
+	offsets := [8][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	neighbors := make([]string, 0, len(offsets))
+	for _, o := range offsets {
+		neighbor, ok, err := GeohashNeighbor(hash, o[0], o[1])
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			neighbors = append(neighbors, neighbor)
+		}
+	}
+	return neighbors, nil
+}
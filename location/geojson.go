@@ -0,0 +1,175 @@
+package location
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// GeoJSON geometry and object types
+const (
+	GeometryPoint        = "Point"
+	GeometryPolygon      = "Polygon"
+	GeometryMultiPolygon = "MultiPolygon"
+
+	typeFeature           = "Feature"
+	typeFeatureCollection = "FeatureCollection"
+)
+
+// Geometry is a GeoJSON geometry object. Coordinates are [longitude, latitude]
+// positions as GeoJSON requires.
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// newGeometry builds a geometry from coordinates that always marshal
+func newGeometry(geometryType string, coordinates any) Geometry {
+	raw, _ := json.Marshal(coordinates)
+	return Geometry{Type: geometryType, Coordinates: raw}
+}
+
+// decodeGeometry unmarshals data as a GeoJSON geometry of the given type
+func decodeGeometry(data []byte, geometryType string, coordinates any) error {
+	var g Geometry
+	if err := json.Unmarshal(data, &g); err != nil {
+		return fmt.Errorf("failed to decode GeoJSON %s: %w", geometryType, err)
+	}
+	return g.decode(geometryType, coordinates)
+}
+
+func (g Geometry) decode(geometryType string, coordinates any) error {
+	if g.Type != geometryType {
+		return fmt.Errorf("failed to decode GeoJSON %s: got type %q", geometryType, g.Type)
+	}
+	if err := json.Unmarshal(g.Coordinates, coordinates); err != nil {
+		return fmt.Errorf("failed to decode GeoJSON %s coordinates: %w", geometryType, err)
+	}
+	return nil
+}
+
+// Feature is a GeoJSON feature
+type Feature struct {
+	Type       string         `json:"type"`
+	ID         string         `json:"id,omitempty"`
+	Geometry   Geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// FeatureCollection is a GeoJSON feature collection
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// NewFeatureCollection creates a feature collection; features is never
+// encoded as null
+func NewFeatureCollection(features ...Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return FeatureCollection{Type: typeFeatureCollection, Features: features}
+}
+
+// ToGeoJSON returns the location as a GeoJSON Point
+func (l Location) ToGeoJSON() Geometry {
+	return newGeometry(GeometryPoint, [2]float64{l.Longitude, l.Latitude})
+}
+
+// LocationFromGeoJSON decodes a GeoJSON Point
+func LocationFromGeoJSON(g Geometry) (Location, error) {
+	var position [2]float64
+	if err := g.decode(GeometryPoint, &position); err != nil {
+		return Location{}, err
+	}
+	return Location{Latitude: position[1], Longitude: position[0]}, nil
+}
+
+// ToGeoJSON returns the rectangle as a GeoJSON Polygon
+func (b Bounds) ToGeoJSON() Geometry {
+	return NewPolygon([]Location{
+		b.SouthWest,
+		{Latitude: b.SouthWest.Latitude, Longitude: b.NorthEast.Longitude},
+		b.NorthEast,
+		{Latitude: b.NorthEast.Latitude, Longitude: b.SouthWest.Longitude},
+	}).ToGeoJSON()
+}
+
+// BoundsFromGeoJSON returns the rectangle enclosing a GeoJSON Polygon
+func BoundsFromGeoJSON(g Geometry) (Bounds, error) {
+	p, err := PolygonFromGeoJSON(g)
+	if err != nil {
+		return Bounds{}, err
+	}
+	return p.Bounds(), nil
+}
+
+// PolygonFromGeoJSON decodes a GeoJSON Polygon
+func PolygonFromGeoJSON(g Geometry) (Polygon, error) {
+	var rings [][][2]float64
+	if err := g.decode(GeometryPolygon, &rings); err != nil {
+		return Polygon{}, err
+	}
+	return polygonFromCoordinates(rings)
+}
+
+// ToGeoJSON returns the driver as a Point feature with its details as properties
+func (d DriverLocation) ToGeoJSON() Feature {
+	properties := map[string]any{
+		"driverId":  d.DriverID,
+		"updatedAt": d.UpdatedAt.Format(time.RFC3339),
+	}
+	if d.Heading != 0 {
+		properties["heading"] = d.Heading
+	}
+	if d.VehicleClass != "" {
+		properties["vehicleClass"] = d.VehicleClass
+	}
+	if d.Capacity.Seats > 0 {
+		properties["seats"] = d.Capacity.Seats
+		properties["luggage"] = d.Capacity.Luggage
+	}
+	return Feature{Type: typeFeature, ID: d.DriverID, Geometry: d.Location.ToGeoJSON(), Properties: properties}
+}
+
+// DriversToGeoJSON returns drivers as a FeatureCollection of points, ready
+// for map dashboards
+func DriversToGeoJSON(drivers []DriverLocation) FeatureCollection {
+	features := make([]Feature, 0, len(drivers))
+	for _, d := range drivers {
+		features = append(features, d.ToGeoJSON())
+	}
+	return NewFeatureCollection(features...)
+}
+
+// DriversFromGeoJSON decodes a FeatureCollection produced by DriversToGeoJSON
+func DriversFromGeoJSON(fc FeatureCollection) ([]DriverLocation, error) {
+	drivers := make([]DriverLocation, 0, len(fc.Features))
+	for _, f := range fc.Features {
+		loc, err := LocationFromGeoJSON(f.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode driver feature %s: %w", f.ID, err)
+		}
+		d := DriverLocation{DriverID: f.ID, Location: loc}
+		if id, ok := f.Properties["driverId"].(string); ok {
+			d.DriverID = id
+		}
+		if heading, ok := f.Properties["heading"].(float64); ok {
+			d.Heading = heading
+		}
+		if class, ok := f.Properties["vehicleClass"].(string); ok {
+			d.VehicleClass = class
+		}
+		if seats, ok := f.Properties["seats"].(float64); ok {
+			d.Capacity.Seats = int(seats)
+		}
+		if luggage, ok := f.Properties["luggage"].(float64); ok {
+			d.Capacity.Luggage = int(luggage)
+		}
+		if updated, ok := f.Properties["updatedAt"].(string); ok {
+			d.UpdatedAt, _ = time.Parse(time.RFC3339, updated)
+		}
+		drivers = append(drivers, d)
+	}
+	return drivers, nil
+}
@@ -0,0 +1,55 @@
+package location
+
+import (
+	"fmt"
+	"math"
+)
+
+// EarthRadiusKm is the mean Earth radius used for distance calculations
+const EarthRadiusKm = 6371.0
+
+// Location is a geographic coordinate
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// NewLocation creates a new location
+func NewLocation(lat, lng float64) Location {
+	return Location{Latitude: lat, Longitude: lng}
+}
+
+// IsValid reports whether the coordinates are within range
+func (l Location) IsValid() bool {
+	return l.Latitude >= -90 && l.Latitude <= 90 && l.Longitude >= -180 && l.Longitude <= 180
+}
+
+// String returns the location as "lat,lng"
+func (l Location) String() string {
+	return fmt.Sprintf("%.6f,%.6f", l.Latitude, l.Longitude)
+}
+
+// DistanceKm returns the great-circle distance to other in kilometres
+func (l Location) DistanceKm(other Location) float64 {
+	return HaversineKm(l, other)
+}
+
+// HaversineKm returns the great-circle distance between two locations in kilometres
+func HaversineKm(from, to Location) float64 {
+	lat1 := toRadians(from.Latitude)
+	lat2 := toRadians(to.Latitude)
+	dLat := lat2 - lat1
+	dLng := toRadians(to.Longitude - from.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return EarthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
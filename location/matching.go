@@ -0,0 +1,164 @@
+package location
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+)
+
+// VehicleCapacity describes what a driver's vehicle can carry
+type VehicleCapacity struct {
+	Seats                int  `json:"seats"`
+	Luggage              int  `json:"luggage"`
+	ChildSeat            bool `json:"childSeat,omitempty"`
+	WheelchairAccessible bool `json:"wheelchairAccessible,omitempty"`
+}
+
+// defaultCapacities applies to drivers that have not reported their vehicle details
+var defaultCapacities = map[string]VehicleCapacity{
+	common.VehicleClassEconomy: {Seats: 4, Luggage: 2},
+	common.VehicleClassComfort: {Seats: 4, Luggage: 3},
+	common.VehicleClassMinivan: {Seats: 7, Luggage: 5},
+	common.VehicleClassLuxury:  {Seats: 4, Luggage: 3},
+}
+
+// DefaultCapacity returns the typical capacity of a vehicle class
+func DefaultCapacity(vehicleClass string) VehicleCapacity {
+	return defaultCapacities[vehicleClass]
+}
+
+// Validate checks the capacity against the platform limits
+func (c VehicleCapacity) Validate() error {
+	if c.Seats < common.MinVehicleSeats || c.Seats > common.MaxVehicleSeats {
+		return fmt.Errorf("seats must be between %d and %d", common.MinVehicleSeats, common.MaxVehicleSeats)
+	}
+	if c.Luggage < 0 || c.Luggage > common.MaxVehicleLuggage {
+		return fmt.Errorf("luggage must be between 0 and %d", common.MaxVehicleLuggage)
+	}
+	return nil
+}
+
+// RiderRequirements are the constraints a trip request places on the vehicle
+type RiderRequirements struct {
+	Passengers           int  `json:"passengers"`
+	Luggage              int  `json:"luggage,omitempty"`
+	ChildSeat            bool `json:"childSeat,omitempty"`
+	WheelchairAccessible bool `json:"wheelchairAccessible,omitempty"`
+}
+
+// Validate checks the requirements against the platform limits
+func (r RiderRequirements) Validate() error {
+	if r.Passengers < 0 || r.Passengers > common.MaxPassengersPerTrip {
+		return fmt.Errorf("passengers must be between 1 and %d", common.MaxPassengersPerTrip)
+	}
+	if r.Luggage < 0 || r.Luggage > common.MaxVehicleLuggage {
+		return fmt.Errorf("luggage must be between 0 and %d", common.MaxVehicleLuggage)
+	}
+	return nil
+}
+
+// Satisfies reports whether the vehicle meets the rider's requirements
+func (c VehicleCapacity) Satisfies(r RiderRequirements) bool {
+	passengers := r.Passengers
+	if passengers == 0 {
+		passengers = 1
+	}
+	return c.Seats >= passengers &&
+		c.Luggage >= r.Luggage &&
+		(!r.ChildSeat || c.ChildSeat) &&
+		(!r.WheelchairAccessible || c.WheelchairAccessible)
+}
+
+// DriverLocation is a driver's last reported position with vehicle metadata
+type DriverLocation struct {
+	DriverID     string          `json:"driverId"`
+	Location     Location        `json:"location"`
+	Heading      float64         `json:"heading,omitempty"`
+	VehicleClass string          `json:"vehicleClass,omitempty"`
+	Capacity     VehicleCapacity `json:"capacity"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+// EffectiveCapacity returns the reported capacity, or the class default when
+// the driver has not reported one
+func (d DriverLocation) EffectiveCapacity() VehicleCapacity {
+	if d.Capacity.Seats > 0 {
+		return d.Capacity
+	}
+	return DefaultCapacity(d.VehicleClass)
+}
+
+// NearbyQuery searches for drivers around a point
+type NearbyQuery struct {
+	Center       Location          `json:"center"`
+	RadiusKm     float64           `json:"radiusKm"`
+	Limit        int               `json:"limit,omitempty"`
+	VehicleClass string            `json:"vehicleClass,omitempty"`
+	Requirements RiderRequirements `json:"requirements"`
+}
+
+// Validate checks the query parameters
+func (q NearbyQuery) Validate() error {
+	if !q.Center.IsValid() {
+		return fmt.Errorf("invalid center %s", q.Center)
+	}
+	if q.RadiusKm <= 0 {
+		return fmt.Errorf("radius must be positive")
+	}
+	if q.VehicleClass != "" {
+		if _, ok := defaultCapacities[q.VehicleClass]; !ok {
+			return fmt.Errorf("unknown vehicle class %s", q.VehicleClass)
+		}
+	}
+	return q.Requirements.Validate()
+}
+
+// Match is a candidate driver that satisfies a query
+type Match struct {
+	Driver     DriverLocation `json:"driver"`
+	DistanceKm float64        `json:"distanceKm"`
+	// Score ranks matches; higher is better
+	Score float64 `json:"score"`
+}
+
+// Rank filters candidates by radius, class and capacity and orders them by
+// score. Closer drivers score higher; among similar distances, vehicles with
+// less spare capacity win so larger vehicles stay free for larger groups.
+func Rank(candidates []DriverLocation, q NearbyQuery) []Match {
+	matches := make([]Match, 0, len(candidates))
+	for _, d := range candidates {
+		if q.VehicleClass != "" && d.VehicleClass != q.VehicleClass {
+			continue
+		}
+		capacity := d.EffectiveCapacity()
+		if !capacity.Satisfies(q.Requirements) {
+			continue
+		}
+		distance := q.Center.DistanceKm(d.Location)
+		if distance > q.RadiusKm {
+			continue
+		}
+
+		passengers := q.Requirements.Passengers
+		if passengers == 0 {
+			passengers = 1
+		}
+		spareSeats := float64(capacity.Seats - passengers)
+		spareLuggage := float64(capacity.Luggage - q.Requirements.Luggage)
+
+		score := 1 - distance/q.RadiusKm
+		score -= 0.02*spareSeats + 0.01*spareLuggage
+
+		matches = append(matches, Match{Driver: d, DistanceKm: distance, Score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[:q.Limit]
+	}
+	return matches
+}
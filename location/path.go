@@ -0,0 +1,178 @@
+package location
+
+import (
+	"math"
+	"time"
+)
+
+// Route is an ordered path of points with its length, duration and extent
+type Route struct {
+	Points     []Location    `json:"points"`
+	DistanceKm float64       `json:"distanceKm"`
+	Duration   time.Duration `json:"duration"`
+	Bounds     Bounds        `json:"bounds"`
+}
+
+// NewRoute builds a route from points, computing its distance and bounds
+func NewRoute(points []Location, duration time.Duration) Route {
+	return Route{
+		Points:     points,
+		DistanceKm: PathLengthKm(points),
+		Duration:   duration,
+		Bounds:     BoundsOf(points),
+	}
+}
+
+// RouteFromPolyline decodes an encoded polyline into a route
+func RouteFromPolyline(encoded string, duration time.Duration) (Route, error) {
+	points, err := DecodePolyline(encoded)
+	if err != nil {
+		return Route{}, err
+	}
+	return NewRoute(points, duration), nil
+}
+
+// Polyline returns the route as a Google encoded polyline
+func (r Route) Polyline() string {
+	return EncodePolyline(r.Points)
+}
+
+// PathLengthKm returns the great-circle length of a path
+func PathLengthKm(points []Location) float64 {
+	total := 0.0
+	for i := 1; i < len(points); i++ {
+		total += HaversineKm(points[i-1], points[i])
+	}
+	return total
+}
+
+// BoundsOf returns the rectangle enclosing points
+func BoundsOf(points []Location) Bounds {
+	if len(points) == 0 {
+		return Bounds{}
+	}
+	b := Bounds{SouthWest: points[0], NorthEast: points[0]}
+	for _, p := range points[1:] {
+		b.SouthWest.Latitude = math.Min(b.SouthWest.Latitude, p.Latitude)
+		b.SouthWest.Longitude = math.Min(b.SouthWest.Longitude, p.Longitude)
+		b.NorthEast.Latitude = math.Max(b.NorthEast.Latitude, p.Latitude)
+		b.NorthEast.Longitude = math.Max(b.NorthEast.Longitude, p.Longitude)
+	}
+	return b
+}
+
+// planar projects loc to kilometres on a plane tangent at origin; accurate
+// enough for the short segments of a city route
+func planar(origin, loc Location) (x, y float64) {
+	x = toRadians(loc.Longitude-origin.Longitude) * math.Cos(toRadians(origin.Latitude)) * EarthRadiusKm
+	y = toRadians(loc.Latitude-origin.Latitude) * EarthRadiusKm
+	return x, y
+}
+
+// projectOnSegment returns the closest point to loc on segment a-b and how
+// far along the segment it lies, from 0 to 1
+func projectOnSegment(loc, a, b Location) (Location, float64) {
+	bx, by := planar(a, b)
+	px, py := planar(a, loc)
+	lengthSq := bx*bx + by*by
+	if lengthSq == 0 {
+		return a, 0
+	}
+	t := math.Max(0, math.Min(1, (px*bx+py*by)/lengthSq))
+	return Location{
+		Latitude:  a.Latitude + t*(b.Latitude-a.Latitude),
+		Longitude: a.Longitude + t*(b.Longitude-a.Longitude),
+	}, t
+}
+
+// Simplify reduces points with the Douglas-Peucker algorithm, dropping points
+// that deviate less than toleranceMeters from the simplified path
+func Simplify(points []Location, toleranceMeters float64) []Location {
+	if len(points) < 3 || toleranceMeters <= 0 {
+		return points
+	}
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	simplifyRange(points, 0, len(points)-1, toleranceMeters/1000, keep)
+
+	simplified := make([]Location, 0, len(points))
+	for i, p := range points {
+		if keep[i] {
+			simplified = append(simplified, p)
+		}
+	}
+	return simplified
+}
+
+func simplifyRange(points []Location, first, last int, toleranceKm float64, keep []bool) {
+	maxDistance, index := 0.0, -1
+	for i := first + 1; i < last; i++ {
+		snapped, _ := projectOnSegment(points[i], points[first], points[last])
+		if d := HaversineKm(points[i], snapped); d > maxDistance {
+			maxDistance, index = d, i
+		}
+	}
+	if index < 0 || maxDistance <= toleranceKm {
+		return
+	}
+	keep[index] = true
+	simplifyRange(points, first, index, toleranceKm, keep)
+	simplifyRange(points, index, last, toleranceKm, keep)
+}
+
+// Simplify returns a copy of the route with a Douglas-Peucker simplified path;
+// distance is recomputed, duration is kept
+func (r Route) Simplify(toleranceMeters float64) Route {
+	return NewRoute(Simplify(r.Points, toleranceMeters), r.Duration)
+}
+
+// Snap is the closest point of a route to a location
+type Snap struct {
+	// Location is the point on the route
+	Location Location `json:"location"`
+	// Segment is the index of the segment's first point
+	Segment int `json:"segment"`
+	// OffsetKm is the distance from the original location to the route
+	OffsetKm float64 `json:"offsetKm"`
+	// AlongKm is the distance from the start of the route to Location
+	AlongKm float64 `json:"alongKm"`
+}
+
+// SnapToRoute returns the point of the route closest to loc
+func (r Route) SnapToRoute(loc Location) (Snap, bool) {
+	if len(r.Points) == 0 {
+		return Snap{}, false
+	}
+	if len(r.Points) == 1 {
+		return Snap{Location: r.Points[0], OffsetKm: HaversineKm(loc, r.Points[0])}, true
+	}
+
+	best := Snap{OffsetKm: math.Inf(1)}
+	along := 0.0
+	for i := 1; i < len(r.Points); i++ {
+		a, b := r.Points[i-1], r.Points[i]
+		snapped, _ := projectOnSegment(loc, a, b)
+		if offset := HaversineKm(loc, snapped); offset < best.OffsetKm {
+			best = Snap{
+				Location: snapped,
+				Segment:  i - 1,
+				OffsetKm: offset,
+				AlongKm:  along + HaversineKm(a, snapped),
+			}
+		}
+		along += HaversineKm(a, b)
+	}
+	return best, true
+}
+
+// DistanceAlong returns how far along the route the point closest to loc is,
+// e.g. to track trip progress
+func (r Route) DistanceAlong(loc Location) float64 {
+	snap, _ := r.SnapToRoute(loc)
+	return snap.AlongKm
+}
+
+// RemainingKm returns the route distance left after the point closest to loc
+func (r Route) RemainingKm(loc Location) float64 {
+	return math.Max(0, r.DistanceKm-r.DistanceAlong(loc))
+}
@@ -0,0 +1,285 @@
+package location
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Polygon is an outer ring with optional holes. Rings are listed in order
+// and are closed implicitly; a repeated closing vertex is tolerated.
+type Polygon struct {
+	Outer []Location
+	Holes [][]Location
+}
+
+// MultiPolygon is a set of disjoint polygons such as a service area split
+// by a river
+type MultiPolygon []Polygon
+
+// NewPolygon creates a polygon from an outer ring and optional holes
+func NewPolygon(outer []Location, holes ...[]Location) Polygon {
+	return Polygon{Outer: outer, Holes: holes}
+}
+
+// RingContains reports whether loc lies inside ring using ray casting on
+// plain latitude/longitude, which is accurate for city-sized areas that do
+// not cross the antimeridian
+func RingContains(ring []Location, loc Location) bool {
+	if len(ring) < 3 {
+		return false
+	}
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Latitude > loc.Latitude) != (b.Latitude > loc.Latitude) {
+			crossing := (b.Longitude-a.Longitude)*(loc.Latitude-a.Latitude)/(b.Latitude-a.Latitude) + a.Longitude
+			if loc.Longitude < crossing {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// Contains reports whether loc lies inside the outer ring and outside every hole
+func (p Polygon) Contains(loc Location) bool {
+	if !RingContains(p.Outer, loc) {
+		return false
+	}
+	for _, hole := range p.Holes {
+		if RingContains(hole, loc) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds returns the rectangle enclosing the outer ring
+func (p Polygon) Bounds() Bounds {
+	return BoundsOf(p.Outer)
+}
+
+// ringMoments returns the signed planar area in km² of ring projected around
+// origin, and its area-weighted centroid in the same plane
+func ringMoments(origin Location, ring []Location) (area, cx, cy float64) {
+	for i := range ring {
+		x1, y1 := planar(origin, ring[i])
+		x2, y2 := planar(origin, ring[(i+1)%len(ring)])
+		cross := x1*y2 - x2*y1
+		area += cross
+		cx += (x1 + x2) * cross
+		cy += (y1 + y2) * cross
+	}
+	return area / 2, cx, cy
+}
+
+// AreaKm2 returns the polygon's area in square kilometres, holes excluded
+func (p Polygon) AreaKm2() float64 {
+	if len(p.Outer) < 3 {
+		return 0
+	}
+	origin := p.Bounds().Center()
+	outer, _, _ := ringMoments(origin, p.Outer)
+	area := math.Abs(outer)
+	for _, hole := range p.Holes {
+		h, _, _ := ringMoments(origin, hole)
+		area -= math.Abs(h)
+	}
+	return math.Max(0, area)
+}
+
+// Centroid returns the polygon's center of mass, holes excluded
+func (p Polygon) Centroid() Location {
+	if len(p.Outer) < 3 {
+		return p.Bounds().Center()
+	}
+	origin := p.Bounds().Center()
+	area, cx, cy := orientedMoments(origin, p.Outer, 1)
+	for _, hole := range p.Holes {
+		a, x, y := orientedMoments(origin, hole, -1)
+		area, cx, cy = area+a, cx+x, cy+y
+	}
+	if area == 0 {
+		return origin
+	}
+	return fromPlanar(origin, cx/(6*area), cy/(6*area))
+}
+
+// orientedMoments returns ringMoments normalised so the area has sign
+func orientedMoments(origin Location, ring []Location, sign float64) (area, cx, cy float64) {
+	area, cx, cy = ringMoments(origin, ring)
+	if area*sign < 0 {
+		area, cx, cy = -area, -cx, -cy
+	}
+	return area, cx, cy
+}
+
+// fromPlanar inverts planar
+func fromPlanar(origin Location, x, y float64) Location {
+	return Location{
+		Latitude:  origin.Latitude + toDegrees(y/EarthRadiusKm),
+		Longitude: origin.Longitude + toDegrees(x/(EarthRadiusKm*math.Cos(toRadians(origin.Latitude)))),
+	}
+}
+
+// Contains reports whether loc lies inside any of the polygons
+func (m MultiPolygon) Contains(loc Location) bool {
+	for _, p := range m {
+		if p.Contains(loc) {
+			return true
+		}
+	}
+	return false
+}
+
+// AreaKm2 returns the combined area of the polygons
+func (m MultiPolygon) AreaKm2() float64 {
+	total := 0.0
+	for _, p := range m {
+		total += p.AreaKm2()
+	}
+	return total
+}
+
+// Centroid returns the area-weighted centroid of the polygons
+func (m MultiPolygon) Centroid() Location {
+	var lat, lng, total float64
+	for _, p := range m {
+		area := p.AreaKm2()
+		c := p.Centroid()
+		lat += c.Latitude * area
+		lng += c.Longitude * area
+		total += area
+	}
+	if total == 0 {
+		return BoundsOf(m.vertices()).Center()
+	}
+	return Location{Latitude: lat / total, Longitude: lng / total}
+}
+
+// Bounds returns the rectangle enclosing every polygon
+func (m MultiPolygon) Bounds() Bounds {
+	return BoundsOf(m.vertices())
+}
+
+func (m MultiPolygon) vertices() []Location {
+	var all []Location
+	for _, p := range m {
+		all = append(all, p.Outer...)
+	}
+	return all
+}
+
+// toPositions converts a ring to closed GeoJSON [lng, lat] positions
+func toPositions(ring []Location) [][2]float64 {
+	positions := make([][2]float64, 0, len(ring)+1)
+	for _, loc := range ring {
+		positions = append(positions, [2]float64{loc.Longitude, loc.Latitude})
+	}
+	if len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+		positions = append(positions, positions[0])
+	}
+	return positions
+}
+
+// fromPositions converts GeoJSON positions to a ring without the closing vertex
+func fromPositions(positions [][2]float64) []Location {
+	ring := make([]Location, 0, len(positions))
+	for _, p := range positions {
+		ring = append(ring, Location{Latitude: p[1], Longitude: p[0]})
+	}
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		ring = ring[:len(ring)-1]
+	}
+	return ring
+}
+
+func (p Polygon) coordinates() [][][2]float64 {
+	rings := [][][2]float64{toPositions(p.Outer)}
+	for _, hole := range p.Holes {
+		rings = append(rings, toPositions(hole))
+	}
+	return rings
+}
+
+func polygonFromCoordinates(rings [][][2]float64) (Polygon, error) {
+	if len(rings) == 0 {
+		return Polygon{}, fmt.Errorf("polygon has no rings")
+	}
+	p := Polygon{Outer: fromPositions(rings[0])}
+	for _, ring := range rings[1:] {
+		p.Holes = append(p.Holes, fromPositions(ring))
+	}
+	return p, nil
+}
+
+// ToGeoJSON returns the polygon as a GeoJSON Polygon geometry
+func (p Polygon) ToGeoJSON() Geometry {
+	return newGeometry(GeometryPolygon, p.coordinates())
+}
+
+// MarshalJSON encodes the polygon as a GeoJSON Polygon geometry
+func (p Polygon) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.ToGeoJSON())
+}
+
+// UnmarshalJSON decodes a GeoJSON Polygon geometry
+func (p *Polygon) UnmarshalJSON(data []byte) error {
+	var rings [][][2]float64
+	if err := decodeGeometry(data, GeometryPolygon, &rings); err != nil {
+		return err
+	}
+	decoded, err := polygonFromCoordinates(rings)
+	if err != nil {
+		return err
+	}
+	*p = decoded
+	return nil
+}
+
+// ToGeoJSON returns the polygons as a GeoJSON MultiPolygon geometry
+func (m MultiPolygon) ToGeoJSON() Geometry {
+	coordinates := make([][][][2]float64, 0, len(m))
+	for _, p := range m {
+		coordinates = append(coordinates, p.coordinates())
+	}
+	return newGeometry(GeometryMultiPolygon, coordinates)
+}
+
+// MarshalJSON encodes the polygons as a GeoJSON MultiPolygon geometry
+func (m MultiPolygon) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.ToGeoJSON())
+}
+
+// UnmarshalJSON decodes a GeoJSON MultiPolygon geometry; a plain Polygon is
+// accepted as a single-element MultiPolygon
+func (m *MultiPolygon) UnmarshalJSON(data []byte) error {
+	var g Geometry
+	if err := json.Unmarshal(data, &g); err != nil {
+		return fmt.Errorf("failed to decode GeoJSON MultiPolygon: %w", err)
+	}
+	if g.Type == GeometryPolygon {
+		var p Polygon
+		if err := p.UnmarshalJSON(data); err != nil {
+			return err
+		}
+		*m = MultiPolygon{p}
+		return nil
+	}
+
+	var polygons [][][][2]float64
+	if err := decodeGeometry(data, GeometryMultiPolygon, &polygons); err != nil {
+		return err
+	}
+	decoded := make(MultiPolygon, 0, len(polygons))
+	for _, rings := range polygons {
+		p, err := polygonFromCoordinates(rings)
+		if err != nil {
+			return fmt.Errorf("failed to decode GeoJSON MultiPolygon: %w", err)
+		}
+		decoded = append(decoded, p)
+	}
+	*m = decoded
+	return nil
+}
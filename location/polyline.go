@@ -0,0 +1,84 @@
+package location
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// ErrInvalidPolyline is returned for malformed encoded polylines
+var ErrInvalidPolyline = errors.New("location: invalid polyline")
+
+// polylineFactor is the precision of Google encoded polylines (5 decimals)
+const polylineFactor = 1e5
+
+// EncodePolyline encodes points in the Google encoded polyline format
+func EncodePolyline(points []Location) string {
+	var b strings.Builder
+	var prevLat, prevLng int64
+	for _, p := range points {
+		lat := int64(math.Round(p.Latitude * polylineFactor))
+		lng := int64(math.Round(p.Longitude * polylineFactor))
+		encodePolylineValue(&b, lat-prevLat)
+		encodePolylineValue(&b, lng-prevLng)
+		prevLat, prevLng = lat, lng
+	}
+	return b.String()
+}
+
+func encodePolylineValue(b *strings.Builder, v int64) {
+	u := v << 1
+	if v < 0 {
+		u = ^u
+	}
+	for u >= 0x20 {
+		b.WriteByte(byte((0x20 | (u & 0x1f)) + 63))
+		u >>= 5
+	}
+	b.WriteByte(byte(u + 63))
+}
+
+// DecodePolyline decodes a Google encoded polyline
+func DecodePolyline(encoded string) ([]Location, error) {
+	var points []Location
+	var lat, lng int64
+	for i := 0; i < len(encoded); {
+		dLat, next, err := decodePolylineValue(encoded, i)
+		if err != nil {
+			return nil, err
+		}
+		dLng, next, err := decodePolylineValue(encoded, next)
+		if err != nil {
+			return nil, err
+		}
+		i = next
+		lat += dLat
+		lng += dLng
+		points = append(points, Location{Latitude: float64(lat) / polylineFactor, Longitude: float64(lng) / polylineFactor})
+	}
+	return points, nil
+}
+
+func decodePolylineValue(encoded string, i int) (int64, int, error) {
+	var result int64
+	var shift uint
+	for {
+		if i >= len(encoded) || shift > 60 {
+			return 0, 0, ErrInvalidPolyline
+		}
+		c := int64(encoded[i]) - 63
+		i++
+		if c < 0 {
+			return 0, 0, ErrInvalidPolyline
+		}
+		result |= (c & 0x1f) << shift
+		shift += 5
+		if c < 0x20 {
+			break
+		}
+	}
+	if result&1 != 0 {
+		return ^(result >> 1), i, nil
+	}
+	return result >> 1, i, nil
+}
@@ -0,0 +1,155 @@
+package location
+
+import (
+	"crypto/hmac"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// PrivacyPolicy controls how precisely a location is revealed
+type PrivacyPolicy struct {
+	// Exact disables all reduction
+	Exact bool `json:"exact"`
+	// Precision is the number of decimal places kept (3 ≈ 110 m, 2 ≈ 1.1 km); 0 keeps full precision
+	Precision int `json:"precision"`
+	// FuzzRadiusMeters offsets the position by up to this distance
+	FuzzRadiusMeters float64 `json:"fuzzRadiusMeters"`
+	// FuzzWindow keeps the offset stable for this long so averaging samples reveals nothing
+	FuzzWindow time.Duration `json:"fuzzWindow"`
+	// FuzzSecret keys the stable offset so it cannot be recomputed from the
+	// subject ID; share it across instances. When empty a random per-process
+	// key is used, so instances fuzz independently.
+	FuzzSecret string `json:"-"`
+}
+
+// processFuzzSecret keys FuzzStable when no secret is configured
+var processFuzzSecret = func() string {
+	b := make([]byte, 32)
+	if _, err := crand.Read(b); err != nil {
+		panic("location: failed to generate fuzz secret: " + err.Error())
+	}
+	return string(b)
+}()
+
+// ExactPolicy reveals the exact location
+var ExactPolicy = PrivacyPolicy{Exact: true}
+
+// PrivacyConfig selects a policy per outbound message type
+type PrivacyConfig struct {
+	// Default applies to message types without an explicit policy
+	Default PrivacyPolicy `json:"default"`
+	// ByMessageType maps a WebSocket/notification message type to its policy
+	ByMessageType map[string]PrivacyPolicy `json:"byMessageType"`
+}
+
+// DefaultPrivacyConfig coarsens and fuzzes driver positions shown before assignment
+func DefaultPrivacyConfig() PrivacyConfig {
+	return PrivacyConfig{
+		Default: PrivacyPolicy{
+			Precision:        3,
+			FuzzRadiusMeters: 150,
+			FuzzWindow:       time.Minute,
+		},
+		ByMessageType: map[string]PrivacyPolicy{},
+	}
+}
+
+// PolicyFor returns the policy for a message type
+func (c PrivacyConfig) PolicyFor(messageType string) PrivacyPolicy {
+	if policy, ok := c.ByMessageType[messageType]; ok {
+		return policy
+	}
+	return c.Default
+}
+
+// Apply reduces loc for a rider-facing message. Once the driver is assigned
+// to the viewer's trip the exact position is returned. subjectID (e.g. the
+// driver ID) keys the fuzz offset so each driver gets an independent offset.
+func (c PrivacyConfig) Apply(messageType, subjectID string, loc Location, assigned bool) Location {
+	if assigned {
+		return loc
+	}
+	return c.PolicyFor(messageType).Apply(subjectID, loc)
+}
+
+// Apply reduces loc according to the policy
+func (p PrivacyPolicy) Apply(subjectID string, loc Location) Location {
+	if p.Exact {
+		return loc
+	}
+	if p.FuzzRadiusMeters > 0 {
+		loc = FuzzStable(loc, p.FuzzRadiusMeters, p.FuzzSecret, subjectID, p.FuzzWindow, time.Now())
+	}
+	if p.Precision > 0 {
+		loc = RoundToPrecision(loc, p.Precision)
+	}
+	return loc
+}
+
+// RoundToPrecision truncates coordinates to the given number of decimal places
+func RoundToPrecision(loc Location, decimals int) Location {
+	factor := math.Pow(10, float64(decimals))
+	return Location{
+		Latitude:  math.Round(loc.Latitude*factor) / factor,
+		Longitude: math.Round(loc.Longitude*factor) / factor,
+	}
+}
+
+// Fuzz offsets loc by a random distance of up to radiusMeters
+func Fuzz(loc Location, radiusMeters float64) Location {
+	return offset(loc, radiusMeters, rand.Float64(), rand.Float64())
+}
+
+// FuzzStable offsets loc by a pseudo-random distance of up to radiusMeters
+// that stays the same for subjectID within each window, so repeated samples
+// cannot be averaged back to the true position. The offset is an HMAC of the
+// subject and window keyed with secret, so it cannot be recomputed without
+// it; an empty secret uses a random per-process key.
+func FuzzStable(loc Location, radiusMeters float64, secret, subjectID string, window time.Duration, now time.Time) Location {
+	bucket := int64(0)
+	if window > 0 {
+		bucket = now.UnixNano() / int64(window)
+	}
+	if secret == "" {
+		secret = processFuzzSecret
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(subjectID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(bucket, 10)))
+	sum := mac.Sum(nil)
+
+	return offset(loc, radiusMeters, unitFloat(sum[0:8]), unitFloat(sum[8:16]))
+}
+
+// unitFloat maps 8 bytes to a float in [0, 1)
+func unitFloat(b []byte) float64 {
+	return float64(binary.BigEndian.Uint64(b)>>11) / (1 << 53)
+}
+
+// offset moves loc by distance sqrt(u)*radius in direction v*2π,
+// which is uniform over the disc
+func offset(loc Location, radiusMeters, u, v float64) Location {
+	distance := radiusMeters * math.Sqrt(u) / 1000 / EarthRadiusKm
+	bearing := v * 2 * math.Pi
+
+	lat := toRadians(loc.Latitude)
+	lng := toRadians(loc.Longitude)
+
+	newLat := math.Asin(math.Sin(lat)*math.Cos(distance) + math.Cos(lat)*math.Sin(distance)*math.Cos(bearing))
+	newLng := lng + math.Atan2(
+		math.Sin(bearing)*math.Sin(distance)*math.Cos(lat),
+		math.Cos(distance)-math.Sin(lat)*math.Sin(newLat),
+	)
+
+	return Location{
+		Latitude:  toDegrees(newLat),
+		Longitude: math.Mod(toDegrees(newLng)+540, 360) - 180,
+	}
+}
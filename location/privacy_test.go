@@ -0,0 +1,25 @@
+package location
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFuzzStableDependsOnSecret(t *testing.T) {
+	loc := NewLocation(18.52, 73.85)
+	now := time.Unix(1_700_000_000, 0)
+
+	a := FuzzStable(loc, 150, "secret-a", "driver-1", time.Minute, now)
+	again := FuzzStable(loc, 150, "secret-a", "driver-1", time.Minute, now.Add(time.Second))
+	b := FuzzStable(loc, 150, "secret-b", "driver-1", time.Minute, now)
+
+	if a != again {
+		t.Errorf("offset changed within the window: %v vs %v", a, again)
+	}
+	if a == b {
+		t.Errorf("offset does not depend on the secret: %v", a)
+	}
+	if d := loc.DistanceKm(a) * 1000; d > 150 {
+		t.Errorf("offset %.1fm exceeds the 150m radius", d)
+	}
+}
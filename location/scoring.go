@@ -0,0 +1,128 @@
+package location
+
+import "sort"
+
+// ScoringConfig weights the components of a driver's matching score. Each
+// component is normalised to [0, 1] before weighting.
+type ScoringConfig struct {
+	DistanceWeight float64
+	RatingWeight   float64
+	// VehicleWeight rewards drivers whose class matches the requested one
+	VehicleWeight float64
+	// StatusWeight rewards drivers that are available right now
+	StatusWeight float64
+	// AcceptanceRateFactor scales the score down by how often the driver
+	// declines: 0 ignores acceptance, 1 multiplies the score by the rate
+	AcceptanceRateFactor float64
+	// MaxDistanceKm is the distance at which the distance component reaches 0
+	MaxDistanceKm float64
+	// MaxRating is the top of the rating scale
+	MaxRating float64
+}
+
+// DefaultScoringConfig returns the weights used by dispatch
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		DistanceWeight:       0.5,
+		RatingWeight:         0.2,
+		VehicleWeight:        0.2,
+		StatusWeight:         0.1,
+		AcceptanceRateFactor: 0.5,
+		MaxDistanceKm:        10,
+		MaxRating:            5,
+	}
+}
+
+func (c ScoringConfig) withDefaults() ScoringConfig {
+	defaults := DefaultScoringConfig()
+	if c.DistanceWeight == 0 && c.RatingWeight == 0 && c.VehicleWeight == 0 && c.StatusWeight == 0 {
+		c.DistanceWeight = defaults.DistanceWeight
+		c.RatingWeight = defaults.RatingWeight
+		c.VehicleWeight = defaults.VehicleWeight
+		c.StatusWeight = defaults.StatusWeight
+	}
+	if c.MaxDistanceKm <= 0 {
+		c.MaxDistanceKm = defaults.MaxDistanceKm
+	}
+	if c.MaxRating <= 0 {
+		c.MaxRating = defaults.MaxRating
+	}
+	return c
+}
+
+// ScoringCandidate is a driver with the signals used for scoring
+type ScoringCandidate struct {
+	Driver DriverLocation `json:"driver"`
+	Rating float64        `json:"rating"`
+	// AcceptanceRate is between 0 and 1; 0 means unknown and is not penalised
+	AcceptanceRate float64 `json:"acceptanceRate"`
+	Available      bool    `json:"available"`
+}
+
+// ScoredDriver is a scored candidate; higher scores are better
+type ScoredDriver struct {
+	ScoringCandidate
+	DistanceKm float64 `json:"distanceKm"`
+	Score      float64 `json:"score"`
+}
+
+// Score returns the candidate's score for a pickup, and its distance to it
+func (c ScoringConfig) Score(candidate ScoringCandidate, pickup Location, vehicleClass string) (score, distanceKm float64) {
+	c = c.withDefaults()
+	distanceKm = pickup.DistanceKm(candidate.Driver.Location)
+
+	if distanceKm < c.MaxDistanceKm {
+		score += c.DistanceWeight * (1 - distanceKm/c.MaxDistanceKm)
+	}
+	if candidate.Rating > 0 {
+		score += c.RatingWeight * min(candidate.Rating/c.MaxRating, 1)
+	}
+	if vehicleClass == "" || candidate.Driver.VehicleClass == vehicleClass {
+		score += c.VehicleWeight
+	}
+	if candidate.Available {
+		score += c.StatusWeight
+	}
+	if rate := candidate.AcceptanceRate; rate > 0 {
+		score *= 1 - c.AcceptanceRateFactor*(1-min(rate, 1))
+	}
+	return score, distanceKm
+}
+
+// SortDriversByScore scores candidates for a pickup and returns them best
+// first; ties keep their input order
+func SortDriversByScore(candidates []ScoringCandidate, pickup Location, vehicleClass string, config ScoringConfig) []ScoredDriver {
+	config = config.withDefaults()
+	scored := make([]ScoredDriver, len(candidates))
+	for i, candidate := range candidates {
+		score, distance := config.Score(candidate, pickup, vehicleClass)
+		scored[i] = ScoredDriver{ScoringCandidate: candidate, DistanceKm: distance, Score: score}
+	}
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+	return scored
+}
+
+// SortLocationsByDistance returns a copy of locations ordered nearest first
+// from origin
+func SortLocationsByDistance(origin Location, locations []Location) []Location {
+	// distances are computed once up front rather than in the comparator
+	type entry struct {
+		loc      Location
+		distance float64
+	}
+	entries := make([]entry, len(locations))
+	for i, loc := range locations {
+		entries[i] = entry{loc: loc, distance: origin.DistanceKm(loc)}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].distance < entries[j].distance
+	})
+
+	sorted := make([]Location, len(entries))
+	for i, e := range entries {
+		sorted[i] = e.loc
+	}
+	return sorted
+}
@@ -0,0 +1,68 @@
+package location
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	common "github.com/mihirk-khode/motocabz-common"
+)
+
+// benchmarkSizes compares 1k and 10k inputs: ns/op should grow by roughly
+// 10·log(10k)/log(1k) ≈ 13x for an n log n sort, not 100x
+var benchmarkSizes = []int{1_000, 10_000}
+
+func randomLocations(n int) []Location {
+	r := rand.New(rand.NewSource(1))
+	locations := make([]Location, n)
+	for i := range locations {
+		locations[i] = NewLocation(18.4+r.Float64()*0.4, 73.7+r.Float64()*0.4)
+	}
+	return locations
+}
+
+func randomCandidates(n int) []ScoringCandidate {
+	r := rand.New(rand.NewSource(1))
+	classes := []string{common.VehicleClassEconomy, common.VehicleClassComfort, common.VehicleClassMinivan, common.VehicleClassLuxury}
+	candidates := make([]ScoringCandidate, n)
+	for i, loc := range randomLocations(n) {
+		candidates[i] = ScoringCandidate{
+			Driver: DriverLocation{
+				DriverID:     fmt.Sprintf("driver-%d", i),
+				Location:     loc,
+				VehicleClass: classes[r.Intn(len(classes))],
+			},
+			Rating:         3 + r.Float64()*2,
+			AcceptanceRate: r.Float64(),
+			Available:      r.Intn(4) != 0,
+		}
+	}
+	return candidates
+}
+
+func BenchmarkSortDriversByScore(b *testing.B) {
+	pickup := NewLocation(18.52, 73.85)
+	config := DefaultScoringConfig()
+	for _, n := range benchmarkSizes {
+		candidates := randomCandidates(n)
+		b.Run(fmt.Sprintf("drivers=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				SortDriversByScore(candidates, pickup, common.VehicleClassComfort, config)
+			}
+		})
+	}
+}
+
+func BenchmarkSortLocationsByDistance(b *testing.B) {
+	origin := NewLocation(18.52, 73.85)
+	for _, n := range benchmarkSizes {
+		locations := randomLocations(n)
+		b.Run(fmt.Sprintf("locations=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				SortLocationsByDistance(origin, locations)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package location
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Ping is a raw GPS fix reported by a device
+type Ping struct {
+	Location Location  `json:"location"`
+	At       time.Time `json:"at"`
+	// AccuracyMeters is the reported horizontal accuracy; 0 means unknown
+	AccuracyMeters float64 `json:"accuracyMeters,omitempty"`
+}
+
+// SmootherConfig configures GPS smoothing and outlier rejection
+type SmootherConfig struct {
+	// MaxSpeedKmh rejects pings implying a faster jump from the last accepted fix
+	MaxSpeedKmh float64
+	// MaxRejections accepts the next ping unfiltered after this many
+	// consecutive rejections, so a genuine jump (e.g. after a tunnel)
+	// does not freeze the driver in place
+	MaxRejections int
+	// ProcessNoise is the expected movement uncertainty in metres per second;
+	// higher values follow the raw pings more closely
+	ProcessNoise float64
+	// DefaultAccuracyMeters is used for pings without a reported accuracy
+	DefaultAccuracyMeters float64
+	// ResetAfter discards a subject's state when pings stop for this long
+	ResetAfter time.Duration
+}
+
+// DefaultSmootherConfig returns settings tuned for vehicles in city traffic
+func DefaultSmootherConfig() SmootherConfig {
+	return SmootherConfig{
+		MaxSpeedKmh:           200,
+		MaxRejections:         3,
+		ProcessNoise:          3,
+		DefaultAccuracyMeters: 15,
+		ResetAfter:            5 * time.Minute,
+	}
+}
+
+// smootherState is a per-subject Kalman filter with a shared variance for
+// latitude and longitude, expressed in metres²
+type smootherState struct {
+	estimate   Location
+	variance   float64
+	lastRaw    Ping
+	at         time.Time
+	rejections int
+}
+
+// Smoother filters driver pings with a simple Kalman filter and rejects
+// fixes implying impossible speeds. It is safe for concurrent use and keeps
+// state per subject (e.g. driver ID).
+type Smoother struct {
+	mu     sync.Mutex
+	config SmootherConfig
+	states map[string]*smootherState
+}
+
+// NewSmoother creates a new GPS smoother
+func NewSmoother(config SmootherConfig) *Smoother {
+	defaults := DefaultSmootherConfig()
+	if config.MaxSpeedKmh <= 0 {
+		config.MaxSpeedKmh = defaults.MaxSpeedKmh
+	}
+	if config.MaxRejections <= 0 {
+		config.MaxRejections = defaults.MaxRejections
+	}
+	if config.ProcessNoise <= 0 {
+		config.ProcessNoise = defaults.ProcessNoise
+	}
+	if config.DefaultAccuracyMeters <= 0 {
+		config.DefaultAccuracyMeters = defaults.DefaultAccuracyMeters
+	}
+	if config.ResetAfter <= 0 {
+		config.ResetAfter = defaults.ResetAfter
+	}
+	return &Smoother{
+		config: config,
+		states: make(map[string]*smootherState),
+	}
+}
+
+// Update feeds a ping for subjectID and returns the smoothed location. ok is
+// false when the ping was rejected as an outlier or arrived out of order;
+// the returned location is then the last smoothed estimate and should not
+// be persisted as a new fix.
+func (s *Smoother) Update(subjectID string, ping Ping) (Location, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accuracy := ping.AccuracyMeters
+	if accuracy <= 0 {
+		accuracy = s.config.DefaultAccuracyMeters
+	}
+
+	state, ok := s.states[subjectID]
+	if !ok || ping.At.Sub(state.at) > s.config.ResetAfter {
+		s.states[subjectID] = &smootherState{estimate: ping.Location, variance: accuracy * accuracy, lastRaw: ping, at: ping.At}
+		return ping.Location, true
+	}
+	if !ping.At.After(state.at) {
+		return state.estimate, false
+	}
+
+	if s.isOutlier(state, ping) {
+		state.rejections++
+		if state.rejections <= s.config.MaxRejections {
+			return state.estimate, false
+		}
+		// the driver really is somewhere else; restart the filter there
+		*state = smootherState{estimate: ping.Location, variance: accuracy * accuracy, lastRaw: ping, at: ping.At}
+		return ping.Location, true
+	}
+
+	elapsed := ping.At.Sub(state.at).Seconds()
+	state.variance += elapsed * s.config.ProcessNoise * s.config.ProcessNoise
+	gain := state.variance / (state.variance + accuracy*accuracy)
+	state.estimate = Location{
+		Latitude:  state.estimate.Latitude + gain*(ping.Location.Latitude-state.estimate.Latitude),
+		Longitude: state.estimate.Longitude + gain*(ping.Location.Longitude-state.estimate.Longitude),
+	}
+	state.variance *= 1 - gain
+	state.lastRaw = ping
+	state.at = ping.At
+	state.rejections = 0
+	return state.estimate, true
+}
+
+// isOutlier reports whether reaching ping from the last accepted raw fix
+// needs an impossible speed; the reported accuracy of both fixes is allowed
+// as slack so a stationary driver with a noisy fix is not rejected
+func (s *Smoother) isOutlier(state *smootherState, ping Ping) bool {
+	elapsed := ping.At.Sub(state.lastRaw.At).Hours()
+	if elapsed <= 0 {
+		return true
+	}
+	slackKm := (ping.AccuracyMeters + state.lastRaw.AccuracyMeters) / 1000
+	distance := math.Max(0, HaversineKm(state.lastRaw.Location, ping.Location)-slackKm)
+	return distance/elapsed > s.config.MaxSpeedKmh
+}
+
+// Forget drops the state kept for subjectID, e.g. when a driver goes offline
+func (s *Smoother) Forget(subjectID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, subjectID)
+}
+
+// Prune drops state for subjects not updated since before cutoff and
+// returns how many were removed
+func (s *Smoother) Prune(cutoff time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for id, state := range s.states {
+		if state.at.Before(cutoff) {
+			delete(s.states, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// FilterOutliers returns the pings that pass speed gating against the
+// previously kept ping, for cleaning up a recorded track after the fact
+func FilterOutliers(pings []Ping, maxSpeedKmh float64) []Ping {
+	if maxSpeedKmh <= 0 {
+		maxSpeedKmh = DefaultSmootherConfig().MaxSpeedKmh
+	}
+	kept := make([]Ping, 0, len(pings))
+	for _, p := range pings {
+		if len(kept) > 0 {
+			last := kept[len(kept)-1]
+			elapsed := p.At.Sub(last.At).Hours()
+			if elapsed <= 0 || HaversineKm(last.Location, p.Location)/elapsed > maxSpeedKmh {
+				continue
+			}
+		}
+		kept = append(kept, p)
+	}
+	return kept
+}
@@ -0,0 +1,130 @@
+package location
+
+import (
+	"sort"
+	"time"
+)
+
+// Distance units
+const (
+	UnitKilometers = "km"
+	UnitMiles      = "mi"
+	UnitMeters     = "m"
+)
+
+const kmPerMile = 1.609344
+
+// ConvertDistance converts kilometres to unit; unknown units return km
+func ConvertDistance(km float64, unit string) float64 {
+	switch unit {
+	case UnitMiles:
+		return km / kmPerMile
+	case UnitMeters:
+		return km * 1000
+	}
+	return km
+}
+
+// TripMetricsConfig configures how a track is measured
+type TripMetricsConfig struct {
+	// IdleSpeedKmh is the speed below which a segment counts as idle; GPS
+	// drift while waiting at a light is not counted as distance
+	IdleSpeedKmh float64
+	// MaxSpeedKmh drops pings implying a faster jump, see FilterOutliers
+	MaxSpeedKmh float64
+	// MaxGap is the longest interval between pings still measured as travel;
+	// longer gaps (signal loss) count the straight-line distance and the
+	// time as idle, since the speed over the gap is unknown
+	MaxGap time.Duration
+}
+
+// DefaultTripMetricsConfig returns the settings used for fares and analytics
+func DefaultTripMetricsConfig() TripMetricsConfig {
+	return TripMetricsConfig{
+		IdleSpeedKmh: 3,
+		MaxSpeedKmh:  200,
+		MaxGap:       2 * time.Minute,
+	}
+}
+
+// TripMetrics summarises a recorded trip
+type TripMetrics struct {
+	DistanceKm float64       `json:"distanceKm"`
+	Duration   time.Duration `json:"duration"`
+	MovingTime time.Duration `json:"movingTime"`
+	IdleTime   time.Duration `json:"idleTime"`
+	// AverageSpeedKmh is over the whole duration, AverageMovingSpeedKmh over
+	// moving time only
+	AverageSpeedKmh       float64 `json:"averageSpeedKmh"`
+	AverageMovingSpeedKmh float64 `json:"averageMovingSpeedKmh"`
+	MaxSpeedKmh           float64 `json:"maxSpeedKmh"`
+	// Points is the number of pings used after outlier rejection
+	Points int `json:"points"`
+}
+
+// Distance returns the trip distance in unit
+func (m TripMetrics) Distance(unit string) float64 {
+	return ConvertDistance(m.DistanceKm, unit)
+}
+
+// AverageSpeed returns the average speed in unit per hour
+func (m TripMetrics) AverageSpeed(unit string) float64 {
+	return ConvertDistance(m.AverageSpeedKmh, unit)
+}
+
+// AverageMovingSpeed returns the moving average speed in unit per hour
+func (m TripMetrics) AverageMovingSpeed(unit string) float64 {
+	return ConvertDistance(m.AverageMovingSpeedKmh, unit)
+}
+
+// ComputeTripMetrics measures a trip from its pings, which are sorted by
+// time first and cleaned of impossible jumps
+func ComputeTripMetrics(pings []Ping, config TripMetricsConfig) TripMetrics {
+	defaults := DefaultTripMetricsConfig()
+	if config.IdleSpeedKmh <= 0 {
+		config.IdleSpeedKmh = defaults.IdleSpeedKmh
+	}
+	if config.MaxSpeedKmh <= 0 {
+		config.MaxSpeedKmh = defaults.MaxSpeedKmh
+	}
+	if config.MaxGap <= 0 {
+		config.MaxGap = defaults.MaxGap
+	}
+
+	sorted := make([]Ping, len(pings))
+	copy(sorted, pings)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+	sorted = FilterOutliers(sorted, config.MaxSpeedKmh)
+
+	m := TripMetrics{Points: len(sorted)}
+	if len(sorted) < 2 {
+		return m
+	}
+	m.Duration = sorted[len(sorted)-1].At.Sub(sorted[0].At)
+
+	for i := 1; i < len(sorted); i++ {
+		elapsed := sorted[i].At.Sub(sorted[i-1].At)
+		distance := HaversineKm(sorted[i-1].Location, sorted[i].Location)
+		speed := distance / elapsed.Hours()
+
+		switch {
+		case elapsed > config.MaxGap:
+			m.DistanceKm += distance
+			m.IdleTime += elapsed
+		case speed < config.IdleSpeedKmh:
+			m.IdleTime += elapsed
+		default:
+			m.DistanceKm += distance
+			m.MovingTime += elapsed
+			m.MaxSpeedKmh = max(m.MaxSpeedKmh, speed)
+		}
+	}
+
+	if m.Duration > 0 {
+		m.AverageSpeedKmh = m.DistanceKm / m.Duration.Hours()
+	}
+	if m.MovingTime > 0 {
+		m.AverageMovingSpeedKmh = m.DistanceKm / m.MovingTime.Hours()
+	}
+	return m
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	common "github.com/mihirk-khode/motocabz-common"
+)
+
+// DefaultMaxBodyBytes is the body limit applied when no route limit matches
+const DefaultMaxBodyBytes int64 = 1 << 20 // 1 MiB
+
+// BodyLimitConfig configures request body size limits per route group
+type BodyLimitConfig struct {
+	// DefaultMaxBytes applies to routes without an explicit limit
+	DefaultMaxBytes int64
+	// RouteLimits maps a path prefix (route group) to its max body size;
+	// the longest matching prefix wins
+	RouteLimits map[string]int64
+}
+
+// LimitFor returns the body limit for the given request path
+func (c BodyLimitConfig) LimitFor(path string) int64 {
+	if limit, ok := matchRoute(c.RouteLimits, path); ok {
+		return limit
+	}
+	if c.DefaultMaxBytes > 0 {
+		return c.DefaultMaxBytes
+	}
+	return DefaultMaxBodyBytes
+}
+
+// BodyLimit rejects requests whose body exceeds the configured limit.
+// Requests declaring a too large Content-Length are rejected immediately;
+// streamed bodies are capped with http.MaxBytesReader so handlers get an
+// error they can report with WriteBodyTooLarge.
+func BodyLimit(cfg BodyLimitConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := cfg.LimitFor(r.URL.Path)
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > limit {
+				WriteBodyTooLarge(w, limit)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsBodyTooLarge reports whether err was caused by exceeding the body limit
+func IsBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+// WriteBodyTooLarge writes a 413 RsBase response for the given limit
+func WriteBodyTooLarge(w http.ResponseWriter, limit int64) {
+	WriteJSON(w, http.StatusRequestEntityTooLarge, common.RsPayloadTooLarge(
+		fmt.Sprintf("request body exceeds the maximum allowed size of %d bytes", limit),
+	))
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/crashreport"
+)
+
+// ErrorMiddleware recovers panics from downstream handlers, forwards them to
+// the crash reporter and answers with a 500 RsBase response
+func ErrorMiddleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				// Let net/http abort the connection as it normally would
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				report := crashreport.NewReport(crashreport.SourceHTTP, r.Method+" "+r.URL.Path, recovered)
+				report.RequestID = r.Header.Get(HeaderRequestID)
+				report.TraceID = TraceIDFromHeader(r.Header.Get(HeaderTraceParent))
+				crashreport.Capture(r.Context(), report)
+
+				if rec.status == 0 {
+					WriteJSON(w, http.StatusInternalServerError, common.RsInternalErr("Internal server error", nil))
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Header names used to correlate slow requests
+const (
+	HeaderRequestID   = "X-Request-ID"
+	HeaderTraceParent = "traceparent"
+)
+
+// DefaultSlowRequestThreshold is the latency budget when none is configured
+const DefaultSlowRequestThreshold = 2 * time.Second
+
+// SlowRequest describes a request that exceeded its latency budget
+type SlowRequest struct {
+	Method    string        `json:"method"`
+	Path      string        `json:"path"`
+	Status    int           `json:"status,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Threshold time.Duration `json:"threshold"`
+	RequestID string        `json:"requestId,omitempty"`
+	TraceID   string        `json:"traceId,omitempty"`
+	TraceURL  string        `json:"traceUrl,omitempty"`
+	InFlight  bool          `json:"inFlight"` // true when reported before the handler returned
+}
+
+// SlowRequestConfig configures slow-request detection
+type SlowRequestConfig struct {
+	// Threshold is the default latency budget
+	Threshold time.Duration
+	// RouteThresholds overrides the budget per path prefix
+	RouteThresholds map[string]time.Duration
+	// TraceURLTemplate builds a trace link, e.g. "https://grafana/trace/%s"
+	TraceURLTemplate string
+	// OnSlowRequest is invoked for every slow request; defaults to logging
+	OnSlowRequest func(SlowRequest)
+}
+
+// ThresholdFor returns the latency budget for the given request path
+func (c SlowRequestConfig) ThresholdFor(path string) time.Duration {
+	if threshold, ok := matchRoute(c.RouteThresholds, path); ok {
+		return threshold
+	}
+	if c.Threshold > 0 {
+		return c.Threshold
+	}
+	return DefaultSlowRequestThreshold
+}
+
+// SlowRequestDetector flags handlers exceeding their latency budget. A handler
+// still running when the budget elapses is reported as in-flight so hung
+// handlers surface before they finish; it is reported again on completion.
+func SlowRequestDetector(cfg SlowRequestConfig) Middleware {
+	report := cfg.OnSlowRequest
+	if report == nil {
+		report = logSlowRequest
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			threshold := cfg.ThresholdFor(r.URL.Path)
+			start := time.Now()
+
+			traceID := TraceIDFromHeader(r.Header.Get(HeaderTraceParent))
+			entry := SlowRequest{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Threshold: threshold,
+				RequestID: r.Header.Get(HeaderRequestID),
+				TraceID:   traceID,
+			}
+			if traceID != "" && cfg.TraceURLTemplate != "" {
+				entry.TraceURL = fmt.Sprintf(cfg.TraceURLTemplate, traceID)
+			}
+
+			timer := time.AfterFunc(threshold, func() {
+				inFlight := entry
+				inFlight.Duration = time.Since(start)
+				inFlight.InFlight = true
+				report(inFlight)
+			})
+
+			recorder := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				timer.Stop()
+				duration := time.Since(start)
+				if duration > threshold {
+					completed := entry
+					completed.Duration = duration
+					completed.Status = recorder.status
+					report(completed)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+// TraceIDFromHeader extracts the trace ID from a W3C traceparent header
+func TraceIDFromHeader(traceParent string) string {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 {
+		return ""
+	}
+	return parts[1]
+}
+
+func logSlowRequest(req SlowRequest) {
+	state := "completed"
+	if req.InFlight {
+		state = "still running"
+	}
+	log.Printf("⚠️ Slow request %s %s %s after %v (budget %v, status %d, requestId=%s, trace=%s)",
+		req.Method, req.Path, state, req.Duration, req.Threshold, req.Status, req.RequestID, req.TraceURL)
+}
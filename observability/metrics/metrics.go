@@ -0,0 +1,53 @@
+package metrics
+
+import "sync"
+
+// Labels are the dimensions attached to a metric sample
+type Labels map[string]string
+
+// Provider records metrics; implementations adapt to Prometheus, OTel, etc.
+type Provider interface {
+	// IncCounter adds value to a monotonically increasing counter
+	IncCounter(name string, value float64, labels Labels)
+	// SetGauge sets the current value of a gauge
+	SetGauge(name string, value float64, labels Labels)
+	// ObserveHistogram records a sample in a histogram
+	ObserveHistogram(name string, value float64, labels Labels)
+}
+
+// NoopProvider discards all metrics
+type NoopProvider struct{}
+
+func (NoopProvider) IncCounter(name string, value float64, labels Labels)       {}
+func (NoopProvider) SetGauge(name string, value float64, labels Labels)         {}
+func (NoopProvider) ObserveHistogram(name string, value float64, labels Labels) {}
+
+var (
+	mu              sync.RWMutex
+	defaultProvider Provider = NoopProvider{}
+)
+
+// SetDefault sets the provider returned by Default
+func SetDefault(provider Provider) {
+	if provider == nil {
+		provider = NoopProvider{}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	defaultProvider = provider
+}
+
+// Default returns the process-wide metrics provider
+func Default() Provider {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultProvider
+}
+
+// OrDefault returns provider, or the process-wide provider when nil
+func OrDefault(provider Provider) Provider {
+	if provider == nil {
+		return Default()
+	}
+	return provider
+}
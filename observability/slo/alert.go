@@ -0,0 +1,69 @@
+package slo
+
+import (
+	"net/http"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/middleware"
+)
+
+// Alert severities
+const (
+	SeverityPage   = "page"
+	SeverityTicket = "ticket"
+)
+
+// AlertRule fires when the burn rate exceeds Threshold over both windows
+// (multi-window, multi-burn-rate alerting)
+type AlertRule struct {
+	Objective   string        `json:"objective"`
+	LongWindow  time.Duration `json:"longWindow"`
+	ShortWindow time.Duration `json:"shortWindow"`
+	Threshold   float64       `json:"threshold"`
+	Severity    string        `json:"severity"`
+}
+
+// Alert is a firing rule
+type Alert struct {
+	Rule          AlertRule `json:"rule"`
+	LongBurnRate  float64   `json:"longBurnRate"`
+	ShortBurnRate float64   `json:"shortBurnRate"`
+}
+
+// DefaultAlertRules returns the standard fast and slow burn rules for an objective
+func DefaultAlertRules(objective string) []AlertRule {
+	return []AlertRule{
+		{Objective: objective, LongWindow: time.Hour, ShortWindow: 5 * time.Minute, Threshold: 14.4, Severity: SeverityPage},
+		{Objective: objective, LongWindow: 6 * time.Hour, ShortWindow: 30 * time.Minute, Threshold: 6, Severity: SeverityPage},
+		{Objective: objective, LongWindow: 72 * time.Hour, ShortWindow: 6 * time.Hour, Threshold: 1, Severity: SeverityTicket},
+	}
+}
+
+// Evaluate returns the rules that are currently firing on this instance
+func (t *Tracker) Evaluate(rules ...AlertRule) []Alert {
+	var alerts []Alert
+	for _, rule := range rules {
+		long, ok := t.BurnRate(rule.Objective, rule.LongWindow)
+		if !ok || long < rule.Threshold {
+			continue
+		}
+		short, _ := t.BurnRate(rule.Objective, rule.ShortWindow)
+		if short < rule.Threshold {
+			continue
+		}
+		alerts = append(alerts, Alert{Rule: rule, LongBurnRate: long, ShortBurnRate: short})
+	}
+	return alerts
+}
+
+// Handler serves this instance's status of all objectives and firing alerts
+// for health pages
+func (t *Tracker) Handler(rules ...AlertRule) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		middleware.WriteJSON(w, http.StatusOK, common.RsOK(map[string]interface{}{
+			"objectives": t.Statuses(),
+			"alerts":     t.Evaluate(rules...),
+		}, "slo status"))
+	})
+}
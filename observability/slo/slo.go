@@ -0,0 +1,243 @@
+package slo
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+)
+
+// Metric names emitted by the tracker
+const (
+	MetricEvents  = "slo_events_total"
+	MetricLatency = "slo_latency_seconds"
+	MetricBurn    = "slo_burn_rate"
+)
+
+// bucketSize is the resolution of the in-memory event windows
+const bucketSize = time.Minute
+
+// Objective describes a service level objective, e.g. 99.5% of match
+// requests succeed in under 2s over 30 days
+type Objective struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Target is the fraction of good events, e.g. 0.995
+	Target float64 `json:"target"`
+	// LatencyThreshold marks slower successful events as bad; 0 disables it
+	LatencyThreshold time.Duration `json:"latencyThreshold,omitempty"`
+	// Window is the compliance period the error budget is computed over; it
+	// must be at least one minute
+	Window time.Duration `json:"window"`
+}
+
+// ErrorBudget returns the allowed fraction of bad events
+func (o Objective) ErrorBudget() float64 {
+	return 1 - o.Target
+}
+
+// Status is a point-in-time view of an objective
+type Status struct {
+	Objective Objective `json:"objective"`
+	Total     int64     `json:"total"`
+	Good      int64     `json:"good"`
+	// SLI is the observed fraction of good events; 1 when there is no traffic
+	SLI float64 `json:"sli"`
+	// BudgetRemaining is the unspent fraction of the error budget (may go negative)
+	BudgetRemaining float64 `json:"budgetRemaining"`
+	// BurnRate is how fast the budget is spent over the last hour; 1 means
+	// exactly on budget for the window
+	BurnRate float64 `json:"burnRate"`
+}
+
+type bucket struct {
+	minute int64
+	good   int64
+	total  int64
+}
+
+type series struct {
+	objective Objective
+	buckets   []bucket
+}
+
+// Tracker records events against objectives and computes budgets and burn
+// rates. Its buckets live in process memory, so Status, BurnRate and Evaluate
+// only see the events of this instance, and a restart starts them over. Use
+// them for local health pages and tests; fleet-wide SLOs and paging alerts
+// must be computed from the slo_events_total counter aggregated across
+// instances by the metrics backend.
+type Tracker struct {
+	mu      sync.RWMutex
+	series  map[string]*series
+	metrics metrics.Provider
+	now     func() time.Time
+}
+
+// NewTracker creates a tracker for objectives; provider may be nil
+func NewTracker(provider metrics.Provider, objectives ...Objective) (*Tracker, error) {
+	t := &Tracker{
+		series:  make(map[string]*series),
+		metrics: metrics.OrDefault(provider),
+		now:     time.Now,
+	}
+	for _, o := range objectives {
+		if err := t.Register(o); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Register adds an objective, replacing any existing one with the same name
+func (t *Tracker) Register(o Objective) error {
+	if o.Name == "" {
+		return fmt.Errorf("objective name is required")
+	}
+	if o.Target <= 0 || o.Target >= 1 {
+		return fmt.Errorf("objective %s: target must be between 0 and 1", o.Name)
+	}
+	if o.Window <= 0 {
+		o.Window = 30 * 24 * time.Hour
+	}
+	if o.Window < bucketSize {
+		return fmt.Errorf("objective %s: window must be at least %s", o.Name, bucketSize)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.series[o.Name] = &series{
+		objective: o,
+		buckets:   make([]bucket, int(o.Window/bucketSize)),
+	}
+	return nil
+}
+
+// Record records one event; an unknown objective is ignored
+func (t *Tracker) Record(name string, success bool, latency time.Duration) {
+	t.mu.Lock()
+	s, ok := t.series[name]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+
+	good := success && (s.objective.LatencyThreshold == 0 || latency <= s.objective.LatencyThreshold)
+	minute := t.now().Unix() / int64(bucketSize/time.Second)
+	b := &s.buckets[minute%int64(len(s.buckets))]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.total++
+	if good {
+		b.good++
+	}
+	t.mu.Unlock()
+
+	result := "bad"
+	if good {
+		result = "good"
+	}
+	t.metrics.IncCounter(MetricEvents, 1, metrics.Labels{"objective": name, "result": result})
+	if latency > 0 {
+		t.metrics.ObserveHistogram(MetricLatency, latency.Seconds(), metrics.Labels{"objective": name})
+	}
+}
+
+// Observe is a helper for deferred recording:
+//
+//	defer tracker.Observe("match", time.Now(), &err)
+func (t *Tracker) Observe(name string, start time.Time, errp *error) {
+	t.Record(name, errp == nil || *errp == nil, time.Since(start))
+}
+
+// counts sums good and total events over the trailing window
+func (s *series) counts(now time.Time, window time.Duration) (good, total int64) {
+	current := now.Unix() / int64(bucketSize/time.Second)
+	n := int64(window / bucketSize)
+	if n < 1 {
+		n = 1
+	}
+	if n > int64(len(s.buckets)) {
+		n = int64(len(s.buckets))
+	}
+	for _, b := range s.buckets {
+		if b.minute > current-n && b.minute <= current {
+			good += b.good
+			total += b.total
+		}
+	}
+	return good, total
+}
+
+func burnRate(o Objective, good, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return (float64(total-good) / float64(total)) / o.ErrorBudget()
+}
+
+// BurnRate returns the error budget burn rate over the trailing window
+func (t *Tracker) BurnRate(name string, window time.Duration) (float64, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s, ok := t.series[name]
+	if !ok {
+		return 0, false
+	}
+	good, total := s.counts(t.now(), window)
+	return burnRate(s.objective, good, total), true
+}
+
+// Status returns the current status of an objective
+func (t *Tracker) Status(name string) (Status, bool) {
+	t.mu.RLock()
+	s, ok := t.series[name]
+	if !ok {
+		t.mu.RUnlock()
+		return Status{}, false
+	}
+
+	now := t.now()
+	good, total := s.counts(now, s.objective.Window)
+	hourGood, hourTotal := s.counts(now, time.Hour)
+	t.mu.RUnlock()
+
+	status := Status{
+		Objective:       s.objective,
+		Total:           total,
+		Good:            good,
+		SLI:             1,
+		BudgetRemaining: 1,
+		BurnRate:        burnRate(s.objective, hourGood, hourTotal),
+	}
+	if total > 0 {
+		status.SLI = float64(good) / float64(total)
+		status.BudgetRemaining = 1 - burnRate(s.objective, good, total)
+	}
+
+	t.metrics.SetGauge(MetricBurn, status.BurnRate, metrics.Labels{"objective": name})
+	return status, true
+}
+
+// Statuses returns the status of every objective, sorted by name
+func (t *Tracker) Statuses() []Status {
+	t.mu.RLock()
+	names := make([]string, 0, len(t.series))
+	for name := range t.series {
+		names = append(names, name)
+	}
+	t.mu.RUnlock()
+
+	sort.Strings(names)
+	statuses := make([]Status, 0, len(names))
+	for _, name := range names {
+		if st, ok := t.Status(name); ok {
+			statuses = append(statuses, st)
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"errors"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrBatchNotExecuted is returned when a batch result is read before Exec
+var ErrBatchNotExecuted = errors.New("redis: batch not executed")
+
+// Pipeline returns a non-transactional pipeline; call Exec to flush it
+func (r *RedisService) Pipeline() goredis.Pipeliner {
+	return r.client.Pipeline()
+}
+
+// Pipelined queues commands in fn and sends them in a single round trip
+func (r *RedisService) Pipelined(ctx context.Context, fn func(goredis.Pipeliner) error) ([]goredis.Cmder, error) {
+	return r.client.Pipelined(ctx, fn)
+}
+
+// Batch groups arbitrary commands into one round trip and hands back typed results.
+//
+//	b := redis.NewBatch(svc)
+//	name := redis.Queue(b, b.Pipe().Get(ctx, "driver:1:name"))
+//	trips := redis.Queue(b, b.Pipe().Incr(ctx, "driver:1:trips"))
+//	err := b.Exec(ctx)
+//	n, err := trips.Get()
+type Batch struct {
+	pipe     goredis.Pipeliner
+	executed bool
+}
+
+// NewBatch starts a batch on svc
+func NewBatch(svc IRedisService) *Batch {
+	return &Batch{pipe: svc.Pipeline()}
+}
+
+// Pipe returns the pipeline commands are queued on
+func (b *Batch) Pipe() goredis.Pipeliner {
+	return b.pipe
+}
+
+// Len returns the number of queued commands
+func (b *Batch) Len() int {
+	return b.pipe.Len()
+}
+
+// Exec sends all queued commands. Missing keys are not treated as a batch
+// failure; they surface as Nil from the individual result.
+func (b *Batch) Exec(ctx context.Context) error {
+	b.executed = true
+	if b.pipe.Len() == 0 {
+		return nil
+	}
+	_, err := b.pipe.Exec(ctx)
+	if errors.Is(err, goredis.Nil) {
+		return nil
+	}
+	return err
+}
+
+// Result is the typed outcome of a batched command
+type Result[T any] struct {
+	batch *Batch
+	cmd   interface{ Result() (T, error) }
+}
+
+// Queue registers a command queued on b.Pipe() and returns its typed result
+func Queue[T any](b *Batch, cmd interface{ Result() (T, error) }) *Result[T] {
+	return &Result[T]{batch: b, cmd: cmd}
+}
+
+// Get returns the command result once the batch has executed
+func (r *Result[T]) Get() (T, error) {
+	if !r.batch.executed {
+		var zero T
+		return zero, ErrBatchNotExecuted
+	}
+	return r.cmd.Result()
+}
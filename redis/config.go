@@ -0,0 +1,225 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Connection modes
+const (
+	ModeStandalone = "standalone"
+	ModeCluster    = "cluster"
+	ModeSentinel   = "sentinel"
+)
+
+// RedisConfig holds Redis connection settings
+type RedisConfig struct {
+	Host string
+	Port string
+	// Username is the ACL user; empty uses the default user
+	Username     string
+	Password     string
+	DB           int
+	PoolSize     int
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+
+	// ClusterAddrs selects cluster mode; DB is ignored in a cluster
+	ClusterAddrs []string
+	// MasterName and SentinelAddrs select sentinel failover mode
+	MasterName       string
+	SentinelAddrs    []string
+	SentinelPassword string
+
+	// ReplicaAddrs lists read replicas serving Get, HGetAll and ZRange;
+	// reads fall back to the primary when a replica fails
+	ReplicaAddrs []string
+
+	// TLS is required by most managed Redis offerings
+	TLS TLSConfig
+
+	// StartupRetries is how many times the initial Ping is retried, with
+	// exponential backoff starting at StartupBackoff and capped at 10s
+	StartupRetries int
+	StartupBackoff time.Duration
+	// LazyConnect skips the initial Ping; connection errors surface on first use
+	LazyConnect bool
+}
+
+// TLSConfig holds Redis transport security settings
+type TLSConfig struct {
+	Enabled bool
+	// CAFile is a PEM bundle used to verify the server; system roots when empty
+	CAFile string
+	// ServerName overrides the name checked against the server certificate
+	ServerName string
+	// InsecureSkipVerify disables server verification; for local testing only
+	InsecureSkipVerify bool
+}
+
+// Config builds a tls.Config, or nil when TLS is disabled
+func (c TLSConfig) Config() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redis CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// DefaultRedisConfig returns the configuration for a local Redis
+func DefaultRedisConfig() RedisConfig {
+	return RedisConfig{
+		Host:         "localhost",
+		Port:         "6379",
+		PoolSize:     20,
+		DialTimeout:  5 * time.Second,
+		ReadTimeout:  3 * time.Second,
+		WriteTimeout: 3 * time.Second,
+
+		StartupRetries: 5,
+		StartupBackoff: 500 * time.Millisecond,
+	}
+}
+
+// LoadFromEnv overrides the defaults with REDIS_* environment variables
+func LoadFromEnv() RedisConfig {
+	cfg := DefaultRedisConfig()
+	if host := os.Getenv(common.EnvRedisHost); host != "" {
+		cfg.Host = host
+	}
+	if port := os.Getenv(common.EnvRedisPort); port != "" {
+		cfg.Port = port
+	}
+	cfg.Username = os.Getenv(common.EnvRedisUsername)
+	cfg.Password = os.Getenv(common.EnvRedisPassword)
+	if db, err := strconv.Atoi(os.Getenv(common.EnvRedisDB)); err == nil {
+		cfg.DB = db
+	}
+	cfg.ClusterAddrs = splitAddrs(os.Getenv(common.EnvRedisClusterAddrs))
+	cfg.SentinelAddrs = splitAddrs(os.Getenv(common.EnvRedisSentinelAddrs))
+	cfg.MasterName = os.Getenv(common.EnvRedisMasterName)
+	cfg.ReplicaAddrs = splitAddrs(os.Getenv(common.EnvRedisReplicaAddrs))
+	cfg.TLS.Enabled, _ = strconv.ParseBool(os.Getenv(common.EnvRedisTLS))
+	cfg.TLS.CAFile = os.Getenv(common.EnvRedisTLSCAFile)
+	cfg.TLS.InsecureSkipVerify, _ = strconv.ParseBool(os.Getenv(common.EnvRedisTLSInsecureSkipVerify))
+	if retries, err := strconv.Atoi(os.Getenv(common.EnvRedisStartupRetries)); err == nil {
+		cfg.StartupRetries = retries
+	}
+	cfg.LazyConnect, _ = strconv.ParseBool(os.Getenv(common.EnvRedisLazyConnect))
+	if cfg.TLS.CAFile != "" {
+		cfg.TLS.Enabled = true
+	}
+	return cfg
+}
+
+// Addr returns the host:port address
+func (c RedisConfig) Addr() string {
+	return fmt.Sprintf("%s:%s", c.Host, c.Port)
+}
+
+// Mode returns the connection mode implied by the config
+func (c RedisConfig) Mode() string {
+	switch {
+	case len(c.ClusterAddrs) > 0:
+		return ModeCluster
+	case c.MasterName != "":
+		return ModeSentinel
+	default:
+		return ModeStandalone
+	}
+}
+
+// Addrs returns the seed addresses for the connection mode
+func (c RedisConfig) Addrs() []string {
+	switch c.Mode() {
+	case ModeCluster:
+		return c.ClusterAddrs
+	case ModeSentinel:
+		return c.SentinelAddrs
+	default:
+		return []string{c.Addr()}
+	}
+}
+
+// NewClient creates a client for the configured mode
+func (c RedisConfig) NewClient() (goredis.UniversalClient, error) {
+	tlsConfig, err := c.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.Mode() {
+	case ModeCluster:
+		return goredis.NewClusterClient(&goredis.ClusterOptions{
+			Addrs:        c.ClusterAddrs,
+			Username:     c.Username,
+			Password:     c.Password,
+			TLSConfig:    tlsConfig,
+			PoolSize:     c.PoolSize,
+			DialTimeout:  c.DialTimeout,
+			ReadTimeout:  c.ReadTimeout,
+			WriteTimeout: c.WriteTimeout,
+		}), nil
+	case ModeSentinel:
+		return goredis.NewFailoverClient(&goredis.FailoverOptions{
+			MasterName:       c.MasterName,
+			SentinelAddrs:    c.SentinelAddrs,
+			SentinelPassword: c.SentinelPassword,
+			Username:         c.Username,
+			Password:         c.Password,
+			TLSConfig:        tlsConfig,
+			DB:               c.DB,
+			PoolSize:         c.PoolSize,
+			DialTimeout:      c.DialTimeout,
+			ReadTimeout:      c.ReadTimeout,
+			WriteTimeout:     c.WriteTimeout,
+		}), nil
+	default:
+		return goredis.NewClient(&goredis.Options{
+			Addr:         c.Addr(),
+			Username:     c.Username,
+			Password:     c.Password,
+			TLSConfig:    tlsConfig,
+			DB:           c.DB,
+			PoolSize:     c.PoolSize,
+			DialTimeout:  c.DialTimeout,
+			ReadTimeout:  c.ReadTimeout,
+			WriteTimeout: c.WriteTimeout,
+		}), nil
+	}
+}
+
+// splitAddrs parses a comma separated address list
+func splitAddrs(raw string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(raw, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,731 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/location"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Driver availability statuses stored with each driver location
+const (
+	DriverStatusAvailable = "available"
+	DriverStatusBusy      = "busy"
+	DriverStatusOffline   = "offline"
+)
+
+// Redis GEO only indexes latitudes within ±85.05112878
+const maxGeoLatitude = 85.05112878
+
+// ErrDriverNotFound is returned when a driver has no stored location
+var ErrDriverNotFound = errors.New("redis: driver location not found")
+
+// LocationUpdateError reports a failed driver location write; no part of the
+// update was applied
+type LocationUpdateError struct {
+	DriverID string
+	Err      error
+}
+
+func (e *LocationUpdateError) Error() string {
+	return fmt.Sprintf("failed to update location of driver %s: %v", e.DriverID, e.Err)
+}
+
+func (e *LocationUpdateError) Unwrap() error {
+	return e.Err
+}
+
+// GeoConfig configures a GeoLocationManager
+type GeoConfig struct {
+	// KeyPrefix is wrapped in a {hash tag} so every driver key shares a
+	// cluster slot and updates can run in one script
+	KeyPrefix string
+	// StaleAfter is how long a driver may go without a location update
+	// before it is removed from search and marked offline
+	StaleAfter time.Duration
+	// SweepInterval is how often Start looks for stale drivers
+	SweepInterval time.Duration
+	// SweepBatch bounds how many drivers one sweep script expires
+	SweepBatch int
+	// OnStale is called with the drivers expired by each sweep
+	OnStale func(driverIDs []string)
+
+	// HistoryLimit caps the positions kept per driver
+	HistoryLimit int
+	// HistoryRetention drops positions older than this
+	HistoryRetention time.Duration
+
+	// Shards splits drivers by city or geohash cell; nil keeps one global set
+	Shards ShardResolver
+}
+
+// DefaultGeoConfig returns the default geo settings
+func DefaultGeoConfig() GeoConfig {
+	return GeoConfig{
+		KeyPrefix:     "drivers",
+		StaleAfter:    2 * time.Minute,
+		SweepInterval: 30 * time.Second,
+		SweepBatch:    500,
+
+		HistoryLimit:     1000,
+		HistoryRetention: 72 * time.Hour,
+	}
+}
+
+// DriverState is a driver's stored location together with its status
+type DriverState struct {
+	location.DriverLocation
+	Status string `json:"status"`
+}
+
+const (
+	scriptUpdateDriverLocation = "geo_update_driver_location"
+	scriptSetDriverStatus      = "geo_set_driver_status"
+	scriptRemoveDriver         = "geo_remove_driver"
+	scriptExpireStaleDrivers   = "geo_expire_stale_drivers"
+)
+
+// geoIndexLua maintains the filtered geo sets: one per status, one per
+// vehicle class and one per status and class pair, so filtered searches run
+// entirely in Redis. Index keys are derived from ARGV[1] (the {hash tagged}
+// prefix), so they live in the same cluster slot as the declared keys.
+const geoIndexLua = `
+local function indexes(prefix, status, class)
+  local keys = {}
+  if status ~= '' then table.insert(keys, prefix .. ':geo:status:' .. status) end
+  if class ~= '' then table.insert(keys, prefix .. ':geo:class:' .. class) end
+  if status ~= '' and class ~= '' then
+    table.insert(keys, prefix .. ':geo:status:' .. status .. ':class:' .. class)
+  end
+  return keys
+end
+local function unindex(prefix, id, status, class)
+  for _, key in ipairs(indexes(prefix, status or '', class or '')) do
+    redis.call('ZREM', key, id)
+  end
+end
+local function index(prefix, id, status, class, lng, lat)
+  for _, key in ipairs(indexes(prefix, status, class)) do
+    redis.call('GEOADD', key, lng, lat, id)
+  end
+end
+`
+
+// KEYS geo set, driver hash, last-seen zset
+// ARGV prefix, driver ID, lng, lat, status, class, heading, capacity, updated (ms)
+const updateDriverLocationScript = geoIndexLua + `
+local prev = redis.call('HMGET', KEYS[2], 'status', 'vehicleClass')
+if prev[1] ~= ARGV[5] or prev[2] ~= ARGV[6] then
+  unindex(ARGV[1], ARGV[2], prev[1], prev[2])
+end
+redis.call('GEOADD', KEYS[1], ARGV[3], ARGV[4], ARGV[2])
+index(ARGV[1], ARGV[2], ARGV[5], ARGV[6], ARGV[3], ARGV[4])
+redis.call('HSET', KEYS[2],
+  'lng', ARGV[3], 'lat', ARGV[4], 'status', ARGV[5],
+  'vehicleClass', ARGV[6], 'heading', ARGV[7], 'capacity', ARGV[8],
+  'updatedAt', ARGV[9])
+redis.call('ZADD', KEYS[3], ARGV[9], ARGV[2])
+return 1
+`
+
+// KEYS driver hash, last-seen zset; ARGV prefix, driver ID, status
+// Returns 0 if the driver has no stored location, 2 if the status was
+// recorded without indexing because the sweeper expired the driver's
+// position (the next location update re-indexes it), 1 otherwise
+const setDriverStatusScript = geoIndexLua + `
+local prev = redis.call('HMGET', KEYS[1], 'status', 'vehicleClass', 'lng', 'lat')
+if not prev[3] then
+  return 0
+end
+unindex(ARGV[1], ARGV[2], prev[1], prev[2])
+redis.call('HSET', KEYS[1], 'status', ARGV[3])
+if not redis.call('ZSCORE', KEYS[2], ARGV[2]) then
+  return 2
+end
+index(ARGV[1], ARGV[2], ARGV[3], prev[2] or '', prev[3], prev[4])
+return 1
+`
+
+// KEYS geo set, driver hash, last-seen zset; ARGV prefix, driver ID
+const removeDriverScript = geoIndexLua + `
+local prev = redis.call('HMGET', KEYS[2], 'status', 'vehicleClass')
+unindex(ARGV[1], ARGV[2], prev[1], prev[2])
+redis.call('ZREM', KEYS[1], ARGV[2])
+redis.call('ZREM', KEYS[3], ARGV[2])
+return redis.call('DEL', KEYS[2])
+`
+
+// KEYS geo set, last-seen zset; ARGV prefix, cutoff (ms), batch size
+// Returns the expired driver IDs
+const expireStaleDriversScript = geoIndexLua + `
+local stale = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[2], 'LIMIT', 0, ARGV[3])
+for _, id in ipairs(stale) do
+  local driverKey = ARGV[1] .. ':driver:' .. id
+  local prev = redis.call('HMGET', driverKey, 'status', 'vehicleClass')
+  unindex(ARGV[1], id, prev[1], prev[2])
+  redis.call('ZREM', KEYS[1], id)
+  redis.call('ZREM', KEYS[2], id)
+  if redis.call('EXISTS', driverKey) == 1 then
+    redis.call('HSET', driverKey, 'status', 'offline')
+  end
+end
+return stale
+`
+
+// GeoLocationManager stores driver positions, statuses and vehicle metadata
+// for nearby-driver search
+type GeoLocationManager struct {
+	redis   IRedisService
+	scripts *ScriptManager
+	config  GeoConfig
+}
+
+// NewGeoLocationManager creates a geo location manager. A PrefixedRedisService
+// namespace is folded into the key prefix, since scripts use raw keys.
+func NewGeoLocationManager(svc IRedisService, config GeoConfig) *GeoLocationManager {
+	defaults := DefaultGeoConfig()
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaults.KeyPrefix
+	}
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = defaults.StaleAfter
+	}
+	if config.SweepInterval <= 0 {
+		config.SweepInterval = defaults.SweepInterval
+	}
+	if config.SweepBatch <= 0 {
+		config.SweepBatch = defaults.SweepBatch
+	}
+	if config.HistoryLimit <= 0 {
+		config.HistoryLimit = defaults.HistoryLimit
+	}
+	if config.HistoryRetention <= 0 {
+		config.HistoryRetention = defaults.HistoryRetention
+	}
+	if p, ok := svc.(*PrefixedRedisService); ok {
+		config.KeyPrefix = p.Prefix() + config.KeyPrefix
+		svc = p.IRedisService
+	}
+	scripts := NewScriptManager(svc.Client())
+	_ = scripts.Register(scriptUpdateDriverLocation, updateDriverLocationScript)
+	_ = scripts.Register(scriptSetDriverStatus, setDriverStatusScript)
+	_ = scripts.Register(scriptRemoveDriver, removeDriverScript)
+	_ = scripts.Register(scriptExpireStaleDrivers, expireStaleDriversScript)
+
+	return &GeoLocationManager{
+		redis:   svc,
+		scripts: scripts,
+		config:  config,
+	}
+}
+
+// prefix is the {hash tag} of a shard; every live key of a shard shares its
+// cluster slot so scripts can touch them together
+func (g *GeoLocationManager) prefix(shard string) string {
+	if shard == "" {
+		return "{" + g.config.KeyPrefix + "}"
+	}
+	return "{" + g.config.KeyPrefix + ":" + shard + "}"
+}
+
+func (g *GeoLocationManager) key(shard string, parts ...string) string {
+	key := g.prefix(shard)
+	for _, part := range parts {
+		key += ":" + part
+	}
+	return key
+}
+
+func (g *GeoLocationManager) geoKey(shard string) string {
+	return g.key(shard, "geo")
+}
+
+func (g *GeoLocationManager) driverKey(shard, driverID string) string {
+	return g.key(shard, "driver", driverID)
+}
+
+func (g *GeoLocationManager) statusGeoKey(shard, status string) string {
+	return g.key(shard, "geo", "status", status)
+}
+
+func (g *GeoLocationManager) classGeoKey(shard, vehicleClass string) string {
+	return g.key(shard, "geo", "class", vehicleClass)
+}
+
+func (g *GeoLocationManager) statusClassGeoKey(shard, status, vehicleClass string) string {
+	return g.key(shard, "geo", "status", status, "class", vehicleClass)
+}
+
+func (g *GeoLocationManager) lastSeenKey(shard string) string {
+	return g.key(shard, "lastseen")
+}
+
+// shardIndexKey maps driver IDs to the shard holding them
+func (g *GeoLocationManager) shardIndexKey() string {
+	return g.config.KeyPrefix + ":shards"
+}
+
+// activeShardsKey lists every shard that has held a driver
+func (g *GeoLocationManager) activeShardsKey() string {
+	return g.config.KeyPrefix + ":activeshards"
+}
+
+// driverShard returns the shard holding a driver, or ErrDriverNotFound
+func (g *GeoLocationManager) driverShard(ctx context.Context, driverID string) (string, error) {
+	if g.config.Shards == nil {
+		return "", nil
+	}
+	shard, err := g.redis.HGet(ctx, g.shardIndexKey(), driverID)
+	if errors.Is(err, Nil) {
+		return "", ErrDriverNotFound
+	}
+	return shard, err
+}
+
+// shards returns every shard a sweep has to visit
+func (g *GeoLocationManager) shards(ctx context.Context) ([]string, error) {
+	if g.config.Shards == nil {
+		return []string{""}, nil
+	}
+	return g.redis.SMembers(ctx, g.activeShardsKey())
+}
+
+// AddDriverLocation atomically stores the driver's position, status, vehicle
+// metadata and last-seen time in a single round trip. With sharding, a driver
+// crossing into another shard is first removed from the old one, so that
+// move takes two more round trips and is not atomic.
+func (g *GeoLocationManager) AddDriverLocation(ctx context.Context, loc location.DriverLocation, status string) error {
+	if loc.DriverID == "" {
+		return &LocationUpdateError{DriverID: loc.DriverID, Err: errors.New("driver ID is required")}
+	}
+	if !loc.Location.IsValid() || loc.Location.Latitude > maxGeoLatitude || loc.Location.Latitude < -maxGeoLatitude {
+		return &LocationUpdateError{DriverID: loc.DriverID, Err: fmt.Errorf("invalid location %s", loc.Location)}
+	}
+	if status == "" {
+		status = DriverStatusAvailable
+	}
+	if loc.UpdatedAt.IsZero() {
+		loc.UpdatedAt = time.Now()
+	}
+
+	capacity, err := json.Marshal(loc.Capacity)
+	if err != nil {
+		return &LocationUpdateError{DriverID: loc.DriverID, Err: err}
+	}
+
+	shard := ""
+	if g.config.Shards != nil {
+		shard = g.config.Shards.Shard(loc.Location)
+		previous, err := g.driverShard(ctx, loc.DriverID)
+		if err != nil && !errors.Is(err, ErrDriverNotFound) {
+			return &LocationUpdateError{DriverID: loc.DriverID, Err: err}
+		}
+		if err == nil && previous != shard {
+			if err := g.removeFromShard(ctx, previous, loc.DriverID); err != nil {
+				return &LocationUpdateError{DriverID: loc.DriverID, Err: err}
+			}
+		}
+	}
+
+	keys := []string{g.geoKey(shard), g.driverKey(shard, loc.DriverID), g.lastSeenKey(shard)}
+	_, err = g.scripts.Run(ctx, scriptUpdateDriverLocation, keys,
+		g.prefix(shard), loc.DriverID, loc.Location.Longitude, loc.Location.Latitude, status,
+		loc.VehicleClass, loc.Heading, string(capacity), loc.UpdatedAt.UnixMilli())
+	if err != nil {
+		return &LocationUpdateError{DriverID: loc.DriverID, Err: err}
+	}
+
+	if g.config.Shards != nil {
+		_, err = g.redis.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.HSet(ctx, g.shardIndexKey(), loc.DriverID, shard)
+			pipe.SAdd(ctx, g.activeShardsKey(), shard)
+			return nil
+		})
+		if err != nil {
+			return &LocationUpdateError{DriverID: loc.DriverID, Err: err}
+		}
+	}
+	return nil
+}
+
+// GetDriverLocation returns the stored state of a driver or ErrDriverNotFound
+func (g *GeoLocationManager) GetDriverLocation(ctx context.Context, driverID string) (DriverState, error) {
+	shard, err := g.driverShard(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, ErrDriverNotFound) {
+			return DriverState{}, err
+		}
+		return DriverState{}, fmt.Errorf("failed to get location of driver %s: %w", driverID, err)
+	}
+	fields, err := g.redis.HGetAll(ctx, g.driverKey(shard, driverID))
+	if err != nil {
+		return DriverState{}, fmt.Errorf("failed to get location of driver %s: %w", driverID, err)
+	}
+	if len(fields) == 0 {
+		return DriverState{}, ErrDriverNotFound
+	}
+	return parseDriverState(driverID, fields), nil
+}
+
+// DriverLookup is the result for one driver of GetMultipleDriverLocations;
+// Err is ErrDriverNotFound for unknown drivers
+type DriverLookup struct {
+	DriverID string
+	State    DriverState
+	Err      error
+}
+
+// GetMultipleDriverLocations loads many drivers in one pipeline (two with
+// sharding, to resolve shards first) and returns results in input order
+func (g *GeoLocationManager) GetMultipleDriverLocations(ctx context.Context, driverIDs []string) ([]DriverLookup, error) {
+	results := make([]DriverLookup, len(driverIDs))
+	if len(driverIDs) == 0 {
+		return results, nil
+	}
+	client := g.reader()
+
+	shards := make([]string, len(driverIDs))
+	unknown := make([]bool, len(driverIDs))
+	if g.config.Shards != nil {
+		found, err := client.HMGet(ctx, g.shardIndexKey(), driverIDs...).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve driver shards: %w", err)
+		}
+		for i, shard := range found {
+			shards[i], _ = shard.(string)
+			unknown[i] = shard == nil
+		}
+	}
+
+	cmds := make([]*goredis.MapStringStringCmd, len(driverIDs))
+	_, err := client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, driverID := range driverIDs {
+			if !unknown[i] {
+				cmds[i] = pipe.HGetAll(ctx, g.driverKey(shards[i], driverID))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load drivers: %w", err)
+	}
+
+	for i, driverID := range driverIDs {
+		results[i].DriverID = driverID
+		if cmds[i] == nil || len(cmds[i].Val()) == 0 {
+			results[i].Err = ErrDriverNotFound
+			continue
+		}
+		results[i].State = parseDriverState(driverID, cmds[i].Val())
+	}
+	return results, nil
+}
+
+// parseDriverState decodes a driver hash; malformed fields are left zero
+func parseDriverState(driverID string, fields map[string]string) DriverState {
+	state := DriverState{Status: fields["status"]}
+	state.DriverID = driverID
+	state.VehicleClass = fields["vehicleClass"]
+	state.Location.Latitude, _ = strconv.ParseFloat(fields["lat"], 64)
+	state.Location.Longitude, _ = strconv.ParseFloat(fields["lng"], 64)
+	state.Heading, _ = strconv.ParseFloat(fields["heading"], 64)
+	_ = json.Unmarshal([]byte(fields["capacity"]), &state.Capacity)
+	if ms, err := strconv.ParseInt(fields["updatedAt"], 10, 64); err == nil {
+		state.UpdatedAt = time.UnixMilli(ms)
+	}
+	return state
+}
+
+// SetDriverStatus changes a driver's status and moves it between the status
+// indexes; it returns ErrDriverNotFound if the driver has no stored location.
+// A driver expired by SweepStale only has its status recorded and stays out
+// of the indexes until its next location update.
+func (g *GeoLocationManager) SetDriverStatus(ctx context.Context, driverID, status string) error {
+	shard, err := g.driverShard(ctx, driverID)
+	if errors.Is(err, ErrDriverNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set status of driver %s: %w", driverID, err)
+	}
+	updated, err := g.scripts.RunInt(ctx, scriptSetDriverStatus,
+		[]string{g.driverKey(shard, driverID), g.lastSeenKey(shard)}, g.prefix(shard), driverID, status)
+	if err != nil {
+		return fmt.Errorf("failed to set status of driver %s: %w", driverID, err)
+	}
+	if updated == 0 {
+		return ErrDriverNotFound
+	}
+	return nil
+}
+
+// RemoveDriver deletes everything stored for a driver, including its indexes
+func (g *GeoLocationManager) RemoveDriver(ctx context.Context, driverID string) error {
+	shard, err := g.driverShard(ctx, driverID)
+	if errors.Is(err, ErrDriverNotFound) {
+		return nil
+	}
+	if err == nil {
+		err = g.removeFromShard(ctx, shard, driverID)
+	}
+	if err == nil && g.config.Shards != nil {
+		_, err = g.redis.HDel(ctx, g.shardIndexKey(), driverID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove driver %s: %w", driverID, err)
+	}
+	return nil
+}
+
+func (g *GeoLocationManager) removeFromShard(ctx context.Context, shard, driverID string) error {
+	keys := []string{g.geoKey(shard), g.driverKey(shard, driverID), g.lastSeenKey(shard)}
+	_, err := g.scripts.Run(ctx, scriptRemoveDriver, keys, g.prefix(shard), driverID)
+	return err
+}
+
+// NearbyDriver is a driver found by a nearby search
+type NearbyDriver struct {
+	DriverState
+	DistanceKm float64 `json:"distanceKm"`
+}
+
+// reader returns the client for read-only geo queries, preferring replicas
+func (g *GeoLocationManager) reader() goredis.UniversalClient {
+	if rs, ok := g.redis.(*RedisService); ok {
+		return rs.Reader()
+	}
+	return g.redis.Client()
+}
+
+// FindNearbyDrivers returns up to limit drivers within radiusKm of center,
+// nearest first. Sorting and the count are applied by GEOSEARCH and driver
+// metadata is fetched in one pipeline, so a query takes two round trips per
+// shard the search circle touches.
+func (g *GeoLocationManager) FindNearbyDrivers(ctx context.Context, center location.Location, radiusKm float64, limit int) ([]NearbyDriver, error) {
+	return g.search(ctx, g.geoKey, center, radiusKm, limit)
+}
+
+// search runs a nearby query in every shard near center and merges the results
+func (g *GeoLocationManager) search(ctx context.Context, geoKey func(shard string) string, center location.Location, radiusKm float64, limit int) ([]NearbyDriver, error) {
+	shards := []string{""}
+	if g.config.Shards != nil {
+		shards = g.config.Shards.ShardsNear(center, radiusKm)
+	}
+
+	var drivers []NearbyDriver
+	for _, shard := range shards {
+		found, err := g.searchShard(ctx, shard, geoKey(shard), center, radiusKm, limit)
+		if err != nil {
+			return nil, err
+		}
+		drivers = append(drivers, found...)
+	}
+
+	if len(shards) > 1 {
+		sort.SliceStable(drivers, func(i, j int) bool {
+			return drivers[i].DistanceKm < drivers[j].DistanceKm
+		})
+		if limit > 0 && len(drivers) > limit {
+			drivers = drivers[:limit]
+		}
+	}
+	return drivers, nil
+}
+
+func (g *GeoLocationManager) searchShard(ctx context.Context, shard, geoKey string, center location.Location, radiusKm float64, limit int) ([]NearbyDriver, error) {
+	client := g.reader()
+	found, err := client.GeoSearchLocation(ctx, geoKey, &goredis.GeoSearchLocationQuery{
+		GeoSearchQuery: goredis.GeoSearchQuery{
+			Longitude:  center.Longitude,
+			Latitude:   center.Latitude,
+			Radius:     radiusKm,
+			RadiusUnit: "km",
+			Sort:       "ASC",
+			Count:      limit,
+		},
+		WithDist: true,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search drivers near %s: %w", center, err)
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	cmds := make([]*goredis.MapStringStringCmd, len(found))
+	_, err = client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, loc := range found {
+			cmds[i] = pipe.HGetAll(ctx, g.driverKey(shard, loc.Name))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nearby drivers: %w", err)
+	}
+
+	drivers := make([]NearbyDriver, 0, len(found))
+	for i, loc := range found {
+		fields := cmds[i].Val()
+		if len(fields) == 0 {
+			// Removed between the search and the lookup
+			continue
+		}
+		drivers = append(drivers, NearbyDriver{
+			DriverState: parseDriverState(loc.Name, fields),
+			DistanceKm:  loc.Dist,
+		})
+	}
+	return drivers, nil
+}
+
+// FindAvailableDrivers returns up to limit available drivers within radiusKm
+// of center, nearest first. Filtering happens in Redis, so limit is exact
+// even where busy drivers are dense.
+func (g *GeoLocationManager) FindAvailableDrivers(ctx context.Context, center location.Location, radiusKm float64, limit int) ([]NearbyDriver, error) {
+	return g.search(ctx, func(shard string) string {
+		return g.statusGeoKey(shard, DriverStatusAvailable)
+	}, center, radiusKm, limit)
+}
+
+// FindDriversByVehicleType returns up to limit drivers of vehicleClass within
+// radiusKm of center regardless of status, nearest first
+func (g *GeoLocationManager) FindDriversByVehicleType(ctx context.Context, center location.Location, radiusKm float64, vehicleClass string, limit int) ([]NearbyDriver, error) {
+	return g.search(ctx, func(shard string) string {
+		return g.classGeoKey(shard, vehicleClass)
+	}, center, radiusKm, limit)
+}
+
+// FindAvailableDriversByVehicleType returns up to limit available drivers of
+// vehicleClass within radiusKm of center, nearest first
+func (g *GeoLocationManager) FindAvailableDriversByVehicleType(ctx context.Context, center location.Location, radiusKm float64, vehicleClass string, limit int) ([]NearbyDriver, error) {
+	return g.search(ctx, func(shard string) string {
+		return g.statusClassGeoKey(shard, DriverStatusAvailable, vehicleClass)
+	}, center, radiusKm, limit)
+}
+
+// SweepStale removes drivers whose last update is older than StaleAfter from
+// every geo set and marks them offline, returning their IDs. Crashed driver
+// apps stop appearing as available once swept. Safe to run on every replica
+// of a service at once.
+func (g *GeoLocationManager) SweepStale(ctx context.Context) ([]string, error) {
+	cutoff := time.Now().Add(-g.config.StaleAfter).UnixMilli()
+	shards, err := g.shards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list driver shards: %w", err)
+	}
+
+	var expired []string
+	for _, shard := range shards {
+		keys := []string{g.geoKey(shard), g.lastSeenKey(shard)}
+		for {
+			batch, err := g.scripts.RunStrings(ctx, scriptExpireStaleDrivers, keys, g.prefix(shard), cutoff, g.config.SweepBatch)
+			if err != nil {
+				return expired, fmt.Errorf("failed to expire stale drivers: %w", err)
+			}
+			expired = append(expired, batch...)
+			if len(batch) < g.config.SweepBatch {
+				break
+			}
+		}
+	}
+	return expired, nil
+}
+
+// Start sweeps stale drivers every SweepInterval until ctx is cancelled
+func (g *GeoLocationManager) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(g.config.SweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				expired, err := g.SweepStale(ctx)
+				if err != nil {
+					log.Printf("⚠️ Stale driver sweep failed: %v", err)
+				}
+				if len(expired) == 0 {
+					continue
+				}
+				log.Printf("Marked %d stale drivers offline", len(expired))
+				if g.config.OnStale != nil {
+					g.config.OnStale(expired)
+				}
+			}
+		}
+	}()
+}
+
+// LocationPoint is one recorded position of a driver
+type LocationPoint struct {
+	Location   location.Location `json:"location"`
+	Heading    float64           `json:"heading,omitempty"`
+	RecordedAt time.Time         `json:"recordedAt"`
+}
+
+// historyKey has its own {hash tag} per driver so trails spread across a
+// cluster instead of sharing the slot of the live indexes
+func (g *GeoLocationManager) historyKey(driverID string) string {
+	return g.config.KeyPrefix + ":history:{" + driverID + "}"
+}
+
+// RecordLocation appends a position to the driver's trail, trimming it to
+// HistoryLimit entries and HistoryRetention, for trip replay and disputes
+func (g *GeoLocationManager) RecordLocation(ctx context.Context, loc location.DriverLocation) error {
+	if loc.UpdatedAt.IsZero() {
+		loc.UpdatedAt = time.Now()
+	}
+	point, err := json.Marshal(LocationPoint{Location: loc.Location, Heading: loc.Heading, RecordedAt: loc.UpdatedAt})
+	if err != nil {
+		return fmt.Errorf("failed to encode location of driver %s: %w", loc.DriverID, err)
+	}
+
+	key := g.historyKey(loc.DriverID)
+	cutoff := time.Now().Add(-g.config.HistoryRetention).UnixMilli()
+	_, err = g.redis.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, goredis.Z{Score: float64(loc.UpdatedAt.UnixMilli()), Member: point})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(cutoff, 10))
+		pipe.ZRemRangeByRank(ctx, key, 0, int64(-g.config.HistoryLimit-1))
+		pipe.PExpire(ctx, key, g.config.HistoryRetention)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record location of driver %s: %w", loc.DriverID, err)
+	}
+	return nil
+}
+
+// GetLocationHistory returns the driver's positions recorded between from
+// and to, oldest first; zero times leave that end open
+func (g *GeoLocationManager) GetLocationHistory(ctx context.Context, driverID string, from, to time.Time) ([]LocationPoint, error) {
+	lower, upper := "-inf", "+inf"
+	if !from.IsZero() {
+		lower = strconv.FormatInt(from.UnixMilli(), 10)
+	}
+	if !to.IsZero() {
+		upper = strconv.FormatInt(to.UnixMilli(), 10)
+	}
+
+	members, err := g.reader().ZRangeByScore(ctx, g.historyKey(driverID), &goredis.ZRangeBy{Min: lower, Max: upper}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location history of driver %s: %w", driverID, err)
+	}
+
+	points := make([]LocationPoint, 0, len(members))
+	for _, member := range members {
+		var point LocationPoint
+		if err := json.Unmarshal([]byte(member), &point); err != nil {
+			continue
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
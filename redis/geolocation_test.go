@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/location"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func newTestClient(t *testing.T) (goredis.UniversalClient, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client, mr
+}
+
+func TestSweptDriverStaysOutOfIndexesOnStatusChange(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t)
+	geo := NewGeoLocationManager(NewRedisServiceFromClient(client), GeoConfig{StaleAfter: time.Millisecond})
+
+	pickup := location.NewLocation(18.52, 73.85)
+	driver := location.DriverLocation{DriverID: "driver-1", Location: pickup, VehicleClass: common.VehicleClassEconomy, UpdatedAt: time.Now()}
+	if err := geo.AddDriverLocation(ctx, driver, DriverStatusAvailable); err != nil {
+		t.Fatalf("AddDriverLocation: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	expired, err := geo.SweepStale(ctx)
+	if err != nil || len(expired) != 1 {
+		t.Fatalf("SweepStale = %v, %v; want driver-1 expired", expired, err)
+	}
+
+	if err := geo.SetDriverStatus(ctx, "driver-1", DriverStatusAvailable); err != nil {
+		t.Fatalf("SetDriverStatus: %v", err)
+	}
+	found, err := geo.FindAvailableDrivers(ctx, pickup, 1, 10)
+	if err != nil {
+		t.Fatalf("FindAvailableDrivers: %v", err)
+	}
+	if len(found) != 0 {
+		t.Errorf("swept driver is searchable again: %+v", found)
+	}
+
+	state, err := geo.GetDriverLocation(ctx, "driver-1")
+	if err != nil || state.Status != DriverStatusAvailable {
+		t.Errorf("GetDriverLocation = %+v, %v; want status recorded", state, err)
+	}
+
+	driver.UpdatedAt = time.Now()
+	if err := geo.AddDriverLocation(ctx, driver, DriverStatusAvailable); err != nil {
+		t.Fatalf("AddDriverLocation: %v", err)
+	}
+	if found, _ := geo.FindAvailableDrivers(ctx, pickup, 1, 10); len(found) != 1 {
+		t.Errorf("driver not re-indexed by a fresh location: %+v", found)
+	}
+}
@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"github.com/mihirk-khode/motocabz-common/location"
+)
+
+// ShardResolver splits driver locations into shards, each stored under its
+// own keys (and cluster slot), so no single key becomes a hotspot and a
+// city can be operated on alone
+type ShardResolver interface {
+	// Shard returns the shard that stores a location
+	Shard(loc location.Location) string
+	// ShardsNear returns every shard a search of radiusKm around center may
+	// touch; searches near a boundary fan out to all of them
+	ShardsNear(center location.Location, radiusKm float64) []string
+}
+
+// CityShard is a served city approximated by a circle
+type CityShard struct {
+	Code     string
+	Center   location.Location
+	RadiusKm float64
+}
+
+// CityShardResolver shards by city code; locations outside every city go to
+// the Default shard
+type CityShardResolver struct {
+	Cities  []CityShard
+	Default string
+}
+
+// NewCityShardResolver creates a resolver; defaultShard defaults to "other"
+func NewCityShardResolver(cities []CityShard, defaultShard string) *CityShardResolver {
+	if defaultShard == "" {
+		defaultShard = "other"
+	}
+	return &CityShardResolver{Cities: cities, Default: defaultShard}
+}
+
+// Shard returns the first city containing loc, or the default shard
+func (r *CityShardResolver) Shard(loc location.Location) string {
+	for _, city := range r.Cities {
+		if city.Center.DistanceKm(loc) <= city.RadiusKm {
+			return city.Code
+		}
+	}
+	return r.Default
+}
+
+// ShardsNear returns the cities overlapping the search circle, plus the
+// default shard unless the circle lies entirely inside one city
+func (r *CityShardResolver) ShardsNear(center location.Location, radiusKm float64) []string {
+	var shards []string
+	covered := false
+	for _, city := range r.Cities {
+		distance := city.Center.DistanceKm(center)
+		if distance <= city.RadiusKm+radiusKm {
+			shards = append(shards, city.Code)
+		}
+		if distance+radiusKm <= city.RadiusKm {
+			covered = true
+		}
+	}
+	if !covered {
+		shards = append(shards, r.Default)
+	}
+	return shards
+}
+
+// GeohashShardResolver shards by geohash cell; precision 4 gives cells of
+// roughly 39 x 20 km
+type GeohashShardResolver struct {
+	Precision int
+}
+
+// NewGeohashShardResolver creates a resolver; precision defaults to 4
+func NewGeohashShardResolver(precision int) *GeohashShardResolver {
+	if precision <= 0 {
+		precision = 4
+	}
+	return &GeohashShardResolver{Precision: precision}
+}
+
+// Shard returns the geohash cell of loc
+func (r *GeohashShardResolver) Shard(loc location.Location) string {
+	return location.EncodeGeohash(loc, r.Precision)
+}
+
+// ShardsNear returns every cell intersecting the box around the search circle
+func (r *GeohashShardResolver) ShardsNear(center location.Location, radiusKm float64) []string {
+	return location.GeohashesCovering(location.BoundsAround(center, radiusKm), r.Precision)
+}
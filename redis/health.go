@@ -0,0 +1,303 @@
+package redis
+
+import (
+	"context"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Health statuses reported by RedisHealthChecker
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+)
+
+// PoolStats is a snapshot of the connection pool
+type PoolStats struct {
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+	TotalConns uint32 `json:"totalConns"`
+	IdleConns  uint32 `json:"idleConns"`
+	StaleConns uint32 `json:"staleConns"`
+	PoolSize   int    `json:"poolSize"`
+	// Saturation is the share of the pool in use, from 0 to 1
+	Saturation float64 `json:"saturation"`
+}
+
+// Stats returns connection pool statistics. In cluster mode the counters are
+// summed over all nodes and PoolSize is the per-node size times the seed count.
+func (r *RedisService) Stats() PoolStats {
+	s := r.client.PoolStats()
+	stats := PoolStats{
+		Hits:       s.Hits,
+		Misses:     s.Misses,
+		Timeouts:   s.Timeouts,
+		TotalConns: s.TotalConns,
+		IdleConns:  s.IdleConns,
+		StaleConns: s.StaleConns,
+		PoolSize:   r.poolSize(),
+	}
+	if stats.PoolSize > 0 && s.TotalConns > s.IdleConns {
+		stats.Saturation = float64(s.TotalConns-s.IdleConns) / float64(stats.PoolSize)
+	}
+	return stats
+}
+
+func (r *RedisService) poolSize() int {
+	switch c := r.client.(type) {
+	case *goredis.Client:
+		return c.Options().PoolSize
+	case *goredis.ClusterClient:
+		nodes := len(c.Options().Addrs)
+		if nodes == 0 {
+			nodes = 1
+		}
+		return c.Options().PoolSize * nodes
+	default:
+		return r.config.PoolSize
+	}
+}
+
+// ExportPoolStats publishes pool gauges every interval until ctx is done
+func ExportPoolStats(ctx context.Context, svc *RedisService, provider metrics.Provider, interval time.Duration) {
+	provider = metrics.OrDefault(provider)
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s := svc.Stats()
+				provider.SetGauge("redis_pool_hits", float64(s.Hits), nil)
+				provider.SetGauge("redis_pool_misses", float64(s.Misses), nil)
+				provider.SetGauge("redis_pool_timeouts", float64(s.Timeouts), nil)
+				provider.SetGauge("redis_pool_total_conns", float64(s.TotalConns), nil)
+				provider.SetGauge("redis_pool_idle_conns", float64(s.IdleConns), nil)
+				provider.SetGauge("redis_pool_saturation", s.Saturation, nil)
+			}
+		}
+	}()
+}
+
+// LatencyPercentiles summarizes recent command latencies
+type LatencyPercentiles struct {
+	Samples int           `json:"samples"`
+	P50     time.Duration `json:"p50"`
+	P95     time.Duration `json:"p95"`
+	P99     time.Duration `json:"p99"`
+}
+
+// latencyRecorder is a go-redis hook keeping the most recent command durations
+type latencyRecorder struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+func newLatencyRecorder(size int) *latencyRecorder {
+	return &latencyRecorder{samples: make([]time.Duration, size)}
+}
+
+func (l *latencyRecorder) record(d time.Duration) {
+	l.mu.Lock()
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % len(l.samples)
+	if l.next == 0 {
+		l.full = true
+	}
+	l.mu.Unlock()
+}
+
+func (l *latencyRecorder) percentiles() LatencyPercentiles {
+	l.mu.Lock()
+	n := l.next
+	if l.full {
+		n = len(l.samples)
+	}
+	sorted := append([]time.Duration(nil), l.samples[:n]...)
+	l.mu.Unlock()
+
+	if n == 0 {
+		return LatencyPercentiles{}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	at := func(p float64) time.Duration {
+		return sorted[int(p*float64(n-1))]
+	}
+	return LatencyPercentiles{Samples: n, P50: at(0.50), P95: at(0.95), P99: at(0.99)}
+}
+
+func (l *latencyRecorder) DialHook(next goredis.DialHook) goredis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// blockingCommands wait server-side for data, so their duration says nothing
+// about Redis latency
+var blockingCommands = map[string]bool{
+	"blpop": true, "brpop": true, "brpoplpush": true, "blmove": true, "blmpop": true,
+	"bzpopmin": true, "bzpopmax": true, "bzmpop": true, "wait": true, "waitaof": true,
+}
+
+// isBlocking reports whether cmd may block, including XREAD/XREADGROUP with BLOCK
+func isBlocking(cmd goredis.Cmder) bool {
+	name := cmd.Name()
+	if blockingCommands[name] {
+		return true
+	}
+	if name != "xread" && name != "xreadgroup" {
+		return false
+	}
+	for _, arg := range cmd.Args() {
+		if s, ok := arg.(string); ok && strings.EqualFold(s, "block") {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *latencyRecorder) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		if isBlocking(cmd) {
+			return next(ctx, cmd)
+		}
+		start := time.Now()
+		err := next(ctx, cmd)
+		l.record(time.Since(start))
+		return err
+	}
+}
+
+func (l *latencyRecorder) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		for _, cmd := range cmds {
+			if isBlocking(cmd) {
+				return next(ctx, cmds)
+			}
+		}
+		start := time.Now()
+		err := next(ctx, cmds)
+		l.record(time.Since(start))
+		return err
+	}
+}
+
+// HealthCheckerConfig tunes RedisHealthChecker
+type HealthCheckerConfig struct {
+	// PingTimeout bounds the health probe
+	PingTimeout time.Duration
+	// SaturationThreshold marks the pool degraded at this share in use
+	SaturationThreshold float64
+	// SlowP99 marks commands degraded when the p99 latency exceeds it
+	SlowP99 time.Duration
+	// LatencySamples is how many recent commands feed the percentiles
+	LatencySamples int
+}
+
+// DefaultHealthCheckerConfig returns the default health thresholds
+func DefaultHealthCheckerConfig() HealthCheckerConfig {
+	return HealthCheckerConfig{
+		PingTimeout:         2 * time.Second,
+		SaturationThreshold: 0.9,
+		SlowP99:             100 * time.Millisecond,
+		LatencySamples:      1024,
+	}
+}
+
+// HealthStatus is the result of a Redis health check
+type HealthStatus struct {
+	Status      string             `json:"status"`
+	PingLatency time.Duration      `json:"pingLatency"`
+	Pool        PoolStats          `json:"pool"`
+	Latency     LatencyPercentiles `json:"latency"`
+	Issues      []string           `json:"issues,omitempty"`
+	CheckedAt   time.Time          `json:"checkedAt"`
+}
+
+// RedisHealthChecker reports connectivity, pool saturation and command latency
+type RedisHealthChecker struct {
+	svc      *RedisService
+	config   HealthCheckerConfig
+	latency  *latencyRecorder
+	timeouts atomic.Uint32
+}
+
+// NewRedisHealthChecker creates a checker and starts recording command latencies
+func NewRedisHealthChecker(svc *RedisService, config HealthCheckerConfig) *RedisHealthChecker {
+	defaults := DefaultHealthCheckerConfig()
+	if config.PingTimeout <= 0 {
+		config.PingTimeout = defaults.PingTimeout
+	}
+	if config.SaturationThreshold <= 0 {
+		config.SaturationThreshold = defaults.SaturationThreshold
+	}
+	if config.SlowP99 <= 0 {
+		config.SlowP99 = defaults.SlowP99
+	}
+	if config.LatencySamples <= 0 {
+		config.LatencySamples = defaults.LatencySamples
+	}
+
+	h := &RedisHealthChecker{
+		svc:     svc,
+		config:  config,
+		latency: newLatencyRecorder(config.LatencySamples),
+	}
+	h.timeouts.Store(svc.Stats().Timeouts)
+	svc.client.AddHook(h.latency)
+	return h
+}
+
+// GetHealthStatus pings Redis and evaluates pool and latency health. Pool
+// timeouts since the previous check also mark the status degraded.
+func (h *RedisHealthChecker) GetHealthStatus(ctx context.Context) HealthStatus {
+	status := HealthStatus{Status: HealthStatusHealthy, CheckedAt: time.Now()}
+
+	pingCtx, cancel := context.WithTimeout(ctx, h.config.PingTimeout)
+	defer cancel()
+	start := time.Now()
+	err := h.svc.Ping(pingCtx)
+	status.PingLatency = time.Since(start)
+
+	status.Pool = h.svc.Stats()
+	status.Latency = h.latency.percentiles()
+
+	if err != nil {
+		status.Status = HealthStatusUnhealthy
+		status.Issues = append(status.Issues, err.Error())
+		log.Printf("❌ Redis health check failed: %v", err)
+		return status
+	}
+
+	if status.Pool.Saturation >= h.config.SaturationThreshold {
+		status.Issues = append(status.Issues, "connection pool saturated")
+	}
+	if status.Pool.Timeouts > h.timeouts.Swap(status.Pool.Timeouts) {
+		status.Issues = append(status.Issues, "connection pool timeouts")
+	}
+	if status.Latency.P99 > h.config.SlowP99 {
+		status.Issues = append(status.Issues, "slow commands")
+	}
+	if len(status.Issues) > 0 {
+		status.Status = HealthStatusDegraded
+	}
+	return status
+}
@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestLatencyRecorderSkipsBlockingCommands(t *testing.T) {
+	ctx := context.Background()
+	client, mr := newTestClient(t)
+	recorder := newLatencyRecorder(16)
+	client.AddHook(recorder)
+
+	client.Ping(ctx) // let connection setup commands record first
+	before := recorder.percentiles().Samples
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		mr.Lpush("queue", "job")
+	}()
+	if err := client.BLPop(ctx, time.Second, "queue").Err(); err != nil {
+		t.Fatalf("BLPop: %v", err)
+	}
+	if got := recorder.percentiles().Samples - before; got != 0 {
+		t.Fatalf("blocking pop recorded %d samples", got)
+	}
+	client.Get(ctx, "missing")
+	if got := recorder.percentiles().Samples - before; got != 1 {
+		t.Errorf("GET recorded %d samples, want 1", got)
+	}
+
+	xread := goredis.NewXStreamSliceCmd(ctx, "xreadgroup", "group", "g", "c", "block", 1000, "streams", "s", ">")
+	if !isBlocking(xread) {
+		t.Error("XREADGROUP BLOCK not treated as blocking")
+	}
+}
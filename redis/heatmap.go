@@ -0,0 +1,183 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/location"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// DefaultHeatmapPrecision buckets drivers into geohash cells of about 1.2 km
+const DefaultHeatmapPrecision = 6
+
+// HeatmapCell is the number of available drivers in one geohash cell
+type HeatmapCell struct {
+	Geohash string            `json:"geohash"`
+	Center  location.Location `json:"center"`
+	Count   int               `json:"count"`
+}
+
+// Heatmap is a supply snapshot around a point, busiest cells first
+type Heatmap struct {
+	Center      location.Location `json:"center"`
+	RadiusKm    float64           `json:"radiusKm"`
+	Precision   int               `json:"precision"`
+	Total       int               `json:"total"`
+	Cells       []HeatmapCell     `json:"cells"`
+	GeneratedAt time.Time         `json:"generatedAt"`
+}
+
+// SupplyHeatmap buckets the available drivers within radiusKm of center into
+// geohash cells at precision and returns the count per cell
+func (g *GeoLocationManager) SupplyHeatmap(ctx context.Context, center location.Location, radiusKm float64, precision int) (Heatmap, error) {
+	if precision <= 0 || precision > location.MaxGeohashPrecision {
+		precision = DefaultHeatmapPrecision
+	}
+
+	shards := []string{""}
+	if g.config.Shards != nil {
+		shards = g.config.Shards.ShardsNear(center, radiusKm)
+	}
+
+	counts := make(map[string]int)
+	total := 0
+	for _, shard := range shards {
+		found, err := g.reader().GeoSearchLocation(ctx, g.statusGeoKey(shard, DriverStatusAvailable), &goredis.GeoSearchLocationQuery{
+			GeoSearchQuery: goredis.GeoSearchQuery{
+				Longitude:  center.Longitude,
+				Latitude:   center.Latitude,
+				Radius:     radiusKm,
+				RadiusUnit: "km",
+			},
+			WithCoord: true,
+		}).Result()
+		if err != nil {
+			return Heatmap{}, fmt.Errorf("failed to aggregate supply near %s: %w", center, err)
+		}
+		for _, loc := range found {
+			counts[location.EncodeGeohash(location.NewLocation(loc.Latitude, loc.Longitude), precision)]++
+			total++
+		}
+	}
+
+	heatmap := Heatmap{
+		Center:      center,
+		RadiusKm:    radiusKm,
+		Precision:   precision,
+		Total:       total,
+		Cells:       make([]HeatmapCell, 0, len(counts)),
+		GeneratedAt: time.Now(),
+	}
+	for hash, count := range counts {
+		bounds, _ := location.GeohashBounds(hash)
+		heatmap.Cells = append(heatmap.Cells, HeatmapCell{Geohash: hash, Center: bounds.Center(), Count: count})
+	}
+	sort.Slice(heatmap.Cells, func(i, j int) bool {
+		if heatmap.Cells[i].Count != heatmap.Cells[j].Count {
+			return heatmap.Cells[i].Count > heatmap.Cells[j].Count
+		}
+		return heatmap.Cells[i].Geohash < heatmap.Cells[j].Geohash
+	})
+	return heatmap, nil
+}
+
+// HeatmapSnapshotConfig describes an area whose supply is recorded periodically
+type HeatmapSnapshotConfig struct {
+	// Name identifies the series, e.g. a city code
+	Name      string
+	Center    location.Location
+	RadiusKm  float64
+	Precision int
+	// Interval is the time between snapshots
+	Interval time.Duration
+	// Retention drops snapshots older than this
+	Retention time.Duration
+}
+
+func (g *GeoLocationManager) heatmapKey(name string) string {
+	return g.config.KeyPrefix + ":heatmap:{" + name + "}"
+}
+
+// SnapshotHeatmap computes a heatmap and appends it to the named series
+func (g *GeoLocationManager) SnapshotHeatmap(ctx context.Context, cfg HeatmapSnapshotConfig) (Heatmap, error) {
+	heatmap, err := g.SupplyHeatmap(ctx, cfg.Center, cfg.RadiusKm, cfg.Precision)
+	if err != nil {
+		return Heatmap{}, err
+	}
+	data, err := json.Marshal(heatmap)
+	if err != nil {
+		return Heatmap{}, fmt.Errorf("failed to encode heatmap %s: %w", cfg.Name, err)
+	}
+
+	retention := cfg.Retention
+	if retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+	key := g.heatmapKey(cfg.Name)
+	cutoff := heatmap.GeneratedAt.Add(-retention).UnixMilli()
+	_, err = g.redis.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, goredis.Z{Score: float64(heatmap.GeneratedAt.UnixMilli()), Member: data})
+		pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(cutoff, 10))
+		pipe.PExpire(ctx, key, retention)
+		return nil
+	})
+	if err != nil {
+		return Heatmap{}, fmt.Errorf("failed to store heatmap %s: %w", cfg.Name, err)
+	}
+	return heatmap, nil
+}
+
+// StartHeatmapSnapshots records a snapshot every cfg.Interval until ctx is
+// cancelled. Run it on a single instance (e.g. the leader) to avoid
+// duplicate snapshots.
+func (g *GeoLocationManager) StartHeatmapSnapshots(ctx context.Context, cfg HeatmapSnapshotConfig) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Minute
+	}
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := g.SnapshotHeatmap(ctx, cfg); err != nil {
+					log.Printf("⚠️ Heatmap snapshot %s failed: %v", cfg.Name, err)
+				}
+			}
+		}
+	}()
+}
+
+// GetHeatmapSnapshots returns the snapshots of a series taken between from
+// and to, oldest first; zero times leave that end open
+func (g *GeoLocationManager) GetHeatmapSnapshots(ctx context.Context, name string, from, to time.Time) ([]Heatmap, error) {
+	lower, upper := "-inf", "+inf"
+	if !from.IsZero() {
+		lower = strconv.FormatInt(from.UnixMilli(), 10)
+	}
+	if !to.IsZero() {
+		upper = strconv.FormatInt(to.UnixMilli(), 10)
+	}
+
+	members, err := g.reader().ZRangeByScore(ctx, g.heatmapKey(name), &goredis.ZRangeBy{Min: lower, Max: upper}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get heatmap snapshots %s: %w", name, err)
+	}
+	snapshots := make([]Heatmap, 0, len(members))
+	for _, member := range members {
+		var heatmap Heatmap
+		if err := json.Unmarshal([]byte(member), &heatmap); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, heatmap)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,201 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/location"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// HotspotConfig configures PickupHotspots
+type HotspotConfig struct {
+	KeyPrefix string
+	// Precision is the geohash length pickups are snapped to; 8 is about 38 x 19 m
+	Precision int
+	// RetentionDays is how many days of pickups count towards suggestions
+	RetentionDays int
+	// MinCount hides cells with fewer pickups than this
+	MinCount int
+}
+
+// DefaultHotspotConfig returns the default hotspot settings
+func DefaultHotspotConfig() HotspotConfig {
+	return HotspotConfig{
+		KeyPrefix:     "pickups",
+		Precision:     8,
+		RetentionDays: 30,
+		MinCount:      3,
+	}
+}
+
+// PickupSuggestion is a popular pickup spot near a rider
+type PickupSuggestion struct {
+	Geohash    string            `json:"geohash"`
+	Location   location.Location `json:"location"`
+	Count      int64             `json:"count"`
+	DistanceKm float64           `json:"distanceKm"`
+}
+
+// PickupHotspots learns popular pickup spots from historical trip origins so
+// apps can offer "pickup here" pins snapped to where trips usually start
+type PickupHotspots struct {
+	client goredis.UniversalClient
+	config HotspotConfig
+	now    func() time.Time
+}
+
+// NewPickupHotspots creates a hotspot tracker
+func NewPickupHotspots(client goredis.UniversalClient, config HotspotConfig) *PickupHotspots {
+	defaults := DefaultHotspotConfig()
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaults.KeyPrefix
+	}
+	if config.Precision <= 0 || config.Precision > location.MaxGeohashPrecision {
+		config.Precision = defaults.Precision
+	}
+	if config.RetentionDays <= 0 {
+		config.RetentionDays = defaults.RetentionDays
+	}
+	if config.MinCount <= 0 {
+		config.MinCount = defaults.MinCount
+	}
+	return &PickupHotspots{client: client, config: config, now: time.Now}
+}
+
+func (h *PickupHotspots) cellsKey() string {
+	return "{" + h.config.KeyPrefix + "}:cells"
+}
+
+func (h *PickupHotspots) lastSeenKey() string {
+	return "{" + h.config.KeyPrefix + "}:lastseen"
+}
+
+func (h *PickupHotspots) dayKey(day time.Time) string {
+	return "{" + h.config.KeyPrefix + "}:counts:" + day.UTC().Format("20060102")
+}
+
+// RecordPickup counts a trip that started at loc
+func (h *PickupHotspots) RecordPickup(ctx context.Context, loc location.Location) error {
+	if !loc.IsValid() {
+		return fmt.Errorf("invalid pickup location %s", loc)
+	}
+	cell := location.EncodeGeohash(loc, h.config.Precision)
+	bounds, err := location.GeohashBounds(cell)
+	if err != nil {
+		return err
+	}
+	center := bounds.Center()
+	now := h.now()
+	dayKey := h.dayKey(now)
+
+	_, err = h.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.GeoAdd(ctx, h.cellsKey(), &goredis.GeoLocation{Name: cell, Longitude: center.Longitude, Latitude: center.Latitude})
+		pipe.ZAdd(ctx, h.lastSeenKey(), goredis.Z{Score: float64(now.Unix()), Member: cell})
+		pipe.ZIncrBy(ctx, dayKey, 1, cell)
+		pipe.Expire(ctx, dayKey, time.Duration(h.config.RetentionDays+1)*24*time.Hour)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record pickup: %w", err)
+	}
+	return nil
+}
+
+// SuggestPickups returns up to limit popular pickup spots within radiusKm of
+// loc, most popular first. It takes two round trips: one GEOSEARCH and one
+// pipeline of per-day counts.
+func (h *PickupHotspots) SuggestPickups(ctx context.Context, loc location.Location, radiusKm float64, limit int) ([]PickupSuggestion, error) {
+	cells, err := h.client.GeoSearchLocation(ctx, h.cellsKey(), &goredis.GeoSearchLocationQuery{
+		GeoSearchQuery: goredis.GeoSearchQuery{
+			Longitude:  loc.Longitude,
+			Latitude:   loc.Latitude,
+			Radius:     radiusKm,
+			RadiusUnit: "km",
+			Sort:       "ASC",
+		},
+		WithDist:  true,
+		WithCoord: true,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search pickup hotspots near %s: %w", loc, err)
+	}
+	if len(cells) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, len(cells))
+	for i, cell := range cells {
+		names[i] = cell.Name
+	}
+	today := h.now()
+	cmds := make([]*goredis.FloatSliceCmd, h.config.RetentionDays)
+	_, err = h.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for d := range cmds {
+			cmds[d] = pipe.ZMScore(ctx, h.dayKey(today.AddDate(0, 0, -d)), names...)
+		}
+		return nil
+	})
+	if err != nil && err != goredis.Nil {
+		return nil, fmt.Errorf("failed to load pickup counts: %w", err)
+	}
+
+	suggestions := make([]PickupSuggestion, 0, len(cells))
+	for i, cell := range cells {
+		var count float64
+		for _, cmd := range cmds {
+			if scores := cmd.Val(); i < len(scores) {
+				count += scores[i]
+			}
+		}
+		if int(count) < h.config.MinCount {
+			continue
+		}
+		suggestions = append(suggestions, PickupSuggestion{
+			Geohash:    cell.Name,
+			Location:   location.NewLocation(cell.Latitude, cell.Longitude),
+			Count:      int64(count),
+			DistanceKm: cell.Dist,
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Count > suggestions[j].Count
+	})
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// Prune forgets cells without a pickup in the retention window
+func (h *PickupHotspots) Prune(ctx context.Context) (int, error) {
+	cutoff := h.now().AddDate(0, 0, -h.config.RetentionDays).Unix()
+	stale, err := h.client.ZRangeByScore(ctx, h.lastSeenKey(), &goredis.ZRangeBy{
+		Min: "-inf",
+		Max: "(" + strconv.FormatInt(cutoff, 10),
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list stale pickup cells: %w", err)
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	members := make([]interface{}, len(stale))
+	for i, cell := range stale {
+		members[i] = cell
+	}
+	_, err = h.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, h.cellsKey(), members...)
+		pipe.ZRem(ctx, h.lastSeenKey(), members...)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune pickup cells: %w", err)
+	}
+	return len(stale), nil
+}
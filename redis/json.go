@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrJSONPathUnsupported is returned for non-root paths when RedisJSON is unavailable
+var ErrJSONPathUnsupported = errors.New("redis: JSON paths other than the root require the RedisJSON module")
+
+// JSON root paths; "$" follows JSONPath and replies with an array of matches,
+// "." is the legacy syntax and replies with the single value
+const (
+	JSONRoot       = "$"
+	JSONLegacyRoot = "."
+)
+
+// jsonSupport caches whether the server understands JSON.* commands
+type jsonSupport struct {
+	mu       sync.Mutex
+	detected bool
+	native   bool
+}
+
+// JSONNative reports whether the RedisJSON module is loaded. When it is not,
+// the JSON methods store whole documents as plain strings and only accept
+// root paths. The result is detected once and cached.
+func (r *RedisService) JSONNative(ctx context.Context) bool {
+	r.json.mu.Lock()
+	defer r.json.mu.Unlock()
+	if r.json.detected {
+		return r.json.native
+	}
+
+	err := r.client.Do(ctx, "JSON.GET", "__json_probe__").Err()
+	switch {
+	case err == nil || errors.Is(err, Nil):
+		r.json.native, r.json.detected = true, true
+	case strings.Contains(strings.ToLower(err.Error()), "unknown command"):
+		r.json.native, r.json.detected = false, true
+	}
+	// Connectivity errors say nothing about the module; probe again next time
+	return r.json.native
+}
+
+func isJSONRoot(path string) bool {
+	return path == "" || path == JSONRoot || path == JSONLegacyRoot
+}
+
+// JSONSet encodes value and stores it at path in the document at key
+func (r *RedisService) JSONSet(ctx context.Context, key, path string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	if path == "" {
+		path = JSONRoot
+	}
+
+	if r.JSONNative(ctx) {
+		return r.client.Do(ctx, "JSON.SET", key, path, string(data)).Err()
+	}
+	if !isJSONRoot(path) {
+		return ErrJSONPathUnsupported
+	}
+	return r.client.Set(ctx, key, data, 0).Err()
+}
+
+// JSONGet returns the raw JSON at path in the document at key, or Nil if the
+// key does not exist. JSONPath queries ("$...") reply with an array of matches.
+func (r *RedisService) JSONGet(ctx context.Context, key, path string) (string, error) {
+	if path == "" {
+		path = JSONRoot
+	}
+
+	if r.JSONNative(ctx) {
+		return r.client.Do(ctx, "JSON.GET", key, path).Text()
+	}
+	if !isJSONRoot(path) {
+		return "", ErrJSONPathUnsupported
+	}
+
+	data, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		return "", err
+	}
+	if path == JSONRoot {
+		return "[" + data + "]", nil
+	}
+	return data, nil
+}
+
+// JSONDel deletes path from the document at key, returning the number of
+// values removed; deleting the root removes the key
+func (r *RedisService) JSONDel(ctx context.Context, key, path string) (int64, error) {
+	if path == "" {
+		path = JSONRoot
+	}
+
+	if r.JSONNative(ctx) {
+		return r.client.Do(ctx, "JSON.DEL", key, path).Int64()
+	}
+	if !isJSONRoot(path) {
+		return 0, ErrJSONPathUnsupported
+	}
+	return r.client.Del(ctx, key).Result()
+}
+
+// JSONGetInto decodes the value at a single-value path (e.g. "." or ".trip.status")
+// into v
+func JSONGetInto(ctx context.Context, svc IRedisService, key, path string, v interface{}) error {
+	if path == "" {
+		path = JSONLegacyRoot
+	}
+	data, err := svc.JSONGet(ctx, key, path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	return nil
+}
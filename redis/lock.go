@@ -0,0 +1,230 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrLockNotAcquired is returned by TryAcquire when the lock is held elsewhere
+	ErrLockNotAcquired = errors.New("redis: lock not acquired")
+	// ErrLockNotHeld is returned when releasing or extending a lock that expired
+	// or was taken over by another owner
+	ErrLockNotHeld = errors.New("redis: lock not held")
+)
+
+// LockConfig configures a LockManager
+type LockConfig struct {
+	KeyPrefix string
+	// TTL is how long a lock lives without being extended
+	TTL time.Duration
+	// RetryInterval is the wait between attempts in Acquire
+	RetryInterval time.Duration
+	// AutoExtend renews held locks every TTL/3 until they are released
+	AutoExtend bool
+}
+
+// DefaultLockConfig returns the default lock settings
+func DefaultLockConfig() LockConfig {
+	return LockConfig{
+		KeyPrefix:     "lock:",
+		TTL:           10 * time.Second,
+		RetryInterval: 100 * time.Millisecond,
+		AutoExtend:    true,
+	}
+}
+
+// LockManager hands out distributed locks with ownership tokens. Locks live
+// on a single Redis primary; every write checks the token, so a holder whose
+// lock expired can never release or extend someone else's.
+type LockManager struct {
+	client  goredis.UniversalClient
+	scripts *ScriptManager
+	config  LockConfig
+}
+
+// NewLockManager creates a lock manager
+func NewLockManager(client goredis.UniversalClient, config LockConfig) *LockManager {
+	defaults := DefaultLockConfig()
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaults.KeyPrefix
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaults.RetryInterval
+	}
+	return &LockManager{
+		client:  client,
+		scripts: NewScriptManager(client),
+		config:  config,
+	}
+}
+
+// BiddingSessionLockName returns the lock guarding bid acceptance for a session
+func BiddingSessionLockName(sessionID string) string {
+	return "bidding-session:" + sessionID
+}
+
+// WalletLockName returns the lock guarding balance changes of a wallet
+func WalletLockName(userID string) string {
+	return "wallet:" + userID
+}
+
+func (m *LockManager) key(name string) string {
+	return m.config.KeyPrefix + name
+}
+
+// Lock is a held distributed lock
+type Lock struct {
+	manager *LockManager
+	key     string
+	token   string
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	lost     chan struct{}
+	lostOnce sync.Once
+}
+
+// TryAcquire takes the lock once, returning ErrLockNotAcquired if it is held
+func (m *LockManager) TryAcquire(ctx context.Context, name string) (*Lock, error) {
+	lock := &Lock{
+		manager: m,
+		key:     m.key(name),
+		token:   uuid.NewString(),
+		stop:    make(chan struct{}),
+		lost:    make(chan struct{}),
+	}
+
+	ok, err := m.client.SetNX(ctx, lock.key, lock.token, m.config.TTL).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+	if !ok {
+		return nil, ErrLockNotAcquired
+	}
+
+	if m.config.AutoExtend {
+		go lock.heartbeat()
+	}
+	return lock, nil
+}
+
+// Acquire retries TryAcquire until the lock is taken or ctx is done
+func (m *LockManager) Acquire(ctx context.Context, name string) (*Lock, error) {
+	ticker := time.NewTicker(m.config.RetryInterval)
+	defer ticker.Stop()
+
+	for {
+		lock, err := m.TryAcquire(ctx, name)
+		if !errors.Is(err, ErrLockNotAcquired) {
+			return lock, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to acquire lock %s: %w", name, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithLock runs fn while holding the lock. fn's context is cancelled if the
+// lock is lost before fn returns. The lock is released even if fn panics.
+func (m *LockManager) WithLock(ctx context.Context, name string, fn func(ctx context.Context) error) (err error) {
+	lock, err := m.Acquire(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	fnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-lock.Lost():
+			cancel()
+		case <-fnCtx.Done():
+		}
+	}()
+
+	// Deferred so a panic in fn stops the heartbeat and frees the lock
+	// instead of leaving it extended for as long as the process lives
+	defer func() {
+		releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer releaseCancel()
+		if releaseErr := lock.Release(releaseCtx); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}()
+
+	return fn(fnCtx)
+}
+
+// Key returns the Redis key of the lock
+func (l *Lock) Key() string { return l.key }
+
+// Token returns the ownership token of the lock
+func (l *Lock) Token() string { return l.token }
+
+// Lost is closed when auto-extension finds the lock no longer held
+func (l *Lock) Lost() <-chan struct{} { return l.lost }
+
+// Extend resets the lock TTL if it is still held
+func (l *Lock) Extend(ctx context.Context, ttl time.Duration) error {
+	extended, err := l.manager.scripts.RunInt(ctx, ScriptCompareAndExpire, []string{l.key}, l.token, ttl.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to extend lock %s: %w", l.key, err)
+	}
+	if extended == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Release deletes the lock if it is still held and stops auto-extension
+func (l *Lock) Release(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+
+	deleted, err := l.manager.scripts.RunInt(ctx, ScriptCompareAndDelete, []string{l.key}, l.token)
+	if err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", l.key, err)
+	}
+	if deleted == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// heartbeat extends the lock every TTL/3 until released or lost
+func (l *Lock) heartbeat() {
+	ttl := l.manager.config.TTL
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), ttl/3)
+			err := l.Extend(ctx, ttl)
+			cancel()
+			if errors.Is(err, ErrLockNotHeld) {
+				log.Printf("⚠️ Lock %s lost before release", l.key)
+				l.lostOnce.Do(func() { close(l.lost) })
+				return
+			}
+			if err != nil {
+				log.Printf("⚠️ Failed to extend lock %s: %v", l.key, err)
+			}
+		}
+	}
+}
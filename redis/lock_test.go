@@ -0,0 +1,28 @@
+package redis
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithLockReleasesOnPanic(t *testing.T) {
+	ctx := context.Background()
+	client, mr := newTestClient(t)
+	locks := NewLockManager(client, LockConfig{AutoExtend: true})
+
+	func() {
+		defer func() { recover() }()
+		locks.WithLock(ctx, "job", func(ctx context.Context) error {
+			panic("boom")
+		})
+	}()
+
+	if keys := mr.Keys(); len(keys) != 0 {
+		t.Fatalf("lock still held after panic: %v", keys)
+	}
+	lock, err := locks.TryAcquire(ctx, "job")
+	if err != nil {
+		t.Fatalf("TryAcquire after panic: %v", err)
+	}
+	lock.Release(ctx)
+}
@@ -0,0 +1,207 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// PrefixedRedisService namespaces every key of an IRedisService so services
+// sharing a Redis do not collide. Keys passed to and returned from its
+// methods are unprefixed. Pub/sub channels are left as is because they are
+// shared between services. Commands issued through Client, Pipeline,
+// Pipelined, TxPipelined or inside Watch bypass the namespace; build their
+// keys with Key.
+type PrefixedRedisService struct {
+	IRedisService
+	prefix string
+}
+
+// WithPrefix wraps svc so all keys are stored under prefix, e.g. "trip:"
+func WithPrefix(svc IRedisService, prefix string) *PrefixedRedisService {
+	if p, ok := svc.(*PrefixedRedisService); ok {
+		return &PrefixedRedisService{IRedisService: p.IRedisService, prefix: p.prefix + prefix}
+	}
+	return &PrefixedRedisService{IRedisService: svc, prefix: prefix}
+}
+
+// Prefix returns the namespace prefix
+func (p *PrefixedRedisService) Prefix() string {
+	return p.prefix
+}
+
+// Key returns key inside the namespace
+func (p *PrefixedRedisService) Key(key string) string {
+	return p.prefix + key
+}
+
+func (p *PrefixedRedisService) keys(keys []string) []string {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = p.prefix + key
+	}
+	return prefixed
+}
+
+func (p *PrefixedRedisService) strip(key string) string {
+	return strings.TrimPrefix(key, p.prefix)
+}
+
+func (p *PrefixedRedisService) Get(ctx context.Context, key string) (string, error) {
+	return p.IRedisService.Get(ctx, p.Key(key))
+}
+
+func (p *PrefixedRedisService) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return p.IRedisService.Set(ctx, p.Key(key), value, expiration)
+}
+
+func (p *PrefixedRedisService) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return p.IRedisService.SetNX(ctx, p.Key(key), value, expiration)
+}
+
+func (p *PrefixedRedisService) Del(ctx context.Context, keys ...string) (int64, error) {
+	return p.IRedisService.Del(ctx, p.keys(keys)...)
+}
+
+func (p *PrefixedRedisService) Exists(ctx context.Context, keys ...string) (int64, error) {
+	return p.IRedisService.Exists(ctx, p.keys(keys)...)
+}
+
+func (p *PrefixedRedisService) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	return p.IRedisService.Expire(ctx, p.Key(key), expiration)
+}
+
+func (p *PrefixedRedisService) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return p.IRedisService.TTL(ctx, p.Key(key))
+}
+
+// Scan matches inside the namespace and returns unprefixed keys
+func (p *PrefixedRedisService) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	if match == "" {
+		match = "*"
+	}
+	keys, next, err := p.IRedisService.Scan(ctx, cursor, p.Key(match), count)
+	for i, key := range keys {
+		keys[i] = p.strip(key)
+	}
+	return keys, next, err
+}
+
+func (p *PrefixedRedisService) Incr(ctx context.Context, key string) (int64, error) {
+	return p.IRedisService.Incr(ctx, p.Key(key))
+}
+
+func (p *PrefixedRedisService) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return p.IRedisService.IncrBy(ctx, p.Key(key), value)
+}
+
+func (p *PrefixedRedisService) HSet(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return p.IRedisService.HSet(ctx, p.Key(key), values...)
+}
+
+func (p *PrefixedRedisService) HGet(ctx context.Context, key, field string) (string, error) {
+	return p.IRedisService.HGet(ctx, p.Key(key), field)
+}
+
+func (p *PrefixedRedisService) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return p.IRedisService.HGetAll(ctx, p.Key(key))
+}
+
+func (p *PrefixedRedisService) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	return p.IRedisService.HDel(ctx, p.Key(key), fields...)
+}
+
+func (p *PrefixedRedisService) SAdd(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return p.IRedisService.SAdd(ctx, p.Key(key), members...)
+}
+
+func (p *PrefixedRedisService) SRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return p.IRedisService.SRem(ctx, p.Key(key), members...)
+}
+
+func (p *PrefixedRedisService) SMembers(ctx context.Context, key string) ([]string, error) {
+	return p.IRedisService.SMembers(ctx, p.Key(key))
+}
+
+func (p *PrefixedRedisService) ZAdd(ctx context.Context, key string, members ...goredis.Z) (int64, error) {
+	return p.IRedisService.ZAdd(ctx, p.Key(key), members...)
+}
+
+func (p *PrefixedRedisService) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return p.IRedisService.ZRange(ctx, p.Key(key), start, stop)
+}
+
+func (p *PrefixedRedisService) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return p.IRedisService.ZRem(ctx, p.Key(key), members...)
+}
+
+func (p *PrefixedRedisService) JSONSet(ctx context.Context, key, path string, value interface{}) error {
+	return p.IRedisService.JSONSet(ctx, p.Key(key), path, value)
+}
+
+func (p *PrefixedRedisService) JSONGet(ctx context.Context, key, path string) (string, error) {
+	return p.IRedisService.JSONGet(ctx, p.Key(key), path)
+}
+
+func (p *PrefixedRedisService) JSONDel(ctx context.Context, key, path string) (int64, error) {
+	return p.IRedisService.JSONDel(ctx, p.Key(key), path)
+}
+
+func (p *PrefixedRedisService) PFAdd(ctx context.Context, key string, elements ...interface{}) (int64, error) {
+	return p.IRedisService.PFAdd(ctx, p.Key(key), elements...)
+}
+
+func (p *PrefixedRedisService) PFCount(ctx context.Context, keys ...string) (int64, error) {
+	return p.IRedisService.PFCount(ctx, p.keys(keys)...)
+}
+
+func (p *PrefixedRedisService) PFMerge(ctx context.Context, dest string, keys ...string) error {
+	return p.IRedisService.PFMerge(ctx, p.Key(dest), p.keys(keys)...)
+}
+
+func (p *PrefixedRedisService) XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLen int64) (string, error) {
+	return p.IRedisService.XAdd(ctx, p.Key(stream), values, maxLen)
+}
+
+func (p *PrefixedRedisService) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	return p.IRedisService.XGroupCreate(ctx, p.Key(stream), group, start)
+}
+
+// XReadGroup prefixes the stream names in args and strips them from the reply
+func (p *PrefixedRedisService) XReadGroup(ctx context.Context, args *goredis.XReadGroupArgs) ([]goredis.XStream, error) {
+	prefixed := *args
+	// Streams holds the stream names followed by one ID per stream
+	prefixed.Streams = append([]string(nil), args.Streams...)
+	for i := 0; i < len(prefixed.Streams)/2; i++ {
+		prefixed.Streams[i] = p.Key(prefixed.Streams[i])
+	}
+
+	streams, err := p.IRedisService.XReadGroup(ctx, &prefixed)
+	for i := range streams {
+		streams[i].Stream = p.strip(streams[i].Stream)
+	}
+	return streams, err
+}
+
+func (p *PrefixedRedisService) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	return p.IRedisService.XAck(ctx, p.Key(stream), group, ids...)
+}
+
+func (p *PrefixedRedisService) XAutoClaim(ctx context.Context, args *goredis.XAutoClaimArgs) ([]goredis.XMessage, string, error) {
+	prefixed := *args
+	prefixed.Stream = p.Key(args.Stream)
+	return p.IRedisService.XAutoClaim(ctx, &prefixed)
+}
+
+func (p *PrefixedRedisService) XPendingExt(ctx context.Context, args *goredis.XPendingExtArgs) ([]goredis.XPendingExt, error) {
+	prefixed := *args
+	prefixed.Stream = p.Key(args.Stream)
+	return p.IRedisService.XPendingExt(ctx, &prefixed)
+}
+
+// Watch prefixes the watched keys; commands inside fn must use Key
+func (p *PrefixedRedisService) Watch(ctx context.Context, fn func(*goredis.Tx) error, keys ...string) error {
+	return p.IRedisService.Watch(ctx, fn, p.keys(keys)...)
+}
@@ -0,0 +1,219 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// PFAdd adds elements to a HyperLogLog
+func (r *RedisService) PFAdd(ctx context.Context, key string, elements ...interface{}) (int64, error) {
+	return r.client.PFAdd(ctx, key, elements...).Result()
+}
+
+// PFCount returns the approximate cardinality of the union of the HyperLogLogs
+func (r *RedisService) PFCount(ctx context.Context, keys ...string) (int64, error) {
+	return r.client.PFCount(ctx, keys...).Result()
+}
+
+// PFMerge merges HyperLogLogs into dest
+func (r *RedisService) PFMerge(ctx context.Context, dest string, keys ...string) error {
+	return r.client.PFMerge(ctx, dest, keys...).Err()
+}
+
+// UniqueCounter counts distinct members per bucket (e.g. riders per zone per day)
+// using HyperLogLog, at roughly 12KB per bucket regardless of cardinality
+type UniqueCounter struct {
+	redis  IRedisService
+	prefix string
+	ttl    time.Duration
+}
+
+// NewUniqueCounter creates a counter storing buckets under prefix with ttl
+func NewUniqueCounter(svc IRedisService, prefix string, ttl time.Duration) *UniqueCounter {
+	return &UniqueCounter{redis: svc, prefix: prefix, ttl: ttl}
+}
+
+func (u *UniqueCounter) key(bucket string) string {
+	return u.prefix + ":" + bucket
+}
+
+// Add records members in bucket
+func (u *UniqueCounter) Add(ctx context.Context, bucket string, members ...interface{}) error {
+	key := u.key(bucket)
+	_, err := u.redis.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.PFAdd(ctx, key, members...)
+		if u.ttl > 0 {
+			pipe.Expire(ctx, key, u.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add to %s: %w", key, err)
+	}
+	return nil
+}
+
+// Count returns the approximate number of distinct members across buckets
+func (u *UniqueCounter) Count(ctx context.Context, buckets ...string) (int64, error) {
+	keys := make([]string, len(buckets))
+	for i, b := range buckets {
+		keys[i] = u.key(b)
+	}
+	return u.redis.PFCount(ctx, keys...)
+}
+
+// BloomFilter is a probabilistic set: Exists may return false positives but
+// never false negatives
+type BloomFilter interface {
+	// Add inserts item and reports whether it was (probably) not present before
+	Add(ctx context.Context, item string) (bool, error)
+	// Exists reports whether item is probably present
+	Exists(ctx context.Context, item string) (bool, error)
+}
+
+// BloomConfig sizes a bloom filter
+type BloomConfig struct {
+	Capacity  int64
+	ErrorRate float64
+	// TTL expires the fallback bitset; RedisBloom filters are not expired
+	TTL time.Duration
+}
+
+// NewBloomFilter returns a RedisBloom-backed filter when the module is loaded
+// and falls back to a plain Redis bitset otherwise
+func NewBloomFilter(ctx context.Context, svc IRedisService, key string, config BloomConfig) (BloomFilter, error) {
+	if config.Capacity <= 0 {
+		config.Capacity = 100000
+	}
+	if config.ErrorRate <= 0 || config.ErrorRate >= 1 {
+		config.ErrorRate = 0.01
+	}
+
+	err := svc.Client().BFReserve(ctx, key, config.ErrorRate, config.Capacity).Err()
+	switch {
+	case err == nil, isItemExists(err):
+		return &redisBloom{client: svc.Client(), key: key}, nil
+	case isUnknownCommand(err):
+		return newBitsetBloom(svc.Client(), key, config), nil
+	default:
+		return nil, fmt.Errorf("failed to reserve bloom filter %s: %w", key, err)
+	}
+}
+
+func isUnknownCommand(err error) bool {
+	var redisErr goredis.Error
+	if !errors.As(err, &redisErr) {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "unknown command") || strings.Contains(msg, "unknown subcommand")
+}
+
+func isItemExists(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "item exists")
+}
+
+// redisBloom uses the RedisBloom BF.* commands
+type redisBloom struct {
+	client goredis.UniversalClient
+	key    string
+}
+
+func (b *redisBloom) Add(ctx context.Context, item string) (bool, error) {
+	return b.client.BFAdd(ctx, b.key, item).Result()
+}
+
+func (b *redisBloom) Exists(ctx context.Context, item string) (bool, error) {
+	return b.client.BFExists(ctx, b.key, item).Result()
+}
+
+// bitsetBloom implements a bloom filter over SETBIT/GETBIT
+type bitsetBloom struct {
+	client goredis.UniversalClient
+	key    string
+	bits   uint64
+	hashes int
+	ttl    time.Duration
+}
+
+func newBitsetBloom(client goredis.UniversalClient, key string, config BloomConfig) *bitsetBloom {
+	n := float64(config.Capacity)
+	m := math.Ceil(-n * math.Log(config.ErrorRate) / (math.Ln2 * math.Ln2))
+	k := int(math.Max(1, math.Round(m/n*math.Ln2)))
+
+	return &bitsetBloom{
+		client: client,
+		key:    key,
+		bits:   uint64(m),
+		hashes: k,
+		ttl:    config.TTL,
+	}
+}
+
+// offsets derives k bit positions with double hashing
+func (b *bitsetBloom) offsets(item string) []int64 {
+	h := fnv.New64a()
+	h.Write([]byte(item))
+	h1 := h.Sum64()
+	h2 := h1>>33 | h1<<31 | 1
+
+	offsets := make([]int64, b.hashes)
+	for i := range offsets {
+		offsets[i] = int64((h1 + uint64(i)*h2) % b.bits)
+	}
+	return offsets
+}
+
+func (b *bitsetBloom) Add(ctx context.Context, item string) (bool, error) {
+	offsets := b.offsets(item)
+	cmds := make([]*goredis.IntCmd, len(offsets))
+
+	_, err := b.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, off := range offsets {
+			cmds[i] = pipe.SetBit(ctx, b.key, off, 1)
+		}
+		if b.ttl > 0 {
+			pipe.Expire(ctx, b.key, b.ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, cmd := range cmds {
+		if cmd.Val() == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (b *bitsetBloom) Exists(ctx context.Context, item string) (bool, error) {
+	offsets := b.offsets(item)
+	cmds := make([]*goredis.IntCmd, len(offsets))
+
+	_, err := b.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, off := range offsets {
+			cmds[i] = pipe.GetBit(ctx, b.key, off)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, cmd := range cmds {
+		if cmd.Val() == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
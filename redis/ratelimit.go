@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Rate limiting algorithms
+const (
+	// AlgorithmFixedWindow counts requests per aligned window; cheap but allows
+	// bursts of up to twice the limit across a window boundary
+	AlgorithmFixedWindow = "fixed_window"
+	// AlgorithmSlidingWindow keeps a log of request times; exact but stores one
+	// entry per request
+	AlgorithmSlidingWindow = "sliding_window"
+	// AlgorithmTokenBucket refills Limit tokens per Window and allows bursts of Limit
+	AlgorithmTokenBucket = "token_bucket"
+)
+
+// Limit allows Limit requests per Window using Algorithm
+type Limit struct {
+	Algorithm string
+	Limit     int64
+	Window    time.Duration
+}
+
+// LimitResult is the outcome of a rate limit check
+type LimitResult struct {
+	Allowed   bool
+	Remaining int64
+	// ResetAt is when the full quota is available again
+	ResetAt time.Time
+	// RetryAfter is how long a denied caller should wait; zero when allowed
+	RetryAfter time.Duration
+}
+
+const (
+	scriptFixedWindow   = "ratelimit_fixed_window"
+	scriptSlidingWindow = "ratelimit_sliding_window"
+	scriptTokenBucket   = "ratelimit_token_bucket"
+)
+
+// KEYS[1] counter; ARGV limit, window (ms)
+// Returns {allowed, remaining, reset ms, retry ms}
+const fixedWindowScript = `
+local limit = tonumber(ARGV[1])
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if count > limit then
+  return {0, 0, ttl, ttl}
+end
+return {1, limit - count, ttl, 0}
+`
+
+// KEYS[1] log; ARGV limit, window (ms), now (ms), member
+const slidingWindowScript = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+  redis.call('ZADD', KEYS[1], now, ARGV[4])
+  count = count + 1
+  allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local reset = 0
+if oldest[2] then
+  reset = tonumber(oldest[2]) + window - now
+end
+local retry = 0
+if allowed == 0 then
+  retry = reset
+end
+return {allowed, limit - count, reset, retry}
+`
+
+// KEYS[1] bucket; ARGV capacity, window (ms), now (ms)
+const tokenBucketScript = `
+local capacity = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local rate = capacity / window
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1]) or capacity
+local ts = tonumber(data[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], window)
+local reset = math.ceil((capacity - tokens) / rate)
+return {allowed, math.floor(tokens), reset, retry}
+`
+
+// RateLimiter enforces limits on arbitrary identifiers (user ID, phone, IP)
+// shared by all replicas through Redis
+type RateLimiter struct {
+	client  goredis.UniversalClient
+	scripts *ScriptManager
+	prefix  string
+}
+
+// NewRateLimiter creates a rate limiter; keys are stored under prefix
+func NewRateLimiter(client goredis.UniversalClient, prefix string) *RateLimiter {
+	if prefix == "" {
+		prefix = "ratelimit:"
+	}
+	scripts := NewScriptManager(client)
+	_ = scripts.Register(scriptFixedWindow, fixedWindowScript)
+	_ = scripts.Register(scriptSlidingWindow, slidingWindowScript)
+	_ = scripts.Register(scriptTokenBucket, tokenBucketScript)
+
+	return &RateLimiter{
+		client:  client,
+		scripts: scripts,
+		prefix:  prefix,
+	}
+}
+
+func (l *RateLimiter) key(algorithm, id string) string {
+	return l.prefix + algorithm + ":" + id
+}
+
+// Allow records a request for id and reports whether it is within limit
+func (l *RateLimiter) Allow(ctx context.Context, id string, limit Limit) (LimitResult, error) {
+	if limit.Limit <= 0 || limit.Window <= 0 {
+		return LimitResult{}, fmt.Errorf("invalid rate limit %d per %v", limit.Limit, limit.Window)
+	}
+
+	now := time.Now()
+	window := limit.Window.Milliseconds()
+	key := []string{l.key(limit.Algorithm, id)}
+
+	var res []int64
+	var err error
+	switch limit.Algorithm {
+	case AlgorithmFixedWindow:
+		res, err = l.scripts.RunInts(ctx, scriptFixedWindow, key, limit.Limit, window)
+	case AlgorithmSlidingWindow:
+		res, err = l.scripts.RunInts(ctx, scriptSlidingWindow, key, limit.Limit, window, now.UnixMilli(), uuid.NewString())
+	case AlgorithmTokenBucket:
+		res, err = l.scripts.RunInts(ctx, scriptTokenBucket, key, limit.Limit, window, now.UnixMilli())
+	default:
+		return LimitResult{}, fmt.Errorf("unknown rate limit algorithm %q", limit.Algorithm)
+	}
+	if err != nil {
+		return LimitResult{}, fmt.Errorf("failed to check rate limit for %s: %w", id, err)
+	}
+
+	return LimitResult{
+		Allowed:    res[0] == 1,
+		Remaining:  res[1],
+		ResetAt:    now.Add(time.Duration(res[2]) * time.Millisecond),
+		RetryAfter: time.Duration(res[3]) * time.Millisecond,
+	}, nil
+}
+
+// Reset clears the state of id for an algorithm, e.g. after a successful login
+func (l *RateLimiter) Reset(ctx context.Context, id, algorithm string) error {
+	return l.client.Del(ctx, l.key(algorithm, id)).Err()
+}
@@ -0,0 +1,282 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Nil is returned when a key does not exist
+const Nil = goredis.Nil
+
+// IRedisService defines the Redis operations shared by all services
+type IRedisService interface {
+	Ping(ctx context.Context) error
+	Close() error
+	// Client exposes the underlying client for commands not covered here
+	Client() goredis.UniversalClient
+
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Del(ctx context.Context, keys ...string) (int64, error)
+	Exists(ctx context.Context, keys ...string) (int64, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) (bool, error)
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// Scan runs one SCAN step; never use KEYS in production
+	Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error)
+	Incr(ctx context.Context, key string) (int64, error)
+	IncrBy(ctx context.Context, key string, value int64) (int64, error)
+
+	HSet(ctx context.Context, key string, values ...interface{}) (int64, error)
+	HGet(ctx context.Context, key, field string) (string, error)
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HDel(ctx context.Context, key string, fields ...string) (int64, error)
+
+	SAdd(ctx context.Context, key string, members ...interface{}) (int64, error)
+	SRem(ctx context.Context, key string, members ...interface{}) (int64, error)
+	SMembers(ctx context.Context, key string) ([]string, error)
+
+	ZAdd(ctx context.Context, key string, members ...goredis.Z) (int64, error)
+	ZRange(ctx context.Context, key string, start, stop int64) ([]string, error)
+	ZRem(ctx context.Context, key string, members ...interface{}) (int64, error)
+
+	// JSONNative reports whether JSON paths are backed by the RedisJSON module
+	JSONNative(ctx context.Context) bool
+	JSONSet(ctx context.Context, key, path string, value interface{}) error
+	JSONGet(ctx context.Context, key, path string) (string, error)
+	JSONDel(ctx context.Context, key, path string) (int64, error)
+
+	PFAdd(ctx context.Context, key string, elements ...interface{}) (int64, error)
+	PFCount(ctx context.Context, keys ...string) (int64, error)
+	PFMerge(ctx context.Context, dest string, keys ...string) error
+
+	// XAdd appends to a stream, trimming it to about maxLen entries when maxLen > 0
+	XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLen int64) (string, error)
+	// XGroupCreate creates a consumer group, creating the stream if needed;
+	// an existing group is not an error
+	XGroupCreate(ctx context.Context, stream, group, start string) error
+	XReadGroup(ctx context.Context, args *goredis.XReadGroupArgs) ([]goredis.XStream, error)
+	XAck(ctx context.Context, stream, group string, ids ...string) (int64, error)
+	XAutoClaim(ctx context.Context, args *goredis.XAutoClaimArgs) ([]goredis.XMessage, string, error)
+	XPendingExt(ctx context.Context, args *goredis.XPendingExtArgs) ([]goredis.XPendingExt, error)
+
+	Publish(ctx context.Context, channel string, message interface{}) error
+	Subscribe(ctx context.Context, channels ...string) *goredis.PubSub
+
+	// Pipeline returns a non-transactional pipeline; call Exec to flush it
+	Pipeline() goredis.Pipeliner
+	// Pipelined queues commands in fn and sends them in a single round trip
+	Pipelined(ctx context.Context, fn func(goredis.Pipeliner) error) ([]goredis.Cmder, error)
+
+	// TxPipelined queues commands in fn and executes them atomically in MULTI/EXEC
+	TxPipelined(ctx context.Context, fn func(goredis.Pipeliner) error) ([]goredis.Cmder, error)
+	// Watch runs fn in an optimistic transaction guarded by WATCH on keys
+	Watch(ctx context.Context, fn func(*goredis.Tx) error, keys ...string) error
+}
+
+// RedisService implements IRedisService on top of go-redis
+type RedisService struct {
+	client goredis.UniversalClient
+	config RedisConfig
+	json   jsonSupport
+
+	// replicas serve read-only commands when ReplicaAddrs is set
+	replicas    []goredis.UniversalClient
+	replicaNext atomic.Uint64
+}
+
+// NewRedisService connects to Redis in standalone, cluster or sentinel mode
+// and verifies the connection. In cluster mode, multi-key commands, TxPipelined
+// and Watch require all keys to share a hash slot, e.g. via {hash tags}.
+func NewRedisService(config RedisConfig) (*RedisService, error) {
+	return NewRedisServiceContext(context.Background(), config)
+}
+
+// NewRedisServiceContext is NewRedisService with a context bounding the
+// startup retries. The first Ping is retried StartupRetries times with
+// exponential backoff so a briefly unavailable Redis does not fail a rolling
+// deploy; with LazyConnect it is skipped and the pool connects on first use.
+func NewRedisServiceContext(ctx context.Context, config RedisConfig) (*RedisService, error) {
+	client, err := config.NewClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure redis client: %w", err)
+	}
+
+	replicas, err := config.NewReplicaClients()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to configure redis replicas: %w", err)
+	}
+
+	service := &RedisService{
+		client:   client,
+		config:   config,
+		replicas: replicas,
+	}
+
+	if config.LazyConnect {
+		log.Printf("✅ Redis (%s) configured at %s, connecting lazily", config.Mode(), strings.Join(config.Addrs(), ","))
+		return service, nil
+	}
+
+	if err := service.pingWithRetry(ctx); err != nil {
+		service.Close()
+		return nil, err
+	}
+
+	log.Printf("✅ Connected to Redis (%s) at %s", config.Mode(), strings.Join(config.Addrs(), ","))
+	return service, nil
+}
+
+// pingWithRetry pings until success, StartupRetries is exhausted or ctx ends
+func (r *RedisService) pingWithRetry(ctx context.Context) error {
+	backoff := r.config.StartupBackoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+
+	for attempt := 0; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := r.Ping(pingCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if attempt >= r.config.StartupRetries {
+			return err
+		}
+
+		log.Printf("⚠️ Redis not reachable (attempt %d/%d), retrying in %v: %v", attempt+1, r.config.StartupRetries+1, backoff, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up connecting to redis: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > 10*time.Second {
+			backoff = 10 * time.Second
+		}
+	}
+}
+
+// NewRedisServiceFromClient wraps an existing client
+func NewRedisServiceFromClient(client goredis.UniversalClient) *RedisService {
+	return &RedisService{client: client}
+}
+
+// Ping checks the connection
+func (r *RedisService) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connection pool
+func (r *RedisService) Close() error {
+	r.closeReplicas()
+	return r.client.Close()
+}
+
+// Client returns the underlying client
+func (r *RedisService) Client() goredis.UniversalClient {
+	return r.client
+}
+
+// Get reads key, from a replica when replicas are configured
+func (r *RedisService) Get(ctx context.Context, key string) (string, error) {
+	return readFrom(ctx, r, func(c goredis.Cmdable) (string, error) {
+		return c.Get(ctx, key).Result()
+	})
+}
+
+func (r *RedisService) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return r.client.Set(ctx, key, value, expiration).Err()
+}
+
+func (r *RedisService) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, expiration).Result()
+}
+
+func (r *RedisService) Del(ctx context.Context, keys ...string) (int64, error) {
+	return r.client.Del(ctx, keys...).Result()
+}
+
+func (r *RedisService) Exists(ctx context.Context, keys ...string) (int64, error) {
+	return r.client.Exists(ctx, keys...).Result()
+}
+
+func (r *RedisService) Expire(ctx context.Context, key string, expiration time.Duration) (bool, error) {
+	return r.client.Expire(ctx, key, expiration).Result()
+}
+
+func (r *RedisService) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return r.client.TTL(ctx, key).Result()
+}
+
+func (r *RedisService) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
+func (r *RedisService) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return r.client.IncrBy(ctx, key, value).Result()
+}
+
+func (r *RedisService) HSet(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return r.client.HSet(ctx, key, values...).Result()
+}
+
+func (r *RedisService) HGet(ctx context.Context, key, field string) (string, error) {
+	return r.client.HGet(ctx, key, field).Result()
+}
+
+// HGetAll reads a hash, from a replica when replicas are configured
+func (r *RedisService) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return readFrom(ctx, r, func(c goredis.Cmdable) (map[string]string, error) {
+		return c.HGetAll(ctx, key).Result()
+	})
+}
+
+func (r *RedisService) HDel(ctx context.Context, key string, fields ...string) (int64, error) {
+	return r.client.HDel(ctx, key, fields...).Result()
+}
+
+func (r *RedisService) SAdd(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return r.client.SAdd(ctx, key, members...).Result()
+}
+
+func (r *RedisService) SRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return r.client.SRem(ctx, key, members...).Result()
+}
+
+func (r *RedisService) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, key).Result()
+}
+
+func (r *RedisService) ZAdd(ctx context.Context, key string, members ...goredis.Z) (int64, error) {
+	return r.client.ZAdd(ctx, key, members...).Result()
+}
+
+// ZRange reads a sorted set range, from a replica when replicas are configured
+func (r *RedisService) ZRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return readFrom(ctx, r, func(c goredis.Cmdable) ([]string, error) {
+		return c.ZRange(ctx, key, start, stop).Result()
+	})
+}
+
+func (r *RedisService) ZRem(ctx context.Context, key string, members ...interface{}) (int64, error) {
+	return r.client.ZRem(ctx, key, members...).Result()
+}
+
+func (r *RedisService) Publish(ctx context.Context, channel string, message interface{}) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+func (r *RedisService) Subscribe(ctx context.Context, channels ...string) *goredis.PubSub {
+	return r.client.Subscribe(ctx, channels...)
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// NewReplicaClients creates one client per ReplicaAddrs entry, sharing the
+// primary's credentials, TLS and timeouts
+func (c RedisConfig) NewReplicaClients() ([]goredis.UniversalClient, error) {
+	if len(c.ReplicaAddrs) == 0 {
+		return nil, nil
+	}
+	tlsConfig, err := c.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make([]goredis.UniversalClient, 0, len(c.ReplicaAddrs))
+	for _, addr := range c.ReplicaAddrs {
+		replicas = append(replicas, goredis.NewClient(&goredis.Options{
+			Addr:         addr,
+			Username:     c.Username,
+			Password:     c.Password,
+			TLSConfig:    tlsConfig,
+			DB:           c.DB,
+			PoolSize:     c.PoolSize,
+			DialTimeout:  c.DialTimeout,
+			ReadTimeout:  c.ReadTimeout,
+			WriteTimeout: c.WriteTimeout,
+		}))
+	}
+	return replicas, nil
+}
+
+// Reader returns the client read-only commands should use: the next replica
+// in round-robin order, or the primary when no replicas are configured.
+// Replicas lag the primary, so never use it for read-your-writes flows.
+func (r *RedisService) Reader() goredis.UniversalClient {
+	if len(r.replicas) == 0 {
+		return r.client
+	}
+	next := r.replicaNext.Add(1)
+	return r.replicas[next%uint64(len(r.replicas))]
+}
+
+// readFrom runs a read-only command on a replica, retrying it on the primary
+// if the replica fails. A missing key (Nil) is a valid answer, not a failure.
+func readFrom[T any](ctx context.Context, r *RedisService, fn func(goredis.Cmdable) (T, error)) (T, error) {
+	reader := r.Reader()
+	result, err := fn(reader)
+	if err == nil || errors.Is(err, goredis.Nil) || reader == r.client || ctx.Err() != nil {
+		return result, err
+	}
+
+	log.Printf("⚠️ Redis replica read failed, falling back to primary: %v", err)
+	return fn(r.client)
+}
+
+func (r *RedisService) closeReplicas() {
+	for _, replica := range r.replicas {
+		if err := replica.Close(); err != nil {
+			log.Printf("⚠️ Failed to close redis replica: %v", err)
+		}
+	}
+}
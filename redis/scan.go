@@ -0,0 +1,109 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// DefaultScanCount is the SCAN COUNT hint used when none is given
+const DefaultScanCount = 100
+
+// Scan runs a single SCAN step; use ScanIterator or ScanAll instead of KEYS,
+// which blocks the server while it walks the whole keyspace
+func (r *RedisService) Scan(ctx context.Context, cursor uint64, match string, count int64) ([]string, uint64, error) {
+	return r.client.Scan(ctx, cursor, match, count).Result()
+}
+
+// ScanIterator walks the keys matching a pattern in batches. In cluster mode
+// it covers a single node; use ScanAll to visit every master.
+type ScanIterator struct {
+	client  goredis.Cmdable
+	match   string
+	count   int64
+	cursor  uint64
+	started bool
+	keys    []string
+	key     string
+	err     error
+}
+
+// NewScanIterator iterates keys matching match (e.g. "trip:*"), fetching
+// about count keys per round trip
+func NewScanIterator(client goredis.Cmdable, match string, count int64) *ScanIterator {
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+	return &ScanIterator{client: client, match: match, count: count}
+}
+
+// Next advances to the next key; it returns false when done, on error, or
+// when ctx is cancelled
+func (it *ScanIterator) Next(ctx context.Context) bool {
+	for len(it.keys) == 0 {
+		if it.err != nil || (it.started && it.cursor == 0) {
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		keys, cursor, err := it.client.Scan(ctx, it.cursor, it.match, it.count).Result()
+		if err != nil {
+			it.err = fmt.Errorf("failed to scan %q: %w", it.match, err)
+			return false
+		}
+		it.keys, it.cursor, it.started = keys, cursor, true
+	}
+
+	it.key, it.keys = it.keys[0], it.keys[1:]
+	return true
+}
+
+// Key returns the current key
+func (it *ScanIterator) Key() string {
+	return it.key
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *ScanIterator) Err() error {
+	return it.err
+}
+
+// ScanAll calls fn for every key matching match, visiting every master in
+// cluster mode. Keys may be reported more than once if the keyspace changes
+// during the scan, so fn should be idempotent; in cluster mode masters are
+// scanned concurrently, so fn must also be safe for concurrent use.
+// Iteration stops at the first error from fn or when ctx is cancelled. On a
+// PrefixedRedisService the match is scoped to its namespace and keys are
+// reported unprefixed.
+func ScanAll(ctx context.Context, svc IRedisService, match string, count int64, fn func(key string) error) error {
+	prefix := ""
+	if p, ok := svc.(*PrefixedRedisService); ok {
+		prefix = p.Prefix()
+		if match == "" {
+			match = "*"
+		}
+		match = prefix + match
+	}
+
+	scan := func(ctx context.Context, client goredis.Cmdable) error {
+		it := NewScanIterator(client, match, count)
+		for it.Next(ctx) {
+			if err := fn(strings.TrimPrefix(it.Key(), prefix)); err != nil {
+				return err
+			}
+		}
+		return it.Err()
+	}
+
+	if cluster, ok := svc.Client().(*goredis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *goredis.Client) error {
+			return scan(ctx, node)
+		})
+	}
+	return scan(ctx, svc.Client())
+}
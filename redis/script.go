@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Built-in scripts registered on every ScriptManager
+const (
+	// ScriptCompareAndDelete deletes KEYS[1] only if it holds ARGV[1]
+	ScriptCompareAndDelete = "compare_and_delete"
+	// ScriptCompareAndExpire sets the TTL of KEYS[1] to ARGV[2] ms only if it holds ARGV[1]
+	ScriptCompareAndExpire = "compare_and_expire"
+)
+
+var builtinScripts = map[string]string{
+	ScriptCompareAndDelete: `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`,
+	ScriptCompareAndExpire: `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`,
+}
+
+// ScriptManager keeps named Lua scripts and runs them with EVALSHA, falling
+// back to EVAL when the server has lost its script cache (NOSCRIPT)
+type ScriptManager struct {
+	client goredis.UniversalClient
+
+	mu      sync.RWMutex
+	scripts map[string]*goredis.Script
+}
+
+// NewScriptManager creates a manager with the built-in scripts registered
+func NewScriptManager(client goredis.UniversalClient) *ScriptManager {
+	m := &ScriptManager{
+		client:  client,
+		scripts: make(map[string]*goredis.Script),
+	}
+	for name, src := range builtinScripts {
+		m.scripts[name] = goredis.NewScript(src)
+	}
+	return m
+}
+
+// Register adds a named script; registering a name twice is an error
+func (m *ScriptManager) Register(name, src string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.scripts[name]; exists {
+		return fmt.Errorf("script %s already registered", name)
+	}
+	m.scripts[name] = goredis.NewScript(src)
+	return nil
+}
+
+// Names returns the registered script names, sorted
+func (m *ScriptManager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := make([]string, 0, len(m.scripts))
+	for name := range m.scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load uploads every registered script with SCRIPT LOAD so the first EVALSHA
+// does not miss; call it at startup
+func (m *ScriptManager) Load(ctx context.Context) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for name, script := range m.scripts {
+		if err := script.Load(ctx, m.client).Err(); err != nil {
+			return fmt.Errorf("failed to load script %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (m *ScriptManager) script(name string) (*goredis.Script, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	script, ok := m.scripts[name]
+	if !ok {
+		return nil, fmt.Errorf("script %s not registered", name)
+	}
+	return script, nil
+}
+
+// Run executes a named script
+func (m *ScriptManager) Run(ctx context.Context, name string, keys []string, args ...interface{}) (interface{}, error) {
+	script, err := m.script(name)
+	if err != nil {
+		return nil, err
+	}
+	result, err := script.Run(ctx, m.client, keys, args...).Result()
+	if err != nil && err != goredis.Nil {
+		return nil, fmt.Errorf("failed to run script %s: %w", name, err)
+	}
+	return result, err
+}
+
+// Queue adds a named script call to pipe. It uses EVAL, since a pipelined
+// EVALSHA cannot fall back when the script cache was flushed.
+func (m *ScriptManager) Queue(ctx context.Context, pipe goredis.Pipeliner, name string, keys []string, args ...interface{}) (*goredis.Cmd, error) {
+	script, err := m.script(name)
+	if err != nil {
+		return nil, err
+	}
+	return script.Eval(ctx, pipe, keys, args...), nil
+}
+
+// RunInt executes a script returning an integer
+func (m *ScriptManager) RunInt(ctx context.Context, name string, keys []string, args ...interface{}) (int64, error) {
+	return runTyped(ctx, m, name, keys, args, (*goredis.Cmd).Int64)
+}
+
+// RunInts executes a script returning an array of integers
+func (m *ScriptManager) RunInts(ctx context.Context, name string, keys []string, args ...interface{}) ([]int64, error) {
+	return runTyped(ctx, m, name, keys, args, (*goredis.Cmd).Int64Slice)
+}
+
+// RunString executes a script returning a string
+func (m *ScriptManager) RunString(ctx context.Context, name string, keys []string, args ...interface{}) (string, error) {
+	return runTyped(ctx, m, name, keys, args, (*goredis.Cmd).Text)
+}
+
+// RunStrings executes a script returning an array of strings
+func (m *ScriptManager) RunStrings(ctx context.Context, name string, keys []string, args ...interface{}) ([]string, error) {
+	return runTyped(ctx, m, name, keys, args, (*goredis.Cmd).StringSlice)
+}
+
+// RunBool executes a script returning 0/1 or a boolean
+func (m *ScriptManager) RunBool(ctx context.Context, name string, keys []string, args ...interface{}) (bool, error) {
+	return runTyped(ctx, m, name, keys, args, (*goredis.Cmd).Bool)
+}
+
+func runTyped[T any](ctx context.Context, m *ScriptManager, name string, keys []string, args []interface{}, decode func(*goredis.Cmd) (T, error)) (T, error) {
+	var zero T
+	script, err := m.script(name)
+	if err != nil {
+		return zero, err
+	}
+	value, err := decode(script.Run(ctx, m.client, keys, args...))
+	if err != nil && err != goredis.Nil {
+		return zero, fmt.Errorf("failed to run script %s: %w", name, err)
+	}
+	return value, err
+}
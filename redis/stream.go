@@ -0,0 +1,245 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func (r *RedisService) XAdd(ctx context.Context, stream string, values map[string]interface{}, maxLen int64) (string, error) {
+	args := &goredis.XAddArgs{Stream: stream, Values: values}
+	if maxLen > 0 {
+		args.MaxLen = maxLen
+		args.Approx = true
+	}
+	return r.client.XAdd(ctx, args).Result()
+}
+
+func (r *RedisService) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	err := r.client.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+func (r *RedisService) XReadGroup(ctx context.Context, args *goredis.XReadGroupArgs) ([]goredis.XStream, error) {
+	return r.client.XReadGroup(ctx, args).Result()
+}
+
+func (r *RedisService) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	return r.client.XAck(ctx, stream, group, ids...).Result()
+}
+
+func (r *RedisService) XAutoClaim(ctx context.Context, args *goredis.XAutoClaimArgs) ([]goredis.XMessage, string, error) {
+	return r.client.XAutoClaim(ctx, args).Result()
+}
+
+func (r *RedisService) XPendingExt(ctx context.Context, args *goredis.XPendingExtArgs) ([]goredis.XPendingExt, error) {
+	return r.client.XPendingExt(ctx, args).Result()
+}
+
+// StreamHandler processes one stream message; returning an error leaves the
+// message pending so it is redelivered after ClaimMinIdle
+type StreamHandler func(ctx context.Context, msg goredis.XMessage) error
+
+// ConsumerGroupConfig configures a ConsumerGroup
+type ConsumerGroupConfig struct {
+	Stream   string
+	Group    string
+	Consumer string
+	// Count is the number of messages read per call
+	Count int64
+	// Block is how long a read waits for new messages
+	Block time.Duration
+	// ClaimMinIdle is how long a message stays pending before another
+	// consumer may reclaim it
+	ClaimMinIdle time.Duration
+	// ClaimInterval is how often pending messages are reclaimed
+	ClaimInterval time.Duration
+	// MaxDeliveries moves a message to the dead-letter stream once it has
+	// been delivered this many times
+	MaxDeliveries int64
+	// DeadLetterStream receives poison messages; Stream + ":dlq" when empty
+	DeadLetterStream string
+}
+
+// ConsumerGroup reads a stream through a consumer group and dispatches
+// messages to a handler with at-least-once delivery
+type ConsumerGroup struct {
+	redis   IRedisService
+	config  ConsumerGroupConfig
+	handler StreamHandler
+}
+
+// NewConsumerGroup creates a consumer group runner
+func NewConsumerGroup(svc IRedisService, config ConsumerGroupConfig, handler StreamHandler) (*ConsumerGroup, error) {
+	if config.Stream == "" || config.Group == "" || config.Consumer == "" {
+		return nil, errors.New("consumer group requires a stream, group and consumer name")
+	}
+	if config.Count <= 0 {
+		config.Count = 10
+	}
+	if config.Block <= 0 {
+		config.Block = 5 * time.Second
+	}
+	if config.ClaimMinIdle <= 0 {
+		config.ClaimMinIdle = time.Minute
+	}
+	if config.ClaimInterval <= 0 {
+		config.ClaimInterval = 30 * time.Second
+	}
+	if config.MaxDeliveries <= 0 {
+		config.MaxDeliveries = 5
+	}
+	if config.DeadLetterStream == "" {
+		config.DeadLetterStream = config.Stream + ":dlq"
+	}
+	return &ConsumerGroup{redis: svc, config: config, handler: handler}, nil
+}
+
+// Run creates the group if needed and consumes until ctx is cancelled
+func (g *ConsumerGroup) Run(ctx context.Context) error {
+	if err := g.redis.XGroupCreate(ctx, g.config.Stream, g.config.Group, "0"); err != nil {
+		return fmt.Errorf("failed to create consumer group %s on %s: %w", g.config.Group, g.config.Stream, err)
+	}
+
+	go g.reclaimLoop(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		streams, err := g.redis.XReadGroup(ctx, &goredis.XReadGroupArgs{
+			Group:    g.config.Group,
+			Consumer: g.config.Consumer,
+			Streams:  []string{g.config.Stream, ">"},
+			Count:    g.config.Count,
+			Block:    g.config.Block,
+		})
+		if errors.Is(err, goredis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Printf("⚠️ Failed to read stream %s: %v", g.config.Stream, err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				g.handle(ctx, msg)
+			}
+		}
+	}
+}
+
+func (g *ConsumerGroup) handle(ctx context.Context, msg goredis.XMessage) {
+	if err := g.handler(ctx, msg); err != nil {
+		log.Printf("⚠️ Stream %s message %s failed: %v", g.config.Stream, msg.ID, err)
+		return
+	}
+	g.ack(ctx, msg.ID)
+}
+
+func (g *ConsumerGroup) ack(ctx context.Context, id string) {
+	if _, err := g.redis.XAck(ctx, g.config.Stream, g.config.Group, id); err != nil {
+		log.Printf("⚠️ Failed to ack %s on %s: %v", id, g.config.Stream, err)
+	}
+}
+
+func (g *ConsumerGroup) reclaimLoop(ctx context.Context) {
+	ticker := time.NewTicker(g.config.ClaimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := g.reclaim(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("⚠️ Failed to reclaim pending messages on %s: %v", g.config.Stream, err)
+			}
+		}
+	}
+}
+
+// reclaim takes over messages left pending by failed or crashed consumers,
+// dead-lettering those delivered too often
+func (g *ConsumerGroup) reclaim(ctx context.Context) error {
+	start := "0-0"
+	for {
+		messages, next, err := g.redis.XAutoClaim(ctx, &goredis.XAutoClaimArgs{
+			Stream:   g.config.Stream,
+			Group:    g.config.Group,
+			Consumer: g.config.Consumer,
+			MinIdle:  g.config.ClaimMinIdle,
+			Start:    start,
+			Count:    g.config.Count,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			deliveries, err := g.deliveries(ctx, msg.ID)
+			if err != nil {
+				return err
+			}
+			if deliveries >= g.config.MaxDeliveries {
+				g.deadLetter(ctx, msg, deliveries)
+				continue
+			}
+			g.handle(ctx, msg)
+		}
+
+		if next == "0-0" || len(messages) == 0 {
+			return nil
+		}
+		start = next
+	}
+}
+
+// deliveries returns how many times a pending message has been delivered
+func (g *ConsumerGroup) deliveries(ctx context.Context, id string) (int64, error) {
+	pending, err := g.redis.XPendingExt(ctx, &goredis.XPendingExtArgs{
+		Stream: g.config.Stream,
+		Group:  g.config.Group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	})
+	if err != nil || len(pending) == 0 {
+		return 0, err
+	}
+	return pending[0].RetryCount, nil
+}
+
+// deadLetter copies msg to the dead-letter stream and acknowledges it
+func (g *ConsumerGroup) deadLetter(ctx context.Context, msg goredis.XMessage, deliveries int64) {
+	values := make(map[string]interface{}, len(msg.Values)+3)
+	for k, v := range msg.Values {
+		values[k] = v
+	}
+	values["dlq_source_id"] = msg.ID
+	values["dlq_group"] = g.config.Group
+	values["dlq_deliveries"] = deliveries
+
+	if _, err := g.redis.XAdd(ctx, g.config.DeadLetterStream, values, 0); err != nil {
+		log.Printf("⚠️ Failed to dead-letter %s from %s: %v", msg.ID, g.config.Stream, err)
+		return
+	}
+	log.Printf("☠️ Moved %s from %s to %s after %d deliveries", msg.ID, g.config.Stream, g.config.DeadLetterStream, deliveries)
+	g.ack(ctx, msg.ID)
+}
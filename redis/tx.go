@@ -0,0 +1,97 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// DefaultMaxTxRetries bounds optimistic transaction retries on WATCH conflicts
+const DefaultMaxTxRetries = 10
+
+// ErrTxConflict is returned when an optimistic update keeps losing the race
+var ErrTxConflict = errors.New("redis: optimistic transaction retries exhausted")
+
+// Aliases so callers can batch without importing go-redis
+type (
+	Pipeliner = goredis.Pipeliner
+	Tx        = goredis.Tx
+	Cmder     = goredis.Cmder
+)
+
+// Transaction runs fn under WATCH on keys and retries it from the start
+// whenever a watched key changes before EXEC. fn must read state through tx
+// and queue its writes with tx.TxPipelined.
+func Transaction(ctx context.Context, svc IRedisService, fn func(tx *Tx) error, keys ...string) error {
+	for attempt := 0; attempt < DefaultMaxTxRetries; attempt++ {
+		err := svc.Watch(ctx, fn, keys...)
+		if errors.Is(err, goredis.TxFailedErr) {
+			continue
+		}
+		return err
+	}
+	return ErrTxConflict
+}
+
+// TxPipelined queues commands in fn and executes them atomically in MULTI/EXEC
+func (r *RedisService) TxPipelined(ctx context.Context, fn func(goredis.Pipeliner) error) ([]goredis.Cmder, error) {
+	return r.client.TxPipelined(ctx, fn)
+}
+
+// Watch runs fn in an optimistic transaction guarded by WATCH on keys.
+// Commands queued through tx.TxPipelined fail with goredis.TxFailedErr if
+// any watched key changed in between.
+func (r *RedisService) Watch(ctx context.Context, fn func(*goredis.Tx) error, keys ...string) error {
+	return r.client.Watch(ctx, fn, keys...)
+}
+
+// UpdateFunc computes the new value from the current one; exists is false
+// when the key is missing
+type UpdateFunc[T any] func(current T, exists bool) (T, error)
+
+// UpdateWithRetry atomically reads the JSON value at key, applies fn and writes
+// the result back with ttl (0 keeps no expiry), retrying on concurrent writes
+func UpdateWithRetry[T any](ctx context.Context, svc IRedisService, key string, ttl time.Duration, fn UpdateFunc[T]) (T, error) {
+	var result T
+
+	err := Transaction(ctx, svc, func(tx *Tx) error {
+		var current T
+		exists := true
+
+		data, err := tx.Get(ctx, key).Bytes()
+		switch {
+		case errors.Is(err, goredis.Nil):
+			exists = false
+		case err != nil:
+			return err
+		default:
+			if err := json.Unmarshal(data, &current); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", key, err)
+			}
+		}
+
+		next, err := fn(current, exists)
+		if err != nil {
+			return err
+		}
+
+		encoded, err := json.Marshal(next)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe Pipeliner) error {
+			pipe.Set(ctx, key, encoded, ttl)
+			return nil
+		})
+		if err == nil {
+			result = next
+		}
+		return err
+	}, key)
+	return result, err
+}
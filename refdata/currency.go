@@ -0,0 +1,99 @@
+package refdata
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Currency codes
+const (
+	CurrencyETB = "ETB"
+	CurrencyUSD = "USD"
+)
+
+// Currency describes how amounts in a currency are displayed
+type Currency struct {
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	Symbol        string `json:"symbol"`
+	MinorUnits    int    `json:"minorUnits"`
+	SymbolPrefix  bool   `json:"symbolPrefix"`
+	MinorUnitName string `json:"minorUnitName,omitempty"`
+}
+
+var currencies = map[string]Currency{
+	CurrencyETB: {Code: CurrencyETB, Name: "Ethiopian Birr", Symbol: "Br", MinorUnits: 2, SymbolPrefix: true, MinorUnitName: "santim"},
+	CurrencyUSD: {Code: CurrencyUSD, Name: "US Dollar", Symbol: "$", MinorUnits: 2, SymbolPrefix: true, MinorUnitName: "cent"},
+}
+
+// GetCurrency returns currency metadata by ISO 4217 code
+func GetCurrency(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(code)]
+	return c, ok
+}
+
+// IsValidCurrency reports whether code is a supported currency
+func IsValidCurrency(code string) bool {
+	_, ok := GetCurrency(code)
+	return ok
+}
+
+// FormatAmount formats amount with the currency symbol and thousands separators,
+// e.g. "Br 1,250.50"
+func FormatAmount(amount float64, code string) string {
+	c, ok := GetCurrency(code)
+	if !ok {
+		return fmt.Sprintf("%.2f %s", amount, code)
+	}
+
+	formatted := FormatNumber(amount, c.MinorUnits)
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+
+	if c.SymbolPrefix {
+		return sign + c.Symbol + " " + formatted
+	}
+	return sign + formatted + " " + c.Symbol
+}
+
+// FormatNumber rounds value to decimals places and groups thousands, e.g. "1,250.50"
+func FormatNumber(value float64, decimals int) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	factor := math.Pow(10, float64(decimals))
+	value = math.Round(value*factor) / factor
+	formatted := strconv.FormatFloat(value, 'f', decimals, 64)
+
+	whole, frac, _ := strings.Cut(formatted, ".")
+	whole = groupThousands(whole)
+	if frac != "" {
+		whole += "." + frac
+	}
+	return sign + whole
+}
+
+func groupThousands(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
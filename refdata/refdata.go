@@ -0,0 +1,131 @@
+package refdata
+
+import (
+	"sort"
+	"strings"
+)
+
+// CountryCodeEthiopia is the ISO 3166-1 alpha-2 code of Ethiopia
+const CountryCodeEthiopia = "ET"
+
+// Region is a first-level administrative division
+type Region struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// City is a city served by the platform
+type City struct {
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	RegionCode string  `json:"regionCode"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Timezone   string  `json:"timezone"`
+	Currency   string  `json:"currency"`
+}
+
+// Ethiopian regions and chartered cities (ISO 3166-2:ET)
+var regions = []Region{
+	{Code: "AA", Name: "Addis Ababa"},
+	{Code: "AF", Name: "Afar"},
+	{Code: "AM", Name: "Amhara"},
+	{Code: "BE", Name: "Benishangul-Gumuz"},
+	{Code: "CE", Name: "Central Ethiopia"},
+	{Code: "DD", Name: "Dire Dawa"},
+	{Code: "GA", Name: "Gambela"},
+	{Code: "HA", Name: "Harari"},
+	{Code: "OR", Name: "Oromia"},
+	{Code: "SI", Name: "Sidama"},
+	{Code: "SO", Name: "Somali"},
+	{Code: "SE", Name: "South Ethiopia"},
+	{Code: "SW", Name: "South West Ethiopia Peoples"},
+	{Code: "TI", Name: "Tigray"},
+}
+
+// Cities served by the platform, keyed by city code
+var cities = []City{
+	{Code: "ADD", Name: "Addis Ababa", RegionCode: "AA", Latitude: 9.0054, Longitude: 38.7636},
+	{Code: "DIR", Name: "Dire Dawa", RegionCode: "DD", Latitude: 9.6009, Longitude: 41.8501},
+	{Code: "ADM", Name: "Adama", RegionCode: "OR", Latitude: 8.5400, Longitude: 39.2700},
+	{Code: "BJR", Name: "Bahir Dar", RegionCode: "AM", Latitude: 11.5936, Longitude: 37.3908},
+	{Code: "GDQ", Name: "Gondar", RegionCode: "AM", Latitude: 12.6030, Longitude: 37.4521},
+	{Code: "DSE", Name: "Dessie", RegionCode: "AM", Latitude: 11.1333, Longitude: 39.6333},
+	{Code: "MQX", Name: "Mekelle", RegionCode: "TI", Latitude: 13.4967, Longitude: 39.4753},
+	{Code: "AWA", Name: "Hawassa", RegionCode: "SI", Latitude: 7.0621, Longitude: 38.4764},
+	{Code: "JIM", Name: "Jimma", RegionCode: "SW", Latitude: 7.6667, Longitude: 36.8333},
+	{Code: "HAR", Name: "Harar", RegionCode: "HA", Latitude: 9.3126, Longitude: 42.1227},
+	{Code: "BSH", Name: "Bishoftu", RegionCode: "OR", Latitude: 8.7500, Longitude: 38.9833},
+	{Code: "AMH", Name: "Arba Minch", RegionCode: "SE", Latitude: 6.0333, Longitude: 37.5500},
+	{Code: "JIJ", Name: "Jijiga", RegionCode: "SO", Latitude: 9.3500, Longitude: 42.8000},
+	{Code: "SHA", Name: "Shashamane", RegionCode: "OR", Latitude: 7.2000, Longitude: 38.6000},
+}
+
+var (
+	regionsByCode = make(map[string]Region, len(regions))
+	citiesByCode  = make(map[string]City, len(cities))
+)
+
+func init() {
+	for _, r := range regions {
+		regionsByCode[r.Code] = r
+	}
+	for i := range cities {
+		cities[i].Timezone = TimezoneAddisAbaba
+		cities[i].Currency = CurrencyETB
+		citiesByCode[cities[i].Code] = cities[i]
+	}
+}
+
+// Regions returns all regions sorted by name
+func Regions() []Region {
+	out := append([]Region{}, regions...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// GetRegion returns a region by its code
+func GetRegion(code string) (Region, bool) {
+	r, ok := regionsByCode[strings.ToUpper(code)]
+	return r, ok
+}
+
+// Cities returns all served cities sorted by name
+func Cities() []City {
+	out := append([]City{}, cities...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// CitiesInRegion returns the served cities of a region
+func CitiesInRegion(regionCode string) []City {
+	var out []City
+	for _, c := range Cities() {
+		if c.RegionCode == strings.ToUpper(regionCode) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// GetCity returns a city by its code
+func GetCity(code string) (City, bool) {
+	c, ok := citiesByCode[strings.ToUpper(code)]
+	return c, ok
+}
+
+// IsValidCityCode reports whether code is a served city
+func IsValidCityCode(code string) bool {
+	_, ok := GetCity(code)
+	return ok
+}
+
+// CityCodes returns all served city codes, sorted
+func CityCodes() []string {
+	codes := make([]string, 0, len(cities))
+	for _, c := range cities {
+		codes = append(codes, c.Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,107 @@
+package refdata
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimezoneAddisAbaba is the IANA timezone of Ethiopia (EAT, UTC+3, no DST)
+const TimezoneAddisAbaba = "Africa/Addis_Ababa"
+
+// addisAbaba falls back to a fixed UTC+3 zone when tzdata is unavailable
+var addisAbaba = loadAddisAbaba()
+
+func loadAddisAbaba() *time.Location {
+	loc, err := time.LoadLocation(TimezoneAddisAbaba)
+	if err != nil {
+		return time.FixedZone("EAT", 3*60*60)
+	}
+	return loc
+}
+
+// AddisAbaba returns the Africa/Addis_Ababa location
+func AddisAbaba() *time.Location {
+	return addisAbaba
+}
+
+// InAddisAbaba converts t to Ethiopian local time
+func InAddisAbaba(t time.Time) time.Time {
+	return t.In(addisAbaba)
+}
+
+// EthiopianDate is a date in the Ethiopian (Ge'ez) calendar
+type EthiopianDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month"` // 1-13, where 13 is Pagume
+	Day   int `json:"day"`
+}
+
+// Ethiopian month names in English transliteration
+var ethiopianMonths = [13]string{
+	"Meskerem", "Tikimt", "Hidar", "Tahsas", "Tir", "Yekatit",
+	"Megabit", "Miyazia", "Ginbot", "Sene", "Hamle", "Nehase", "Pagume",
+}
+
+// Ethiopian month names in Amharic
+var ethiopianMonthsAmharic = [13]string{
+	"መስከረም", "ጥቅምት", "ኅዳር", "ታኅሣሥ", "ጥር", "የካቲት",
+	"መጋቢት", "ሚያዝያ", "ግንቦት", "ሰኔ", "ሐምሌ", "ነሐሴ", "ጳጉሜ",
+}
+
+// ethiopianEpoch is the Amete Mihret epoch offset used for Julian Day Number conversion
+const ethiopianEpoch = 1723856
+
+// ToEthiopian converts the calendar date of t (in its own location) to the Ethiopian calendar
+func ToEthiopian(t time.Time) EthiopianDate {
+	jdn := gregorianToJDN(t.Year(), int(t.Month()), t.Day())
+
+	r := (jdn - ethiopianEpoch) % 1461
+	n := r%365 + 365*(r/1460)
+
+	year := 4*((jdn-ethiopianEpoch)/1461) + r/365 - r/1460
+	month := n/30 + 1
+	day := n%30 + 1
+
+	return EthiopianDate{Year: year, Month: month, Day: day}
+}
+
+// MonthName returns the English transliteration of the month
+func (d EthiopianDate) MonthName() string {
+	if d.Month < 1 || d.Month > 13 {
+		return ""
+	}
+	return ethiopianMonths[d.Month-1]
+}
+
+// MonthNameAmharic returns the Amharic month name
+func (d EthiopianDate) MonthNameAmharic() string {
+	if d.Month < 1 || d.Month > 13 {
+		return ""
+	}
+	return ethiopianMonthsAmharic[d.Month-1]
+}
+
+// String formats the date as "Meskerem 1, 2017 E.C."
+func (d EthiopianDate) String() string {
+	return fmt.Sprintf("%s %d, %d E.C.", d.MonthName(), d.Day, d.Year)
+}
+
+// FormatAmharic formats the date as "መስከረም 1 ቀን 2017 ዓ.ም."
+func (d EthiopianDate) FormatAmharic() string {
+	return fmt.Sprintf("%s %d ቀን %d ዓ.ም.", d.MonthNameAmharic(), d.Day, d.Year)
+}
+
+// FormatReceiptDate formats t for receipts with both calendars in Addis Ababa time,
+// e.g. "2024-09-11 14:30 (Meskerem 1, 2017 E.C.)"
+func FormatReceiptDate(t time.Time) string {
+	local := InAddisAbaba(t)
+	return fmt.Sprintf("%s (%s)", local.Format("2006-01-02 15:04"), ToEthiopian(local).String())
+}
+
+// gregorianToJDN returns the Julian Day Number of a proleptic Gregorian date
+func gregorianToJDN(year, month, day int) int {
+	a := (14 - month) / 12
+	y := year + 4800 - a
+	m := month + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + y/4 - y/100 + y/400 - 32045
+}
@@ -29,6 +29,7 @@ type MetaInfo struct {
 	Version     string      `json:"version,omitempty"`
 	Environment string      `json:"environment,omitempty"`
 	Pagination  *Pagination `json:"pagination,omitempty"`
+	SystemFlags []string    `json:"systemFlags,omitempty"`
 }
 
 // Pagination represents pagination information
@@ -210,3 +211,14 @@ func RsConflict(msg string, errMsg interface{}) RsBase {
 		errMsg,
 	)
 }
+
+func RsPayloadTooLarge(msg string) RsBase {
+	if msg == "" {
+		msg = "Request payload too large"
+	}
+	return RsErr(
+		http.StatusRequestEntityTooLarge,
+		msg,
+		nil,
+	)
+}
@@ -0,0 +1,324 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/fareconfig"
+	"github.com/mihirk-khode/motocabz-common/location"
+)
+
+// MaxIntermediateStops is the number of stops allowed between pickup and dropoff
+const MaxIntermediateStops = 3
+
+// Waypoint types
+const (
+	WaypointPickup  = "pickup"
+	WaypointStop    = "stop"
+	WaypointDropoff = "dropoff"
+)
+
+// Waypoint statuses
+const (
+	WaypointPending = "pending"
+	WaypointReached = "reached"
+	WaypointSkipped = "skipped"
+)
+
+// Route errors
+var (
+	ErrTooManyStops     = fmt.Errorf("a trip may have at most %d intermediate stops", MaxIntermediateStops)
+	ErrRouteLocked      = errors.New("route cannot be changed in the current trip status")
+	ErrWaypointReached  = errors.New("waypoint has already been reached")
+	ErrWaypointNotFound = errors.New("waypoint not found")
+	ErrInvalidWaypoint  = errors.New("invalid waypoint")
+)
+
+// Waypoint is an ordered point of a trip
+type Waypoint struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	Location  location.Location `json:"location"`
+	Address   string            `json:"address,omitempty"`
+	Status    string            `json:"status"`
+	ReachedAt *time.Time        `json:"reachedAt,omitempty"`
+}
+
+// Leg is the segment between two consecutive waypoints
+type Leg struct {
+	FromID     string        `json:"fromId"`
+	ToID       string        `json:"toId"`
+	DistanceKm float64       `json:"distanceKm"`
+	Duration   time.Duration `json:"duration"`
+}
+
+// Route is the ordered list of waypoints of a trip with per-leg estimates
+type Route struct {
+	Waypoints       []Waypoint    `json:"waypoints"`
+	Legs            []Leg         `json:"legs"`
+	TotalDistanceKm float64       `json:"totalDistanceKm"`
+	TotalDuration   time.Duration `json:"totalDuration"`
+	// Version increments on every change so concurrent edits can be detected
+	Version int `json:"version"`
+}
+
+// LegEstimator estimates distance and duration between two points, e.g. a
+// routing API client
+type LegEstimator interface {
+	Estimate(from, to location.Location) (distanceKm float64, duration time.Duration, err error)
+}
+
+// StraightLineEstimator approximates road distance from the great-circle
+// distance; used when no routing provider is configured
+type StraightLineEstimator struct {
+	// RoadFactor scales the straight-line distance to road distance
+	RoadFactor float64
+	// SpeedKmh is the assumed average speed
+	SpeedKmh float64
+}
+
+// Estimate implements LegEstimator
+func (e StraightLineEstimator) Estimate(from, to location.Location) (float64, time.Duration, error) {
+	factor, speed := e.RoadFactor, e.SpeedKmh
+	if factor <= 0 {
+		factor = 1.3
+	}
+	if speed <= 0 {
+		speed = 25
+	}
+	distance := from.DistanceKm(to) * factor
+	return distance, time.Duration(distance / speed * float64(time.Hour)), nil
+}
+
+// New builds a route from pickup, intermediate stops and dropoff
+func New(pickup Waypoint, stops []Waypoint, dropoff Waypoint, estimator LegEstimator) (*Route, error) {
+	pickup.Type, dropoff.Type = WaypointPickup, WaypointDropoff
+	waypoints := make([]Waypoint, 0, len(stops)+2)
+	waypoints = append(waypoints, pickup)
+	for _, s := range stops {
+		s.Type = WaypointStop
+		waypoints = append(waypoints, s)
+	}
+	waypoints = append(waypoints, dropoff)
+	for i := range waypoints {
+		if waypoints[i].Status == "" {
+			waypoints[i].Status = WaypointPending
+		}
+	}
+
+	r := &Route{Waypoints: waypoints}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if err := r.recompute(estimator); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate checks waypoint order, count and coordinates
+func (r *Route) Validate() error {
+	n := len(r.Waypoints)
+	if n < 2 {
+		return fmt.Errorf("%w: a route needs a pickup and a dropoff", ErrInvalidWaypoint)
+	}
+	if r.Waypoints[0].Type != WaypointPickup || r.Waypoints[n-1].Type != WaypointDropoff {
+		return fmt.Errorf("%w: route must start with pickup and end with dropoff", ErrInvalidWaypoint)
+	}
+	if n-2 > MaxIntermediateStops {
+		return ErrTooManyStops
+	}
+
+	seen := make(map[string]bool, n)
+	for i, wp := range r.Waypoints {
+		if wp.ID == "" || seen[wp.ID] {
+			return fmt.Errorf("%w: waypoint %d needs a unique id", ErrInvalidWaypoint, i)
+		}
+		seen[wp.ID] = true
+		if !wp.Location.IsValid() {
+			return fmt.Errorf("%w: waypoint %s has invalid coordinates", ErrInvalidWaypoint, wp.ID)
+		}
+		if i > 0 && i < n-1 && wp.Type != WaypointStop {
+			return fmt.Errorf("%w: waypoint %s must be a stop", ErrInvalidWaypoint, wp.ID)
+		}
+	}
+	return nil
+}
+
+// Stops returns the number of intermediate stops
+func (r *Route) Stops() int {
+	return len(r.Waypoints) - 2
+}
+
+// index returns the position of a waypoint
+func (r *Route) index(id string) int {
+	for i, wp := range r.Waypoints {
+		if wp.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastReached returns the index of the furthest reached or skipped waypoint, or -1
+func (r *Route) lastReached() int {
+	last := -1
+	for i, wp := range r.Waypoints {
+		if wp.Status != WaypointPending {
+			last = i
+		}
+	}
+	return last
+}
+
+// editable reports whether the route may change in tripStatus
+func editable(tripStatus string) bool {
+	switch tripStatus {
+	case common.TripStatusPending, common.TripStatusAccepted, common.TripStatusInProgress:
+		return true
+	default:
+		return false
+	}
+}
+
+// AddStop inserts a stop before the waypoint at position (1 = right after
+// pickup). While IN_PROGRESS, stops can only be added after the last reached waypoint.
+func (r *Route) AddStop(tripStatus string, position int, stop Waypoint, estimator LegEstimator) error {
+	if !editable(tripStatus) {
+		return ErrRouteLocked
+	}
+	if r.Stops() >= MaxIntermediateStops {
+		return ErrTooManyStops
+	}
+	if position < 1 || position > len(r.Waypoints)-1 {
+		return fmt.Errorf("%w: position %d out of range", ErrInvalidWaypoint, position)
+	}
+	if position <= r.lastReached() {
+		return ErrWaypointReached
+	}
+
+	stop.Type, stop.Status, stop.ReachedAt = WaypointStop, WaypointPending, nil
+	waypoints := make([]Waypoint, 0, len(r.Waypoints)+1)
+	waypoints = append(waypoints, r.Waypoints[:position]...)
+	waypoints = append(waypoints, stop)
+	waypoints = append(waypoints, r.Waypoints[position:]...)
+
+	return r.apply(waypoints, estimator)
+}
+
+// RemoveStop removes an intermediate stop that has not been reached yet
+func (r *Route) RemoveStop(tripStatus, id string, estimator LegEstimator) error {
+	if !editable(tripStatus) {
+		return ErrRouteLocked
+	}
+	i := r.index(id)
+	if i < 0 {
+		return ErrWaypointNotFound
+	}
+	if r.Waypoints[i].Type != WaypointStop {
+		return fmt.Errorf("%w: only intermediate stops can be removed", ErrInvalidWaypoint)
+	}
+	if r.Waypoints[i].Status != WaypointPending {
+		return ErrWaypointReached
+	}
+
+	waypoints := make([]Waypoint, 0, len(r.Waypoints)-1)
+	waypoints = append(waypoints, r.Waypoints[:i]...)
+	waypoints = append(waypoints, r.Waypoints[i+1:]...)
+	return r.apply(waypoints, estimator)
+}
+
+// ChangeDropoff moves the dropoff while it has not been reached
+func (r *Route) ChangeDropoff(tripStatus string, loc location.Location, address string, estimator LegEstimator) error {
+	if !editable(tripStatus) {
+		return ErrRouteLocked
+	}
+	waypoints := append([]Waypoint{}, r.Waypoints...)
+	last := &waypoints[len(waypoints)-1]
+	if last.Status != WaypointPending {
+		return ErrWaypointReached
+	}
+	last.Location, last.Address = loc, address
+	return r.apply(waypoints, estimator)
+}
+
+// MarkReached records arrival at a waypoint
+func (r *Route) MarkReached(id string, at time.Time) error {
+	i := r.index(id)
+	if i < 0 {
+		return ErrWaypointNotFound
+	}
+	r.Waypoints[i].Status = WaypointReached
+	r.Waypoints[i].ReachedAt = &at
+	r.Version++
+	return nil
+}
+
+// apply validates and installs new waypoints, recomputing legs
+func (r *Route) apply(waypoints []Waypoint, estimator LegEstimator) error {
+	next := &Route{Waypoints: waypoints, Version: r.Version}
+	if err := next.Validate(); err != nil {
+		return err
+	}
+	if err := next.recompute(estimator); err != nil {
+		return err
+	}
+	next.Version++
+	*r = *next
+	return nil
+}
+
+func (r *Route) recompute(estimator LegEstimator) error {
+	if estimator == nil {
+		estimator = StraightLineEstimator{}
+	}
+
+	r.Legs = make([]Leg, 0, len(r.Waypoints)-1)
+	r.TotalDistanceKm, r.TotalDuration = 0, 0
+	for i := 1; i < len(r.Waypoints); i++ {
+		from, to := r.Waypoints[i-1], r.Waypoints[i]
+		distance, duration, err := estimator.Estimate(from.Location, to.Location)
+		if err != nil {
+			return fmt.Errorf("failed to estimate leg %s → %s: %w", from.ID, to.ID, err)
+		}
+		r.Legs = append(r.Legs, Leg{FromID: from.ID, ToID: to.ID, DistanceKm: distance, Duration: duration})
+		r.TotalDistanceKm += distance
+		r.TotalDuration += duration
+	}
+	return nil
+}
+
+// Fare prices the route with a class fare, including per-stop charges
+func (r *Route) Fare(fare fareconfig.ClassFare) float64 {
+	return fare.CalculateWithStops(r.TotalDistanceKm, r.TotalDuration.Minutes(), r.Stops())
+}
+
+// FareAdjustment is the fare difference caused by a route change
+type FareAdjustment struct {
+	PreviousFare float64 `json:"previousFare"`
+	NewFare      float64 `json:"newFare"`
+	Difference   float64 `json:"difference"`
+	RouteVersion int     `json:"routeVersion"`
+}
+
+// Reprice compares the fare of the route before and after a change
+func Reprice(before, after *Route, fare fareconfig.ClassFare) FareAdjustment {
+	previous, next := before.Fare(fare), after.Fare(fare)
+	return FareAdjustment{
+		PreviousFare: previous,
+		NewFare:      next,
+		Difference:   math.Round((next-previous)*100) / 100,
+		RouteVersion: after.Version,
+	}
+}
+
+// Clone returns a deep copy, useful for computing a FareAdjustment around an edit
+func (r *Route) Clone() *Route {
+	c := *r
+	c.Waypoints = append([]Waypoint{}, r.Waypoints...)
+	c.Legs = append([]Leg{}, r.Legs...)
+	return &c
+}
@@ -0,0 +1,152 @@
+package systemstate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mihirk-khode/motocabz-common/middleware"
+)
+
+// HeaderSystemFlags carries the raised flags on every HTTP response
+const HeaderSystemFlags = "X-System-Flags"
+
+// Middleware adds the raised flags to the X-System-Flags header and to
+// meta.systemFlags of JSON RsBase responses
+func (m *Manager) Middleware() middleware.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			names := m.Names()
+			if len(names) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set(HeaderSystemFlags, strings.Join(names, ","))
+			fw := &flagWriter{ResponseWriter: w, flags: names}
+			next.ServeHTTP(fw, r)
+			fw.finish()
+		})
+	}
+}
+
+// flagWriter buffers JSON bodies so meta can be rewritten; other content
+// types (SSE, files) pass straight through
+type flagWriter struct {
+	http.ResponseWriter
+	flags     []string
+	status    int
+	decided   bool
+	buffering bool
+	hijacked  bool
+	buf       bytes.Buffer
+}
+
+func (w *flagWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.buffering = strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	if !w.buffering && w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
+
+func (w *flagWriter) WriteHeader(code int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = code
+	w.decide()
+}
+
+func (w *flagWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.decide()
+	if w.buffering {
+		return w.buf.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends buffered output for streaming handlers
+func (w *flagWriter) Flush() {
+	if w.buffering {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hands the connection to the handler (e.g. a websocket upgrade);
+// nothing is buffered or written by the wrapper afterwards
+func (w *flagWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer %T does not support hijacking", w.ResponseWriter)
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		w.hijacked = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *flagWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *flagWriter) finish() {
+	if !w.buffering || w.hijacked {
+		return
+	}
+
+	body := w.buf.Bytes()
+	if rewritten, ok := injectFlags(body, w.flags); ok {
+		body = rewritten
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(body)
+}
+
+// injectFlags sets meta.systemFlags on an RsBase-shaped JSON object
+func injectFlags(body []byte, flags []string) ([]byte, bool) {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, false
+	}
+	if _, ok := envelope["apiVersion"]; !ok {
+		return nil, false
+	}
+
+	meta := map[string]interface{}{}
+	if raw, ok := envelope["meta"]; ok {
+		if err := json.Unmarshal(raw, &meta); err != nil || meta == nil {
+			meta = map[string]interface{}{}
+		}
+	}
+	meta["systemFlags"] = flags
+
+	encodedMeta, err := json.Marshal(meta)
+	if err != nil {
+		return nil, false
+	}
+	envelope["meta"] = encodedMeta
+
+	out, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, false
+	}
+	return append(out, '\n'), true
+}
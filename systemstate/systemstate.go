@@ -0,0 +1,236 @@
+package systemstate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/websocket"
+	"github.com/redis/go-redis/v9"
+)
+
+// Well-known degradation flags
+const (
+	FlagPaymentsDegraded = "payments_degraded"
+	FlagSurgeDisabled    = "surge_disabled"
+	FlagBiddingDisabled  = "bidding_disabled"
+	FlagMatchingDegraded = "matching_degraded"
+	FlagChatDisabled     = "chat_disabled"
+	FlagMaintenance      = "maintenance"
+)
+
+// Flag is an active degradation flag
+type Flag struct {
+	Name    string    `json:"name"`
+	Message string    `json:"message,omitempty"`
+	SetBy   string    `json:"setBy,omitempty"`
+	SetAt   time.Time `json:"setAt"`
+}
+
+// Config configures the state manager
+type Config struct {
+	KeyPrefix string
+	// RefreshInterval reloads flags in case a change notification was missed
+	RefreshInterval time.Duration
+}
+
+// Manager stores degradation flags in Redis and keeps a local snapshot
+type Manager struct {
+	client redis.UniversalClient
+	config Config
+	ws     websocket.IWebSocketManager
+
+	mu        sync.RWMutex
+	flags     map[string]Flag
+	listeners []func([]Flag)
+}
+
+// NewManager creates a new state manager; ws may be nil to skip client
+// broadcasts. Every instance reloads on a change and notifies its own
+// connections, so a distributed ws is used for local delivery only.
+func NewManager(client redis.UniversalClient, ws websocket.IWebSocketManager, config Config) *Manager {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "systemstate"
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = 30 * time.Second
+	}
+	return &Manager{
+		client: client,
+		config: config,
+		ws:     websocket.LocalOnly(ws),
+		flags:  make(map[string]Flag),
+	}
+}
+
+func (m *Manager) flagsKey() string {
+	return m.config.KeyPrefix + ":flags"
+}
+
+func (m *Manager) channel() string {
+	return m.config.KeyPrefix + ":changes"
+}
+
+// Set raises a flag and notifies all instances
+func (m *Manager) Set(ctx context.Context, name, message, setBy string) error {
+	data, err := json.Marshal(Flag{Name: name, Message: message, SetBy: setBy, SetAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	if err := m.client.HSet(ctx, m.flagsKey(), name, data).Err(); err != nil {
+		return fmt.Errorf("failed to set flag %s: %w", name, err)
+	}
+	m.client.Publish(ctx, m.channel(), name)
+	return m.Reload(ctx)
+}
+
+// Clear lowers a flag and notifies all instances
+func (m *Manager) Clear(ctx context.Context, name string) error {
+	if err := m.client.HDel(ctx, m.flagsKey(), name).Err(); err != nil {
+		return fmt.Errorf("failed to clear flag %s: %w", name, err)
+	}
+	m.client.Publish(ctx, m.channel(), name)
+	return m.Reload(ctx)
+}
+
+// IsActive reports whether a flag is currently raised
+func (m *Manager) IsActive(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.flags[name]
+	return ok
+}
+
+// Active returns the raised flags sorted by name
+func (m *Manager) Active() []Flag {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	flags := make([]Flag, 0, len(m.flags))
+	for _, f := range m.flags {
+		flags = append(flags, f)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// Names returns the names of the raised flags
+func (m *Manager) Names() []string {
+	flags := m.Active()
+	names := make([]string, len(flags))
+	for i, f := range flags {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// OnChange registers a callback invoked with the new flag set after each change
+func (m *Manager) OnChange(fn func([]Flag)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, fn)
+}
+
+// Reload reads the flags from Redis and notifies listeners and clients on change
+func (m *Manager) Reload(ctx context.Context) error {
+	raw, err := m.client.HGetAll(ctx, m.flagsKey()).Result()
+	if err != nil {
+		return fmt.Errorf("failed to load system flags: %w", err)
+	}
+
+	flags := make(map[string]Flag, len(raw))
+	for name, data := range raw {
+		var f Flag
+		if err := json.Unmarshal([]byte(data), &f); err != nil {
+			f = Flag{Name: name}
+		}
+		flags[name] = f
+	}
+
+	m.mu.Lock()
+	changed := !sameNames(m.flags, flags)
+	m.flags = flags
+	listeners := append([]func([]Flag){}, m.listeners...)
+	m.mu.Unlock()
+
+	if !changed {
+		return nil
+	}
+
+	active := m.Active()
+	log.Printf("System flags changed: [%s]", strings.Join(m.Names(), ", "))
+	for _, fn := range listeners {
+		fn(active)
+	}
+	m.broadcast(active)
+	return nil
+}
+
+func sameNames(a, b map[string]Flag) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, fa := range a {
+		fb, ok := b[name]
+		if !ok || fa.Message != fb.Message {
+			return false
+		}
+	}
+	return true
+}
+
+// broadcast sends a system_message with the current flags to all connected apps
+func (m *Manager) broadcast(flags []Flag) {
+	if m.ws == nil {
+		return
+	}
+
+	names := make([]string, len(flags))
+	messages := make(map[string]string, len(flags))
+	for i, f := range flags {
+		names[i] = f.Name
+		if f.Message != "" {
+			messages[f.Name] = f.Message
+		}
+	}
+
+	msg := websocket.NewPayloadMessage(websocket.SystemMessagePayload{
+		SystemFlags: names,
+		Messages:    messages,
+	})
+	for _, userType := range []string{websocket.UserTypeDriver, websocket.UserTypeRider, websocket.UserTypeAdmin} {
+		m.ws.BroadcastToType(userType, msg)
+	}
+}
+
+// Start loads the flags and follows changes until ctx is cancelled
+func (m *Manager) Start(ctx context.Context) error {
+	if err := m.Reload(ctx); err != nil {
+		return err
+	}
+
+	pubsub := m.client.Subscribe(ctx, m.channel())
+	go func() {
+		defer pubsub.Close()
+		ticker := time.NewTicker(m.config.RefreshInterval)
+		defer ticker.Stop()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-messages:
+			case <-ticker.C:
+			}
+			if err := m.Reload(ctx); err != nil {
+				log.Printf("Failed to reload system flags: %v", err)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,135 @@
+package testintegration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dapr/go-sdk/client"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/events"
+	"github.com/ory/dockertest/v3"
+)
+
+// DaprImageTag is the daprd image used by StartDapr
+const DaprImageTag = "1.13.0"
+
+// inMemoryPubsub is the pub/sub component loaded into the test sidecar
+const inMemoryPubsub = `apiVersion: dapr.io/v1alpha1
+kind: Component
+metadata:
+  name: %s
+spec:
+  type: pubsub.in-memory
+  version: v1
+`
+
+// DaprContainer is a standalone Dapr sidecar with an in-memory pub/sub
+type DaprContainer struct {
+	GRPCAddr string
+	HTTPAddr string
+	client   client.Client
+}
+
+// StartDapr starts a daprd sidecar for the duration of the test
+func StartDapr(t TB, appID string) *DaprContainer {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "dapr-components-")
+	if err != nil {
+		t.Fatalf("failed to create components dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	component := fmt.Sprintf(inMemoryPubsub, common.DaprPubsubName)
+	if err := os.WriteFile(filepath.Join(dir, "pubsub.yaml"), []byte(component), 0o644); err != nil {
+		t.Fatalf("failed to write pubsub component: %v", err)
+	}
+
+	resource := run(t, &dockertest.RunOptions{
+		Repository:   "daprio/daprd",
+		Tag:          DaprImageTag,
+		Mounts:       []string{dir + ":/components"},
+		ExposedPorts: []string{"50001/tcp", "3500/tcp"},
+		Cmd: []string{
+			"./daprd",
+			"--app-id", appID,
+			"--dapr-grpc-port", "50001",
+			"--dapr-http-port", "3500",
+			"--resources-path", "/components",
+		},
+	})
+
+	dapr := &DaprContainer{
+		GRPCAddr: resource.GetHostPort("50001/tcp"),
+		HTTPAddr: resource.GetHostPort("3500/tcp"),
+	}
+
+	retry(t, "dapr sidecar", func() error {
+		resp, err := http.Get("http://" + dapr.HTTPAddr + "/v1.0/healthz")
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("healthz returned %d", resp.StatusCode)
+		}
+		return nil
+	})
+
+	dapr.client, err = client.NewClientWithAddressContext(context.Background(), dapr.GRPCAddr)
+	if err != nil {
+		t.Fatalf("failed to connect to dapr sidecar: %v", err)
+	}
+	t.Cleanup(dapr.client.Close)
+	return dapr
+}
+
+// Client returns a Dapr client connected to the sidecar
+func (d *DaprContainer) Client() client.Client {
+	return d.client
+}
+
+// Publisher returns an events publisher on the sidecar's in-memory pub/sub
+func (d *DaprContainer) Publisher(source string) *events.DaprPublisher {
+	return events.NewDaprPublisher(d.client, common.DaprPubsubName, source)
+}
+
+// RecordingPublisher is an in-process events.Publisher for tests that only
+// need to assert on what was published
+type RecordingPublisher struct {
+	mu        sync.Mutex
+	published map[string][]events.BaseEvent
+}
+
+// NewRecordingPublisher creates an empty recording publisher
+func NewRecordingPublisher() *RecordingPublisher {
+	return &RecordingPublisher{published: make(map[string][]events.BaseEvent)}
+}
+
+// Publish records event under topic
+func (p *RecordingPublisher) Publish(_ context.Context, topic string, event events.BaseEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published[topic] = append(p.published[topic], event)
+	return nil
+}
+
+// Events returns the events published to topic
+func (p *RecordingPublisher) Events(topic string) []events.BaseEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]events.BaseEvent{}, p.published[topic]...)
+}
+
+// Reset forgets all recorded events
+func (p *RecordingPublisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published = make(map[string][]events.BaseEvent)
+}
+
+var _ events.Publisher = (*RecordingPublisher)(nil)
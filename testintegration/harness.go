@@ -0,0 +1,87 @@
+package testintegration
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// EnvSkip disables container-backed tests when set to "1", e.g. on CI
+// runners without Docker
+const EnvSkip = "SKIP_INTEGRATION"
+
+// containerTTL makes Docker reap containers left behind by killed test runs
+const containerTTL = 10 * time.Minute
+
+// TB is the subset of testing.TB used by the harness, so this package does
+// not pull the testing package into production binaries
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+	Skipf(format string, args ...interface{})
+	Cleanup(func())
+	Name() string
+}
+
+var (
+	poolOnce sync.Once
+	pool     *dockertest.Pool
+	poolErr  error
+)
+
+// Pool returns the shared Docker pool, skipping the test when Docker is unavailable
+func Pool(t TB) *dockertest.Pool {
+	t.Helper()
+
+	if os.Getenv(EnvSkip) == "1" {
+		t.Skipf("integration tests disabled by %s", EnvSkip)
+	}
+
+	poolOnce.Do(func() {
+		pool, poolErr = dockertest.NewPool("")
+		if poolErr == nil {
+			poolErr = pool.Client.Ping()
+		}
+		if pool != nil {
+			pool.MaxWait = 60 * time.Second
+		}
+	})
+	if poolErr != nil {
+		t.Skipf("docker unavailable: %v", poolErr)
+	}
+	return pool
+}
+
+// run starts a container that is purged when the test finishes
+func run(t TB, opts *dockertest.RunOptions) *dockertest.Resource {
+	t.Helper()
+
+	p := Pool(t)
+	resource, err := p.RunWithOptions(opts, func(hc *docker.HostConfig) {
+		hc.AutoRemove = true
+		hc.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		t.Fatalf("failed to start %s:%s: %v", opts.Repository, opts.Tag, err)
+	}
+	resource.Expire(uint(containerTTL.Seconds()))
+
+	t.Cleanup(func() {
+		if err := p.Purge(resource); err != nil {
+			log.Printf("⚠️ testintegration: failed to purge %s: %v", opts.Repository, err)
+		}
+	})
+	return resource
+}
+
+// retry waits for op to succeed within the pool's MaxWait
+func retry(t TB, what string, op func() error) {
+	t.Helper()
+	if err := Pool(t).Retry(op); err != nil {
+		t.Fatalf("%s did not become ready: %v", what, err)
+	}
+}
@@ -0,0 +1,78 @@
+package testintegration
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+)
+
+// PostgresOptions configures StartPostgres
+type PostgresOptions struct {
+	Tag      string
+	Database string
+	User     string
+	Password string
+}
+
+// PostgresContainer is a disposable Postgres server
+type PostgresContainer struct {
+	Host    string
+	Port    string
+	options PostgresOptions
+}
+
+// StartPostgres starts a Postgres container for the duration of the test
+func StartPostgres(t TB, options PostgresOptions) *PostgresContainer {
+	t.Helper()
+
+	if options.Tag == "" {
+		options.Tag = "16-alpine"
+	}
+	if options.Database == "" {
+		options.Database = "motocabz_test"
+	}
+	if options.User == "" {
+		options.User = "postgres"
+	}
+	if options.Password == "" {
+		options.Password = "postgres"
+	}
+
+	resource := run(t, &dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        options.Tag,
+		Env: []string{
+			"POSTGRES_DB=" + options.Database,
+			"POSTGRES_USER=" + options.User,
+			"POSTGRES_PASSWORD=" + options.Password,
+		},
+	})
+
+	host, port, _ := net.SplitHostPort(resource.GetHostPort("5432/tcp"))
+	pg := &PostgresContainer{Host: host, Port: port, options: options}
+
+	// Common has no SQL driver dependency; wait for pg_isready inside the container
+	retry(t, "postgres", func() error {
+		code, err := resource.Exec([]string{"pg_isready", "-U", options.User, "-d", options.Database}, dockertest.ExecOptions{})
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return fmt.Errorf("pg_isready exited with %d", code)
+		}
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), time.Second)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+	return pg
+}
+
+// DSN returns a connection string for lib/pq or pgx
+func (p *PostgresContainer) DSN() string {
+	return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable",
+		p.options.User, p.options.Password, net.JoinHostPort(p.Host, p.Port), p.options.Database)
+}
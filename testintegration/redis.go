@@ -0,0 +1,78 @@
+package testintegration
+
+import (
+	"context"
+	"net"
+
+	"github.com/mihirk-khode/motocabz-common/redis"
+	"github.com/ory/dockertest/v3"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// RedisImageTag is the Redis image used by StartRedis
+const RedisImageTag = "7-alpine"
+
+// RedisContainer is a disposable Redis server
+type RedisContainer struct {
+	Host   string
+	Port   string
+	client goredis.UniversalClient
+}
+
+// StartRedis starts a Redis container for the duration of the test
+func StartRedis(t TB) *RedisContainer {
+	t.Helper()
+
+	resource := run(t, &dockertest.RunOptions{
+		Repository: "redis",
+		Tag:        RedisImageTag,
+	})
+
+	hostPort := resource.GetHostPort("6379/tcp")
+	host, port, _ := net.SplitHostPort(hostPort)
+	client := goredis.NewClient(&goredis.Options{Addr: hostPort})
+	t.Cleanup(func() { client.Close() })
+
+	retry(t, "redis", func() error {
+		return client.Ping(context.Background()).Err()
+	})
+
+	return &RedisContainer{Host: host, Port: port, client: client}
+}
+
+// Addr returns host:port of the server
+func (r *RedisContainer) Addr() string {
+	return net.JoinHostPort(r.Host, r.Port)
+}
+
+// Config returns a RedisConfig pointing at the container
+func (r *RedisContainer) Config() redis.RedisConfig {
+	cfg := redis.DefaultRedisConfig()
+	cfg.Host = r.Host
+	cfg.Port = r.Port
+	return cfg
+}
+
+// Client returns a raw client connected to the container
+func (r *RedisContainer) Client() goredis.UniversalClient {
+	return r.client
+}
+
+// Service returns a RedisService connected to the container
+func (r *RedisContainer) Service(t TB) *redis.RedisService {
+	t.Helper()
+	svc, err := redis.NewRedisService(r.Config())
+	if err != nil {
+		t.Fatalf("failed to create redis service: %v", err)
+	}
+	t.Cleanup(func() { svc.Close() })
+	return svc
+}
+
+// Flush removes all keys, for isolating subtests that share a container
+func (r *RedisContainer) Flush(t TB) {
+	t.Helper()
+	if err := r.client.FlushAll(context.Background()).Err(); err != nil {
+		t.Fatalf("failed to flush redis: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/events"
+	"github.com/redis/go-redis/v9"
+)
+
+// StoreConfig configures the Redis-backed timeline store
+type StoreConfig struct {
+	KeyPrefix string
+	// Retention is how long events of a trip are kept after the last update
+	Retention time.Duration
+	// CacheTTL is how long a rendered timeline of an active trip is cached;
+	// terminal timelines are cached for the whole retention
+	CacheTTL time.Duration
+}
+
+// Store records trip events and renders cached timelines
+type Store struct {
+	client redis.UniversalClient
+	config StoreConfig
+}
+
+// NewStore creates a new timeline store
+func NewStore(client redis.UniversalClient, config StoreConfig) *Store {
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "timeline"
+	}
+	if config.Retention <= 0 {
+		config.Retention = 30 * 24 * time.Hour
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 30 * time.Second
+	}
+	return &Store{
+		client: client,
+		config: config,
+	}
+}
+
+// eventsKey and renderedKey hash-tag the trip so Consume's transaction stays
+// in one Redis Cluster slot
+func (s *Store) eventsKey(tripID string) string {
+	return fmt.Sprintf("{%s:%s}:events", s.config.KeyPrefix, tripID)
+}
+
+func (s *Store) renderedKey(tripID string) string {
+	return fmt.Sprintf("{%s:%s}:rendered", s.config.KeyPrefix, tripID)
+}
+
+// Consume records a trip event, ignoring events that do not affect the timeline.
+// Redelivered events are deduplicated by ID.
+func (s *Store) Consume(ctx context.Context, event events.BaseEvent) error {
+	if _, ok := StageOf(event); !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+	}
+
+	tripID := event.AggregateID
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.eventsKey(tripID), event.ID, data)
+		pipe.Expire(ctx, s.eventsKey(tripID), s.config.Retention)
+		pipe.Del(ctx, s.renderedKey(tripID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record timeline event for trip %s: %w", tripID, err)
+	}
+	return nil
+}
+
+// Get returns the rendered timeline of a trip, using the cache when fresh
+func (s *Store) Get(ctx context.Context, tripID string) (Timeline, error) {
+	cached, err := s.client.Get(ctx, s.renderedKey(tripID)).Bytes()
+	if err == nil {
+		var timeline Timeline
+		if err := json.Unmarshal(cached, &timeline); err == nil {
+			return timeline, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		return Timeline{}, fmt.Errorf("failed to read cached timeline: %w", err)
+	}
+
+	// The events key is watched so a Consume landing between the read and the
+	// cache write aborts the write instead of caching a timeline without it
+	var timeline Timeline
+	err = s.client.Watch(ctx, func(tx *redis.Tx) error {
+		raw, err := tx.HGetAll(ctx, s.eventsKey(tripID)).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read timeline events: %w", err)
+		}
+
+		evts := make([]events.BaseEvent, 0, len(raw))
+		for _, data := range raw {
+			var event events.BaseEvent
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				continue
+			}
+			evts = append(evts, event)
+		}
+		timeline = Build(tripID, evts)
+
+		ttl := s.config.CacheTTL
+		if timeline.Terminal {
+			ttl = s.config.Retention
+		}
+		data, err := json.Marshal(timeline)
+		if err != nil {
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, s.renderedKey(tripID), data, ttl)
+			return nil
+		})
+		return err
+	}, s.eventsKey(tripID))
+	if errors.Is(err, redis.TxFailedErr) {
+		// A newer event arrived; the next Get renders and caches it
+		return timeline, nil
+	}
+	if err != nil {
+		return Timeline{}, err
+	}
+	return timeline, nil
+}
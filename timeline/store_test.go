@@ -0,0 +1,76 @@
+package timeline
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/events"
+	"github.com/redis/go-redis/v9"
+)
+
+// afterHGetAll runs fn once, right after the first HGETALL returns
+type afterHGetAll struct {
+	once sync.Once
+	fn   func()
+}
+
+func (h *afterHGetAll) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *afterHGetAll) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if cmd.Name() == "hgetall" {
+			h.once.Do(h.fn)
+		}
+		return err
+	}
+}
+
+func (h *afterHGetAll) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func newEvent(t *testing.T, eventType string) events.BaseEvent {
+	t.Helper()
+	event, err := events.NewEvent(eventType, common.AggregateTypeTrip, "trip-1", map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestGetDoesNotCacheTimelineOlderThanConsume(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	reader := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	writer := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer reader.Close()
+	defer writer.Close()
+
+	readStore := NewStore(reader, StoreConfig{})
+	writeStore := NewStore(writer, StoreConfig{})
+	if err := writeStore.Consume(ctx, newEvent(t, common.EventTypeTripCreated)); err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+
+	// the driver is assigned while Get is rendering the old events
+	reader.AddHook(&afterHGetAll{fn: func() {
+		if err := writeStore.Consume(ctx, newEvent(t, common.EventTypeDriverAssigned)); err != nil {
+			t.Errorf("Consume: %v", err)
+		}
+	}})
+	if _, err := readStore.Get(ctx, "trip-1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	timeline, err := writeStore.Get(ctx, "trip-1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(timeline.Entries) != 2 {
+		t.Errorf("timeline has %d entries, want the assignment too: %+v", len(timeline.Entries), timeline.Entries)
+	}
+}
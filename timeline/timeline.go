@@ -0,0 +1,194 @@
+package timeline
+
+import (
+	"sort"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/events"
+)
+
+// Stages of a trip, in lifecycle order
+const (
+	StageRequested = "requested"
+	StageBidding   = "bidding"
+	StageAssigned  = "assigned"
+	StageArrived   = "arrived"
+	StageStarted   = "started"
+	StageCompleted = "completed"
+	StageCancelled = "cancelled"
+)
+
+var stageOrder = map[string]int{
+	StageRequested: 0,
+	StageBidding:   1,
+	StageAssigned:  2,
+	StageArrived:   3,
+	StageStarted:   4,
+	StageCompleted: 5,
+	StageCancelled: 5,
+}
+
+// eventStages maps event types directly to stages
+var eventStages = map[string]string{
+	common.EventTypeTripCreated:           StageRequested,
+	common.EventTypeBiddingSessionStarted: StageBidding,
+	common.EventTypeDriverAssigned:        StageAssigned,
+	common.EventTypeInstantMatched:        StageAssigned,
+	common.EventTypeBidAccepted:           StageAssigned,
+	common.EventTypeDriverArrived:         StageArrived,
+	common.EventTypeTripStarted:           StageStarted,
+	common.EventTypeTripCompleted:         StageCompleted,
+	common.EventTypeTripCancelled:         StageCancelled,
+}
+
+// statusStages maps trip statuses carried by TripUpdated events to stages
+var statusStages = map[string]string{
+	common.TripStatusAccepted:   StageAssigned,
+	common.TripStatusInProgress: StageStarted,
+	common.TripStatusCompleted:  StageCompleted,
+	common.TripStatusCancelled:  StageCancelled,
+}
+
+// Metadata keys identifying who caused an event
+const (
+	MetadataActorID   = "actorId"
+	MetadataActorType = "actorType"
+)
+
+// Entry is one step of a trip timeline
+type Entry struct {
+	Stage     string    `json:"stage"`
+	At        time.Time `json:"at"`
+	ActorID   string    `json:"actorId,omitempty"`
+	ActorType string    `json:"actorType,omitempty"`
+	EventID   string    `json:"eventId"`
+	EventType string    `json:"eventType"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Timeline is the ordered lifecycle of a trip
+type Timeline struct {
+	TripID  string  `json:"tripId"`
+	Entries []Entry `json:"entries"`
+	// Terminal is true once the trip completed or was cancelled
+	Terminal bool `json:"terminal"`
+}
+
+// eventPayload holds the payload fields the timeline understands
+type eventPayload struct {
+	Status   string `json:"status"`
+	DriverID string `json:"driverId"`
+	RiderID  string `json:"riderId"`
+	Reason   string `json:"reason"`
+}
+
+// StageOf returns the stage an event represents, if any
+func StageOf(event events.BaseEvent) (string, bool) {
+	if stage, ok := eventStages[event.Type]; ok {
+		return stage, true
+	}
+	if event.Type == common.EventTypeTripUpdated {
+		var payload eventPayload
+		if err := event.Decode(&payload); err == nil {
+			stage, ok := statusStages[payload.Status]
+			return stage, ok
+		}
+	}
+	return "", false
+}
+
+// Build produces an ordered, deduplicated timeline from trip events. Events
+// may arrive in any order and more than once; each stage keeps its earliest
+// occurrence.
+func Build(tripID string, evts []events.BaseEvent) Timeline {
+	seen := make(map[string]bool, len(evts))
+	byStage := make(map[string]Entry)
+
+	for _, event := range evts {
+		if event.ID != "" {
+			if seen[event.ID] {
+				continue
+			}
+			seen[event.ID] = true
+		}
+
+		stage, ok := StageOf(event)
+		if !ok {
+			continue
+		}
+		entry := toEntry(stage, event)
+		if existing, ok := byStage[stage]; ok && !entry.At.Before(existing.At) {
+			continue
+		}
+		byStage[stage] = entry
+	}
+
+	timeline := Timeline{TripID: tripID, Entries: make([]Entry, 0, len(byStage))}
+	for _, entry := range byStage {
+		timeline.Entries = append(timeline.Entries, entry)
+		if entry.Stage == StageCompleted || entry.Stage == StageCancelled {
+			timeline.Terminal = true
+		}
+	}
+
+	sort.Slice(timeline.Entries, func(i, j int) bool {
+		a, b := timeline.Entries[i], timeline.Entries[j]
+		if !a.At.Equal(b.At) {
+			return a.At.Before(b.At)
+		}
+		return stageOrder[a.Stage] < stageOrder[b.Stage]
+	})
+	return timeline
+}
+
+func toEntry(stage string, event events.BaseEvent) Entry {
+	entry := Entry{
+		Stage:     stage,
+		At:        event.Timestamp,
+		EventID:   event.ID,
+		EventType: event.Type,
+		ActorID:   event.Metadata[MetadataActorID],
+		ActorType: event.Metadata[MetadataActorType],
+	}
+
+	var payload eventPayload
+	if err := event.Decode(&payload); err == nil {
+		entry.Reason = payload.Reason
+		if entry.ActorID == "" {
+			switch stage {
+			case StageRequested:
+				entry.ActorID, entry.ActorType = payload.RiderID, common.UserTypeRider
+			case StageAssigned, StageArrived, StageStarted, StageCompleted:
+				entry.ActorID, entry.ActorType = payload.DriverID, common.UserTypeDriver
+			}
+			if entry.ActorID == "" {
+				entry.ActorType = ""
+			}
+		}
+	}
+	return entry
+}
+
+// Stage returns the entry for stage, if present
+func (t Timeline) Stage(stage string) (Entry, bool) {
+	for _, entry := range t.Entries {
+		if entry.Stage == stage {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Duration returns the time between two stages, e.g. requested → assigned wait time
+func (t Timeline) Duration(from, to string) (time.Duration, bool) {
+	start, ok := t.Stage(from)
+	if !ok {
+		return 0, false
+	}
+	end, ok := t.Stage(to)
+	if !ok {
+		return 0, false
+	}
+	return end.At.Sub(start.At), true
+}
@@ -0,0 +1,93 @@
+package common
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Default timeouts and intervals
+const (
+	DefaultBiddingTimer          = 20 * time.Second
+	DefaultWebSocketPingInterval = 30 * time.Second
+	DefaultWebSocketWriteTimeout = 10 * time.Second
+	DefaultWebSocketReadTimeout  = 10 * time.Second
+	DefaultWebSocketPongTimeout  = 60 * time.Second
+	DefaultRequestTimeoutPeriod  = 30 * time.Second
+)
+
+// Timeouts is the single source of truth for time based settings shared across packages
+type Timeouts struct {
+	BiddingTimer          time.Duration `json:"biddingTimer"`
+	WebSocketPingInterval time.Duration `json:"webSocketPingInterval"`
+	WebSocketWriteTimeout time.Duration `json:"webSocketWriteTimeout"`
+	WebSocketReadTimeout  time.Duration `json:"webSocketReadTimeout"`
+	WebSocketPongTimeout  time.Duration `json:"webSocketPongTimeout"`
+	RequestTimeout        time.Duration `json:"requestTimeout"`
+}
+
+// DefaultTimeouts returns the built-in timeouts
+func DefaultTimeouts() Timeouts {
+	return Timeouts{
+		BiddingTimer:          DefaultBiddingTimer,
+		WebSocketPingInterval: DefaultWebSocketPingInterval,
+		WebSocketWriteTimeout: DefaultWebSocketWriteTimeout,
+		WebSocketReadTimeout:  DefaultWebSocketReadTimeout,
+		WebSocketPongTimeout:  DefaultWebSocketPongTimeout,
+		RequestTimeout:        DefaultRequestTimeoutPeriod,
+	}
+}
+
+// LoadTimeoutsFromEnv returns the defaults overridden by environment variables.
+// Values accept Go durations ("45s", "1m30s") or plain integers as seconds.
+func LoadTimeoutsFromEnv() Timeouts {
+	t := DefaultTimeouts()
+	t.BiddingTimer = durationFromEnv(EnvBiddingTimer, t.BiddingTimer)
+	t.WebSocketPingInterval = durationFromEnv(EnvWebSocketPingInterval, t.WebSocketPingInterval)
+	t.WebSocketWriteTimeout = durationFromEnv(EnvWebSocketWriteTimeout, t.WebSocketWriteTimeout)
+	t.WebSocketReadTimeout = durationFromEnv(EnvWebSocketReadTimeout, t.WebSocketReadTimeout)
+	t.WebSocketPongTimeout = durationFromEnv(EnvWebSocketPongTimeout, t.WebSocketPongTimeout)
+	t.RequestTimeout = durationFromEnv(EnvRequestTimeout, t.RequestTimeout)
+	return t
+}
+
+var (
+	timeoutsMu   sync.RWMutex
+	timeoutsOnce sync.Once
+	timeouts     Timeouts
+)
+
+// GetTimeouts returns the active timeouts, loaded from the environment on first use
+func GetTimeouts() Timeouts {
+	timeoutsOnce.Do(func() {
+		timeoutsMu.Lock()
+		timeouts = LoadTimeoutsFromEnv()
+		timeoutsMu.Unlock()
+	})
+	timeoutsMu.RLock()
+	defer timeoutsMu.RUnlock()
+	return timeouts
+}
+
+// SetTimeouts replaces the active timeouts, e.g. from a config store
+func SetTimeouts(t Timeouts) {
+	timeoutsOnce.Do(func() {})
+	timeoutsMu.Lock()
+	defer timeoutsMu.Unlock()
+	timeouts = t
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
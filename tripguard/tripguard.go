@@ -0,0 +1,188 @@
+package tripguard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/location"
+	"github.com/redis/go-redis/v9"
+)
+
+// TripRequest holds the fields compared when detecting duplicate trip requests
+type TripRequest struct {
+	RiderID      string            `json:"riderId"`
+	Pickup       location.Location `json:"pickup"`
+	Dropoff      location.Location `json:"dropoff"`
+	VehicleClass string            `json:"vehicleClass,omitempty"`
+}
+
+// Config configures duplicate detection
+type Config struct {
+	// Window is how long a request blocks near-identical ones from the same rider
+	Window time.Duration
+	// ToleranceMeters is the max pickup/dropoff distance for requests to be considered identical
+	ToleranceMeters float64
+	// MaxRetries bounds optimistic-transaction retries under contention
+	MaxRetries int
+	KeyPrefix  string
+}
+
+// fingerprint is a recent trip request recorded for a rider
+type fingerprint struct {
+	TripID    string      `json:"tripId"`
+	Request   TripRequest `json:"request"`
+	CreatedAt int64       `json:"createdAt"`
+}
+
+// Guard detects duplicate trip creation attempts using Redis
+type Guard struct {
+	client redis.UniversalClient
+	config Config
+}
+
+// ErrContention is returned when the rider's fingerprints keep changing concurrently
+var ErrContention = errors.New("trip guard: too much contention")
+
+// NewGuard creates a new trip request guard
+func NewGuard(client redis.UniversalClient, config Config) *Guard {
+	if config.Window <= 0 {
+		config.Window = 30 * time.Second
+	}
+	if config.ToleranceMeters <= 0 {
+		config.ToleranceMeters = 100
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 5
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "tripguard:rider:"
+	}
+	return &Guard{
+		client: client,
+		config: config,
+	}
+}
+
+func (g *Guard) key(riderID string) string {
+	return g.config.KeyPrefix + riderID
+}
+
+// matches reports whether two requests are near-identical
+func (g *Guard) matches(a, b TripRequest) bool {
+	if a.VehicleClass != b.VehicleClass {
+		return false
+	}
+	toleranceKm := g.config.ToleranceMeters / 1000
+	return location.HaversineKm(a.Pickup, b.Pickup) <= toleranceKm &&
+		location.HaversineKm(a.Dropoff, b.Dropoff) <= toleranceKm
+}
+
+// Reserve records req for tripID unless the rider made a near-identical request
+// within the window, in which case the existing trip ID is returned and
+// duplicate is true. Call it before creating the trip.
+func (g *Guard) Reserve(ctx context.Context, req TripRequest, tripID string) (existingTripID string, duplicate bool, err error) {
+	if req.RiderID == "" {
+		return "", false, errors.New("trip guard: rider ID is required")
+	}
+
+	key := g.key(req.RiderID)
+	for attempt := 0; attempt < g.config.MaxRetries; attempt++ {
+		err = g.client.Watch(ctx, func(tx *redis.Tx) error {
+			recent, err := g.load(ctx, tx, key)
+			if err != nil {
+				return err
+			}
+
+			for _, fp := range recent {
+				if g.matches(fp.Request, req) {
+					existingTripID, duplicate = fp.TripID, true
+					return nil
+				}
+			}
+
+			recent = append(recent, fingerprint{TripID: tripID, Request: req, CreatedAt: time.Now().UnixMilli()})
+			data, err := json.Marshal(recent)
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, data, g.config.Window)
+				return nil
+			})
+			return err
+		}, key)
+
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("trip guard: failed to reserve request: %w", err)
+		}
+		return existingTripID, duplicate, nil
+	}
+	return "", false, ErrContention
+}
+
+// Release forgets tripID, e.g. when trip creation failed after Reserve
+func (g *Guard) Release(ctx context.Context, riderID, tripID string) error {
+	key := g.key(riderID)
+	err := g.client.Watch(ctx, func(tx *redis.Tx) error {
+		recent, err := g.load(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+
+		kept := recent[:0]
+		for _, fp := range recent {
+			if fp.TripID != tripID {
+				kept = append(kept, fp)
+			}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if len(kept) == 0 {
+				pipe.Del(ctx, key)
+				return nil
+			}
+			data, err := json.Marshal(kept)
+			if err != nil {
+				return err
+			}
+			pipe.Set(ctx, key, data, redis.KeepTTL)
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		return fmt.Errorf("trip guard: failed to release trip %s: %w", tripID, err)
+	}
+	return nil
+}
+
+// load returns the rider's fingerprints that are still within the window
+func (g *Guard) load(ctx context.Context, tx *redis.Tx, key string) ([]fingerprint, error) {
+	data, err := tx.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var recent []fingerprint
+	if err := json.Unmarshal(data, &recent); err != nil {
+		return nil, nil // corrupt entry, start over
+	}
+
+	cutoff := time.Now().Add(-g.config.Window).UnixMilli()
+	fresh := recent[:0]
+	for _, fp := range recent {
+		if fp.CreatedAt >= cutoff {
+			fresh = append(fresh, fp)
+		}
+	}
+	return fresh, nil
+}
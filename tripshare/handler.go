@@ -0,0 +1,121 @@
+package tripshare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/middleware"
+	"github.com/mihirk-khode/motocabz-common/util/timefmt"
+	"github.com/mihirk-khode/motocabz-common/websocket"
+)
+
+// TokenQueryParam is the query parameter carrying the share token
+const TokenQueryParam = "token"
+
+// authorize verifies the request's share token and writes an error response on failure
+func (s *Service) authorize(w http.ResponseWriter, r *http.Request) (*Claims, bool) {
+	claims, err := s.Verify(r.Context(), r.URL.Query().Get(TokenQueryParam))
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrTokenExpired) || errors.Is(err, ErrTokenRevoked) {
+			middleware.WriteJSON(w, http.StatusUnauthorized, common.RsUnauthorized(err.Error()))
+		} else {
+			middleware.WriteJSON(w, http.StatusInternalServerError, common.RsInternalErr("", err.Error()))
+		}
+		return nil, false
+	}
+	return claims, true
+}
+
+// SSEHandler streams trip updates as server-sent events
+func (s *Service) SSEHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := s.authorize(w, r)
+		if !ok {
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			middleware.WriteJSON(w, http.StatusInternalServerError, common.RsInternalErr("streaming unsupported", nil))
+			return
+		}
+
+		updates, err := s.Subscribe(r.Context(), claims)
+		if err != nil {
+			middleware.WriteJSON(w, http.StatusInternalServerError, common.RsInternalErr("", err.Error()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for update := range updates {
+			data, err := json.Marshal(update)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", websocket.MessageTypeTripStatusUpdate, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	})
+}
+
+// WebSocketHandler streams trip updates over a WebSocket connection
+func (s *Service) WebSocketHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := s.authorize(w, r)
+		if !ok {
+			return
+		}
+
+		conn, err := websocket.WebSocketUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("Failed to upgrade trip share connection: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		// Viewers never send data; reading detects the close so the subscription ends
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		updates, err := s.Subscribe(ctx, claims)
+		if err != nil {
+			log.Printf("Failed to subscribe trip share viewer: %v", err)
+			return
+		}
+
+		for update := range updates {
+			msg := websocket.NewPayloadMessage(websocket.TripStatusUpdatePayload{
+				TripID:         update.TripID,
+				Status:         update.Status,
+				DriverLocation: update.DriverLocation,
+				EtaMinutes:     update.ETAMinutes,
+				UpdatedAt:      timefmt.NewTime(update.UpdatedAt),
+			})
+			conn.SetWriteDeadline(time.Now().Add(common.GetTimeouts().WebSocketWriteTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package tripshare
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Scopes granted to share tokens
+const (
+	ScopeTripStatus     = "trip:status"
+	ScopeDriverLocation = "driver:location"
+)
+
+// Token errors
+var (
+	ErrInvalidToken = errors.New("invalid share token")
+	ErrTokenExpired = errors.New("share token expired")
+	ErrTokenRevoked = errors.New("share token revoked")
+)
+
+// Claims are the contents of a share token
+type Claims struct {
+	TokenID   string   `json:"jti"`
+	TripID    string   `json:"trip"`
+	Scopes    []string `json:"scp"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// HasScope reports whether the token grants scope
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether the token is past its expiry
+func (c *Claims) Expired(now time.Time) bool {
+	return now.Unix() >= c.ExpiresAt
+}
+
+// encodeToken signs claims as base64url(payload).base64url(hmac)
+func encodeToken(secret []byte, claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	body := base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + base64.RawURLEncoding.EncodeToString(sign(secret, body)), nil
+}
+
+// decodeToken verifies the signature and returns the claims
+func decodeToken(secret []byte, token string) (*Claims, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	expected, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(expected, sign(secret, body)) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+func sign(secret []byte, body string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
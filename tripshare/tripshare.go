@@ -0,0 +1,303 @@
+package tripshare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/location"
+	"github.com/redis/go-redis/v9"
+)
+
+// TripUpdate is the view of a trip shared with unauthenticated viewers
+type TripUpdate struct {
+	TripID         string             `json:"tripId"`
+	Status         string             `json:"status"`
+	DriverLocation *location.Location `json:"driverLocation,omitempty"`
+	ETAMinutes     *float64           `json:"etaMinutes,omitempty"`
+	UpdatedAt      time.Time          `json:"updatedAt"`
+}
+
+// IsTerminal reports whether the trip has finished
+func (u TripUpdate) IsTerminal() bool {
+	return u.Status == common.TripStatusCompleted || u.Status == common.TripStatusCancelled
+}
+
+// Config configures share token minting and delivery
+type Config struct {
+	// Secret signs share tokens; it must be shared by all instances
+	Secret []byte
+	// DefaultTTL applies when Mint is called without a TTL
+	DefaultTTL time.Duration
+	// MaxTTL caps the lifetime of any token
+	MaxTTL time.Duration
+	// LocationPolicy coarsens the driver location shown to viewers
+	LocationPolicy location.PrivacyPolicy
+	// RevocationCheckInterval is how often open streams re-check that their
+	// token has not been revoked
+	RevocationCheckInterval time.Duration
+	KeyPrefix               string
+}
+
+// Service mints share tokens and relays trip updates to viewers
+type Service struct {
+	client redis.UniversalClient
+	config Config
+}
+
+// NewService creates a new trip sharing service
+func NewService(client redis.UniversalClient, config Config) (*Service, error) {
+	if len(config.Secret) < 32 {
+		return nil, errors.New("trip share secret must be at least 32 bytes")
+	}
+	if config.DefaultTTL <= 0 {
+		config.DefaultTTL = 2 * time.Hour
+	}
+	if config.MaxTTL <= 0 {
+		config.MaxTTL = 12 * time.Hour
+	}
+	if config.LocationPolicy == (location.PrivacyPolicy{}) {
+		config.LocationPolicy = location.PrivacyPolicy{Precision: 3}
+	}
+	if config.RevocationCheckInterval <= 0 {
+		config.RevocationCheckInterval = 10 * time.Second
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "tripshare:"
+	}
+	return &Service{
+		client: client,
+		config: config,
+	}, nil
+}
+
+// tripTag hash-tags every key of a trip into one Redis Cluster slot, so
+// token, token set and last update can change in a single transaction
+func (s *Service) tripTag(tripID string) string {
+	return "{" + s.config.KeyPrefix + "trip:" + tripID + "}"
+}
+
+func (s *Service) tokenKey(tripID, tokenID string) string {
+	return s.tripTag(tripID) + ":token:" + tokenID
+}
+func (s *Service) tripKey(tripID string) string { return s.tripTag(tripID) + ":tokens" }
+func (s *Service) lastKey(tripID string) string { return s.tripTag(tripID) + ":last" }
+func (s *Service) channel(tripID string) string { return s.config.KeyPrefix + "updates:" + tripID }
+
+// Mint creates a share token for tripID valid for ttl (capped at MaxTTL)
+func (s *Service) Mint(ctx context.Context, tripID string, ttl time.Duration, scopes ...string) (string, *Claims, error) {
+	if tripID == "" {
+		return "", nil, errors.New(common.ErrMsgTripIDRequired)
+	}
+	if ttl <= 0 {
+		ttl = s.config.DefaultTTL
+	}
+	if ttl > s.config.MaxTTL {
+		ttl = s.config.MaxTTL
+	}
+	if len(scopes) == 0 {
+		scopes = []string{ScopeTripStatus, ScopeDriverLocation}
+	}
+
+	claims := Claims{
+		TokenID:   uuid.NewString(),
+		TripID:    tripID,
+		Scopes:    scopes,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+
+	token, err := encodeToken(s.config.Secret, claims)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode share token: %w", err)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.tokenKey(tripID, claims.TokenID), tripID, ttl)
+		pipe.SAdd(ctx, s.tripKey(tripID), claims.TokenID)
+		pipe.Expire(ctx, s.tripKey(tripID), s.config.MaxTTL)
+		return nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to store share token: %w", err)
+	}
+	return token, &claims, nil
+}
+
+// Verify checks the signature, expiry and revocation state of a token
+func (s *Service) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims, err := decodeToken(s.config.Secret, token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Expired(time.Now()) {
+		return nil, ErrTokenExpired
+	}
+	if err := s.checkRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// checkRevoked returns ErrTokenRevoked once the token's record is gone
+func (s *Service) checkRevoked(ctx context.Context, claims *Claims) error {
+	tripID, err := s.client.Get(ctx, s.tokenKey(claims.TripID, claims.TokenID)).Result()
+	if errors.Is(err, redis.Nil) || (err == nil && tripID != claims.TripID) {
+		return ErrTokenRevoked
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify share token: %w", err)
+	}
+	return nil
+}
+
+// Revoke invalidates a single token
+func (s *Service) Revoke(ctx context.Context, claims *Claims) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.tokenKey(claims.TripID, claims.TokenID))
+		pipe.SRem(ctx, s.tripKey(claims.TripID), claims.TokenID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke share token: %w", err)
+	}
+	return nil
+}
+
+// RevokeTrip invalidates every token of a trip
+func (s *Service) RevokeTrip(ctx context.Context, tripID string) error {
+	tokenIDs, err := s.client.SMembers(ctx, s.tripKey(tripID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list share tokens for trip %s: %w", tripID, err)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tokenID := range tokenIDs {
+			pipe.Del(ctx, s.tokenKey(tripID, tokenID))
+		}
+		pipe.Del(ctx, s.tripKey(tripID), s.lastKey(tripID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke share tokens for trip %s: %w", tripID, err)
+	}
+	return nil
+}
+
+// Publish relays a trip update to viewers. The driver location is coarsened
+// and all tokens are revoked once the trip reaches a terminal status.
+func (s *Service) Publish(ctx context.Context, update TripUpdate) error {
+	if update.UpdatedAt.IsZero() {
+		update.UpdatedAt = time.Now().UTC()
+	}
+	if update.DriverLocation != nil {
+		coarse := s.config.LocationPolicy.Apply(update.TripID, *update.DriverLocation)
+		update.DriverLocation = &coarse
+	}
+
+	data, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trip update: %w", err)
+	}
+
+	_, err = s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.lastKey(update.TripID), data, s.config.MaxTTL)
+		pipe.Publish(ctx, s.channel(update.TripID), data)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish trip update: %w", err)
+	}
+
+	if update.IsTerminal() {
+		return s.RevokeTrip(ctx, update.TripID)
+	}
+	return nil
+}
+
+// Subscribe streams updates for the token's trip, starting with the latest
+// known state. The channel closes when the trip ends, the token expires or is
+// revoked (checked every RevocationCheckInterval), or ctx is cancelled.
+func (s *Service) Subscribe(ctx context.Context, claims *Claims) (<-chan TripUpdate, error) {
+	sub := s.client.Subscribe(ctx, s.channel(claims.TripID))
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("failed to subscribe to trip %s: %w", claims.TripID, err)
+	}
+
+	out := make(chan TripUpdate, 8)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+
+		expiry := time.NewTimer(time.Until(time.Unix(claims.ExpiresAt, 0)))
+		defer expiry.Stop()
+		revocation := time.NewTicker(s.config.RevocationCheckInterval)
+		defer revocation.Stop()
+
+		if data, err := s.client.Get(ctx, s.lastKey(claims.TripID)).Bytes(); err == nil {
+			if update, ok := s.decode(claims, data); ok {
+				out <- update
+				if update.IsTerminal() {
+					return
+				}
+			}
+		}
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-expiry.C:
+				return
+			case <-revocation.C:
+				err := s.checkRevoked(ctx, claims)
+				if errors.Is(err, ErrTokenRevoked) {
+					return
+				}
+				// a Redis blip keeps the stream open until the next check
+				if err != nil && ctx.Err() == nil {
+					log.Printf("⚠️ Failed to re-check trip share token %s: %v", claims.TokenID, err)
+				}
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				update, ok := s.decode(claims, []byte(msg.Payload))
+				if !ok {
+					continue
+				}
+				select {
+				case out <- update:
+				case <-ctx.Done():
+					return
+				}
+				if update.IsTerminal() {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// decode parses an update and strips fields the token has no scope for
+func (s *Service) decode(claims *Claims, data []byte) (TripUpdate, bool) {
+	var update TripUpdate
+	if err := json.Unmarshal(data, &update); err != nil {
+		log.Printf("Failed to decode trip share update: %v", err)
+		return update, false
+	}
+	if !claims.HasScope(ScopeDriverLocation) {
+		update.DriverLocation = nil
+	}
+	if !claims.HasScope(ScopeTripStatus) {
+		update.Status = ""
+	}
+	return update, true
+}
@@ -0,0 +1,48 @@
+package tripshare
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRevokedTokenClosesOpenStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	svc, err := NewService(client, Config{
+		Secret:                  []byte(strings.Repeat("s", 32)),
+		RevocationCheckInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	_, claims, err := svc.Mint(ctx, "trip-1", time.Hour)
+	if err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+	updates, err := svc.Subscribe(ctx, claims)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := svc.Revoke(ctx, claims); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Fatal("received an update after revocation")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream still open after the token was revoked")
+	}
+}
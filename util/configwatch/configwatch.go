@@ -0,0 +1,284 @@
+package configwatch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/dapr/go-sdk/client"
+)
+
+// Config holds watcher settings
+type Config struct {
+	StoreName string
+	// CacheFile persists the last good raw values so a restart during a
+	// sidecar outage still starts with real configuration; empty disables it
+	CacheFile string
+	// RefreshInterval re-reads all keys to recover from dropped subscriptions
+	RefreshInterval time.Duration
+}
+
+// binding decodes raw values for one key
+type binding interface {
+	apply(raw string) error
+}
+
+// Watcher subscribes to Dapr configuration keys and keeps typed values current
+type Watcher struct {
+	client client.Client
+	config Config
+
+	mu       sync.RWMutex
+	bindings map[string]binding
+	raw      map[string]string
+}
+
+// NewWatcher creates a watcher on the given configuration store
+func NewWatcher(daprClient client.Client, config Config) *Watcher {
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = 5 * time.Minute
+	}
+	return &Watcher{
+		client:   daprClient,
+		config:   config,
+		bindings: make(map[string]binding),
+		raw:      make(map[string]string),
+	}
+}
+
+// Value is a typed, concurrently readable configuration value
+type Value[T any] struct {
+	mu       sync.RWMutex
+	value    T
+	loaded   bool
+	validate func(T) error
+	onChange []func(old, new T)
+}
+
+// Get returns the current value and whether one has been loaded
+func (v *Value[T]) Get() (T, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.value, v.loaded
+}
+
+// GetOr returns the current value or fallback when none has been loaded
+func (v *Value[T]) GetOr(fallback T) T {
+	if value, ok := v.Get(); ok {
+		return value
+	}
+	return fallback
+}
+
+// OnChange registers a callback invoked after each accepted update
+func (v *Value[T]) OnChange(fn func(old, new T)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.onChange = append(v.onChange, fn)
+}
+
+func (v *Value[T]) apply(raw string) error {
+	var next T
+	if err := decode(raw, &next); err != nil {
+		return err
+	}
+	if v.validate != nil {
+		if err := v.validate(next); err != nil {
+			return fmt.Errorf("invalid value: %w", err)
+		}
+	}
+
+	v.mu.Lock()
+	old, loaded := v.value, v.loaded
+	v.value, v.loaded = next, true
+	callbacks := append([]func(old, new T){}, v.onChange...)
+	v.mu.Unlock()
+
+	if loaded && reflect.DeepEqual(old, next) {
+		return nil
+	}
+	for _, fn := range callbacks {
+		fn(old, next)
+	}
+	return nil
+}
+
+// decode parses JSON, falling back to the raw string for string targets so
+// plain values like "on" do not need quoting in the store
+func decode[T any](raw string, target *T) error {
+	err := json.Unmarshal([]byte(raw), target)
+	if err == nil {
+		return nil
+	}
+	if s, ok := any(target).(*string); ok {
+		*s = raw
+		return nil
+	}
+	return fmt.Errorf("failed to decode value: %w", err)
+}
+
+// Bind registers key with an optional validator. Bind must be called before Run.
+func Bind[T any](w *Watcher, key string, validate func(T) error) *Value[T] {
+	value := &Value[T]{validate: validate}
+
+	w.mu.Lock()
+	w.bindings[key] = value
+	raw, cached := w.raw[key]
+	w.mu.Unlock()
+
+	if cached {
+		if err := value.apply(raw); err != nil {
+			log.Printf("Config watcher: cached value for %s rejected: %v", key, err)
+		}
+	}
+	return value
+}
+
+func (w *Watcher) keys() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	keys := make([]string, 0, len(w.bindings))
+	for key := range w.bindings {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Run loads all bound keys, subscribes to updates and periodically refreshes
+// until ctx is cancelled. When the sidecar is unavailable at startup the
+// cached values are used instead.
+func (w *Watcher) Run(ctx context.Context) error {
+	keys := w.keys()
+	if len(keys) == 0 {
+		return fmt.Errorf("no configuration keys bound")
+	}
+
+	if err := w.refresh(ctx, keys); err != nil {
+		log.Printf("⚠️ Config watcher: initial load from %s failed, using cache: %v", w.config.StoreName, err)
+		w.loadCache()
+	}
+
+	subscriptionID, err := w.client.SubscribeConfigurationItems(ctx, w.config.StoreName, keys,
+		func(_ string, items map[string]*client.ConfigurationItem) {
+			w.update(items)
+		})
+	if err != nil {
+		log.Printf("⚠️ Config watcher: subscribe to %s failed, falling back to polling: %v", w.config.StoreName, err)
+	}
+
+	ticker := time.NewTicker(w.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if subscriptionID != "" {
+				unsubCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				w.client.UnsubscribeConfigurationItems(unsubCtx, w.config.StoreName, subscriptionID)
+				cancel()
+			}
+			return ctx.Err()
+		case <-ticker.C:
+			if err := w.refresh(ctx, keys); err != nil {
+				log.Printf("Config watcher: refresh from %s failed: %v", w.config.StoreName, err)
+			}
+		}
+	}
+}
+
+func (w *Watcher) refresh(ctx context.Context, keys []string) error {
+	items, err := w.client.GetConfigurationItems(ctx, w.config.StoreName, keys)
+	if err != nil {
+		return fmt.Errorf("failed to get configuration: %w", err)
+	}
+	w.update(items)
+	return nil
+}
+
+func (w *Watcher) update(items map[string]*client.ConfigurationItem) {
+	changed := false
+	for key, item := range items {
+		if item == nil {
+			continue
+		}
+
+		w.mu.RLock()
+		b, bound := w.bindings[key]
+		previous, seen := w.raw[key]
+		w.mu.RUnlock()
+		if !bound || (seen && previous == item.Value) {
+			continue
+		}
+
+		if err := b.apply(item.Value); err != nil {
+			log.Printf("Config watcher: rejected update for %s (version %s): %v", key, item.Version, err)
+			continue
+		}
+
+		w.mu.Lock()
+		w.raw[key] = item.Value
+		w.mu.Unlock()
+		changed = true
+	}
+
+	if changed {
+		w.saveCache()
+	}
+}
+
+func (w *Watcher) loadCache() {
+	if w.config.CacheFile == "" {
+		return
+	}
+	data, err := os.ReadFile(w.config.CacheFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Config watcher: failed to read cache: %v", err)
+		}
+		return
+	}
+
+	var cached map[string]string
+	if err := json.Unmarshal(data, &cached); err != nil {
+		log.Printf("Config watcher: failed to decode cache: %v", err)
+		return
+	}
+
+	items := make(map[string]*client.ConfigurationItem, len(cached))
+	for key, value := range cached {
+		items[key] = &client.ConfigurationItem{Value: value}
+	}
+	w.update(items)
+}
+
+func (w *Watcher) saveCache() {
+	if w.config.CacheFile == "" {
+		return
+	}
+
+	w.mu.RLock()
+	data, err := json.Marshal(w.raw)
+	w.mu.RUnlock()
+	if err != nil {
+		return
+	}
+
+	tmp := w.config.CacheFile + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(w.config.CacheFile), 0o755); err != nil {
+		log.Printf("Config watcher: failed to create cache dir: %v", err)
+		return
+	}
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		log.Printf("Config watcher: failed to write cache: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, w.config.CacheFile); err != nil {
+		log.Printf("Config watcher: failed to replace cache: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package hashring
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultVirtualNodes is the number of points each member places on the ring
+const DefaultVirtualNodes = 128
+
+// Ring is a consistent hash ring with virtual nodes
+type Ring struct {
+	mu           sync.RWMutex
+	virtualNodes int
+	hashes       []uint32
+	owners       map[uint32]string
+	members      map[string]struct{}
+}
+
+// New creates a new hash ring with the given members
+func New(virtualNodes int, members ...string) *Ring {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	r := &Ring{
+		virtualNodes: virtualNodes,
+		owners:       make(map[uint32]string),
+		members:      make(map[string]struct{}),
+	}
+	r.Add(members...)
+	return r
+}
+
+// Add places members on the ring
+func (r *Ring) Add(members ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, member := range members {
+		if _, exists := r.members[member]; exists || member == "" {
+			continue
+		}
+		r.members[member] = struct{}{}
+		for i := 0; i < r.virtualNodes; i++ {
+			h := hashKey(member + "#" + strconv.Itoa(i))
+			r.owners[h] = member
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+}
+
+// Remove takes a member and its virtual nodes off the ring
+func (r *Ring) Remove(member string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.members[member]; !exists {
+		return
+	}
+	delete(r.members, member)
+
+	hashes := r.hashes[:0]
+	for _, h := range r.hashes {
+		if r.owners[h] == member {
+			delete(r.owners, h)
+			continue
+		}
+		hashes = append(hashes, h)
+	}
+	r.hashes = hashes
+}
+
+// Get returns the member owning key, or "" when the ring is empty
+func (r *Ring) Get(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	h := hashKey(key)
+	idx := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if idx == len(r.hashes) {
+		idx = 0
+	}
+	return r.owners[r.hashes[idx]]
+}
+
+// Members returns the sorted list of members on the ring
+func (r *Ring) Members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]string, 0, len(r.members))
+	for member := range r.members {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// Len returns the number of members on the ring
+func (r *Ring) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.members)
+}
+
+func hashKey(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
@@ -0,0 +1,177 @@
+package hashring
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MembershipConfig configures Redis heartbeat based ring membership
+type MembershipConfig struct {
+	// Group identifies the set of replicas sharing work, e.g. "location-processor"
+	Group string
+	// NodeID identifies this replica, typically the pod name
+	NodeID string
+	// HeartbeatInterval is how often this node refreshes its heartbeat
+	HeartbeatInterval time.Duration
+	// TTL is how long a node stays a member without heartbeats
+	TTL          time.Duration
+	VirtualNodes int
+	KeyPrefix    string
+}
+
+// ChangeFunc is called with the new ring whenever membership changes
+type ChangeFunc func(ring *Ring, added, removed []string)
+
+// Membership keeps a hash ring in sync with live replicas via Redis heartbeats
+type Membership struct {
+	client redis.UniversalClient
+	config MembershipConfig
+
+	mu        sync.RWMutex
+	ring      *Ring
+	callbacks []ChangeFunc
+}
+
+// NewMembership creates a new membership tracker
+func NewMembership(client redis.UniversalClient, config MembershipConfig) *Membership {
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = 5 * time.Second
+	}
+	if config.TTL <= 0 {
+		config.TTL = 3 * config.HeartbeatInterval
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "hashring:"
+	}
+	return &Membership{
+		client: client,
+		config: config,
+		ring:   New(config.VirtualNodes),
+	}
+}
+
+func (m *Membership) key() string {
+	return m.config.KeyPrefix + m.config.Group
+}
+
+// OnChange registers a callback invoked when ownership changes
+func (m *Membership) OnChange(fn ChangeFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callbacks = append(m.callbacks, fn)
+}
+
+// Ring returns the current ring snapshot
+func (m *Membership) Ring() *Ring {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ring
+}
+
+// Owner returns the node owning key
+func (m *Membership) Owner(key string) string {
+	return m.Ring().Get(key)
+}
+
+// Owns reports whether this node owns key
+func (m *Membership) Owns(key string) bool {
+	return m.Owner(key) == m.config.NodeID
+}
+
+// Heartbeat records this node as alive and refreshes the ring
+func (m *Membership) Heartbeat(ctx context.Context) error {
+	now := time.Now()
+	cutoff := now.Add(-m.config.TTL).UnixMilli()
+
+	var members *redis.StringSliceCmd
+	_, err := m.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, m.key(), redis.Z{Score: float64(now.UnixMilli()), Member: m.config.NodeID})
+		pipe.ZRemRangeByScore(ctx, m.key(), "-inf", strconv.FormatInt(cutoff, 10))
+		members = pipe.ZRange(ctx, m.key(), 0, -1)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send heartbeat for %s: %w", m.config.NodeID, err)
+	}
+
+	m.update(members.Val())
+	return nil
+}
+
+// update rebuilds the ring when the member set differs from the current one
+func (m *Membership) update(members []string) {
+	m.mu.Lock()
+	current := m.ring.Members()
+
+	added, removed := diffMembers(current, members)
+	if len(added) == 0 && len(removed) == 0 {
+		m.mu.Unlock()
+		return
+	}
+
+	ring := New(m.config.VirtualNodes, members...)
+	m.ring = ring
+	callbacks := append([]ChangeFunc(nil), m.callbacks...)
+	m.mu.Unlock()
+
+	log.Printf("Hash ring %s membership changed: +%v -%v", m.config.Group, added, removed)
+	for _, fn := range callbacks {
+		fn(ring, added, removed)
+	}
+}
+
+// Start sends heartbeats until ctx is cancelled, then leaves the group
+func (m *Membership) Start(ctx context.Context) error {
+	if err := m.Heartbeat(ctx); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(m.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			leaveCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			return m.Leave(leaveCtx)
+		case <-ticker.C:
+			if err := m.Heartbeat(ctx); err != nil {
+				log.Printf("Hash ring heartbeat failed: %v", err)
+			}
+		}
+	}
+}
+
+// Leave removes this node from the group so peers take over its keys immediately
+func (m *Membership) Leave(ctx context.Context) error {
+	if err := m.client.ZRem(ctx, m.key(), m.config.NodeID).Err(); err != nil {
+		return fmt.Errorf("failed to leave hash ring %s: %w", m.config.Group, err)
+	}
+	return nil
+}
+
+func diffMembers(current, next []string) (added, removed []string) {
+	seen := make(map[string]bool, len(current))
+	for _, member := range current {
+		seen[member] = true
+	}
+	for _, member := range next {
+		if !seen[member] {
+			added = append(added, member)
+		}
+		delete(seen, member)
+	}
+	for _, member := range current {
+		if seen[member] {
+			removed = append(removed, member)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,118 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mihirk-khode/motocabz-common/grpcmd"
+)
+
+// Supported locales
+const (
+	LocaleEnglish = "en"
+	LocaleAmharic = "am"
+	LocaleOromo   = "om"
+
+	DefaultLocale = LocaleEnglish
+)
+
+// Catalog keys used by the formatting helpers
+const (
+	KeyDistanceMeters     = "distance.meters"
+	KeyDistanceKilometers = "distance.kilometers"
+	KeyDurationLessMinute = "duration.less_than_minute"
+	KeyDurationMinutes    = "duration.minutes"
+	KeyDurationHours      = "duration.hours"
+	KeyDurationHoursMins  = "duration.hours_minutes"
+	KeyCurrencyPrefix     = "currency." // + ISO code
+)
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]map[string]string{
+		LocaleEnglish: {
+			KeyDistanceMeters:         "%s m",
+			KeyDistanceKilometers:     "%s km",
+			KeyDurationLessMinute:     "< 1 min",
+			KeyDurationMinutes:        "%d min",
+			KeyDurationHours:          "%d hr",
+			KeyDurationHoursMins:      "%d hr %d min",
+			KeyCurrencyPrefix + "ETB": "Br %s",
+			KeyCurrencyPrefix + "USD": "$%s",
+		},
+		LocaleAmharic: {
+			KeyDistanceMeters:         "%s ሜትር",
+			KeyDistanceKilometers:     "%s ኪ.ሜ",
+			KeyDurationLessMinute:     "ከ1 ደቂቃ በታች",
+			KeyDurationMinutes:        "%d ደቂቃ",
+			KeyDurationHours:          "%d ሰዓት",
+			KeyDurationHoursMins:      "%d ሰዓት %d ደቂቃ",
+			KeyCurrencyPrefix + "ETB": "%s ብር",
+			KeyCurrencyPrefix + "USD": "%s ዶላር",
+		},
+		LocaleOromo: {
+			KeyDistanceMeters:         "meetira %s",
+			KeyDistanceKilometers:     "km %s",
+			KeyDurationLessMinute:     "daqiiqaa 1 gadi",
+			KeyDurationMinutes:        "daqiiqaa %d",
+			KeyDurationHours:          "sa'aatii %d",
+			KeyDurationHoursMins:      "sa'aatii %d daqiiqaa %d",
+			KeyCurrencyPrefix + "ETB": "Birrii %s",
+		},
+	}
+)
+
+// Register adds or overrides messages for a locale
+func Register(locale string, messages map[string]string) {
+	locale = Normalize(locale)
+	mu.Lock()
+	defer mu.Unlock()
+	if catalogs[locale] == nil {
+		catalogs[locale] = make(map[string]string, len(messages))
+	}
+	for key, msg := range messages {
+		catalogs[locale][key] = msg
+	}
+}
+
+// Normalize reduces a locale tag such as "am-ET" or "en_US" to its language
+func Normalize(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	if locale == "" {
+		return DefaultLocale
+	}
+	return locale
+}
+
+// Lookup returns the message for key, falling back to the default locale
+func Lookup(locale, key string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if msg, ok := catalogs[Normalize(locale)][key]; ok {
+		return msg, true
+	}
+	msg, ok := catalogs[DefaultLocale][key]
+	return msg, ok
+}
+
+// T formats the message for key; the key itself is returned when unknown
+func T(locale, key string, args ...interface{}) string {
+	msg, ok := Lookup(locale, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// FromContext returns the locale propagated in gRPC metadata
+func FromContext(ctx context.Context) string {
+	return Normalize(grpcmd.Locale(ctx))
+}
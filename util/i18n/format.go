@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/refdata"
+)
+
+// FormatDistance renders meters as "850 m" below 1km and "12.4 km" above
+func FormatDistance(meters float64, locale string) string {
+	if meters < 1000 {
+		// Round to 10m; finer precision is noise for GPS distances
+		rounded := math.Round(meters/10) * 10
+		return T(locale, KeyDistanceMeters, refdata.FormatNumber(rounded, 0))
+	}
+
+	km := meters / 1000
+	decimals := 1
+	if km >= 100 {
+		decimals = 0
+	}
+	formatted := strings.TrimSuffix(refdata.FormatNumber(km, decimals), ".0")
+	return T(locale, KeyDistanceKilometers, formatted)
+}
+
+// FormatDuration renders durations as "12 min" or "1 hr 5 min", rounding to the minute
+func FormatDuration(d time.Duration, locale string) string {
+	if d < 30*time.Second {
+		return T(locale, KeyDurationLessMinute)
+	}
+
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 60 {
+		return T(locale, KeyDurationMinutes, minutes)
+	}
+
+	hours, minutes := minutes/60, minutes%60
+	if minutes == 0 {
+		return T(locale, KeyDurationHours, hours)
+	}
+	return T(locale, KeyDurationHoursMins, hours, minutes)
+}
+
+// FormatFareLocalized renders a fare in the currency's minor units with the
+// locale's symbol placement, e.g. "Br 1,250.00" or "1,250.00 ብር"
+func FormatFareLocalized(amount float64, currency, locale string) string {
+	c, ok := refdata.GetCurrency(currency)
+	if !ok {
+		return refdata.FormatAmount(amount, currency)
+	}
+	if _, ok := Lookup(locale, KeyCurrencyPrefix+c.Code); !ok {
+		return refdata.FormatAmount(amount, currency)
+	}
+
+	formatted := refdata.FormatNumber(amount, c.MinorUnits)
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+	return sign + T(locale, KeyCurrencyPrefix+c.Code, formatted)
+}
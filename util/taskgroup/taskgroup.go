@@ -0,0 +1,67 @@
+package taskgroup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mihirk-khode/motocabz-common/crashreport"
+)
+
+// Group runs tasks concurrently, cancelling the shared context on the first
+// error. Panics are recovered, reported and returned as errors.
+type Group struct {
+	name   string
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	once sync.Once
+	err  error
+}
+
+// New creates a group whose tasks share a context derived from ctx
+func New(ctx context.Context, name string) *Group {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{
+		name:   name,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Context returns the context passed to tasks
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go starts task in its own goroutine
+func (g *Group) Go(task func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := g.run(task); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+func (g *Group) run(task func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			crashreport.Capture(g.ctx, crashreport.NewReport(crashreport.SourceTask, g.name, r))
+			err = fmt.Errorf("task in group %s panicked: %v", g.name, r)
+		}
+	}()
+	return task(g.ctx)
+}
+
+// Wait blocks until all tasks finish and returns the first error
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,162 @@
+package timefmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout is the canonical wire format: RFC3339 in UTC with millisecond precision
+const Layout = "2006-01-02T15:04:05.000Z07:00"
+
+// DateLayout is the canonical date-only format
+const DateLayout = "2006-01-02"
+
+// parseLayouts are tried in order by Parse
+var parseLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	DateLayout,
+}
+
+// Format renders t in the canonical layout
+func Format(t time.Time) string {
+	return t.UTC().Format(Layout)
+}
+
+// Now returns the current time in the canonical layout
+func Now() string {
+	return Format(time.Now())
+}
+
+// Parse accepts any of the formats emitted across services: RFC3339 with or
+// without fraction, naive timestamps (treated as UTC), dates, and Unix
+// seconds or milliseconds as decimal strings
+func Parse(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty timestamp")
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return FromUnix(n), nil
+	}
+
+	for _, layout := range parseLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", s)
+}
+
+// ParseAny converts a decoded JSON value (string, number or time.Time) to a time
+func ParseAny(v interface{}) (time.Time, error) {
+	switch val := v.(type) {
+	case time.Time:
+		return val.UTC(), nil
+	case string:
+		return Parse(val)
+	case float64:
+		return FromUnix(int64(val)), nil
+	case int64:
+		return FromUnix(val), nil
+	case int:
+		return FromUnix(int64(val)), nil
+	case json.Number:
+		return Parse(val.String())
+	default:
+		return time.Time{}, fmt.Errorf("unsupported timestamp type %T", v)
+	}
+}
+
+// FromUnix interprets n as Unix seconds, or milliseconds when it is too large
+// to be a plausible seconds value
+func FromUnix(n int64) time.Time {
+	// 1e11 seconds is year 5138; anything above is milliseconds
+	if n > 1e11 || n < -1e11 {
+		return time.UnixMilli(n).UTC()
+	}
+	return time.Unix(n, 0).UTC()
+}
+
+// Time marshals to the canonical layout and unmarshals from any supported format
+type Time struct {
+	time.Time
+}
+
+// NewTime wraps t
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+// MarshalJSON implements json.Marshaler
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(Format(t.Time))), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *Time) UnmarshalJSON(data []byte) error {
+	parsed, err := unmarshalAny(data)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+// UnixTime marshals to Unix seconds and unmarshals from any supported format
+type UnixTime struct {
+	time.Time
+}
+
+// MarshalJSON implements json.Marshaler
+func (t UnixTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	parsed, err := unmarshalAny(data)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+func unmarshalAny(data []byte) (time.Time, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return time.Time{}, nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		s, err := strconv.Unquote(string(data))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return Parse(s)
+	}
+	return Parse(string(data))
+}
+
+// Score converts t to a Redis sorted-set score in Unix milliseconds
+func Score(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
+
+// FromScore converts a Unix-millisecond sorted-set score back to a time
+func FromScore(score float64) time.Time {
+	return time.UnixMilli(int64(score)).UTC()
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mihirk-khode/motocabz-common/refdata"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -328,6 +329,34 @@ func ValidateUserType(userType string) *ValidationError {
 	return ValidateEnum(userType, "userType", allowedTypes)
 }
 
+// ValidateVehicleClass validates vehicle class
+func ValidateVehicleClass(class string) *ValidationError {
+	allowedClasses := []string{
+		"ECONOMY",
+		"COMFORT",
+		"MINIVAN",
+		"LUXURY",
+	}
+	return ValidateEnum(class, "vehicleClass", allowedClasses)
+}
+
+// ValidateCityCode validates that a city code is a served city
+func ValidateCityCode(code string) *ValidationError {
+	return ValidateEnum(strings.ToUpper(code), "cityCode", refdata.CityCodes())
+}
+
+// ValidateCurrency validates that a currency code is supported
+func ValidateCurrency(code string) *ValidationError {
+	if !refdata.IsValidCurrency(code) {
+		return &ValidationError{
+			Field:   "currency",
+			Message: "currency must be a supported ISO 4217 code",
+			Value:   code,
+		}
+	}
+	return nil
+}
+
 // Helper function to convert validation errors to gRPC status
 func ValidationErrorsToStatus(errors []ValidationError) error {
 	if len(errors) == 0 {
@@ -0,0 +1,259 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageTypeAck is sent by clients to acknowledge a message by ID
+const MessageTypeAck = "ack"
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// AckConfig configures acknowledged delivery
+type AckConfig struct {
+	// CriticalTypes are the message types tracked until acknowledged; other
+	// types are sent fire-and-forget
+	CriticalTypes []string
+	// MaxAttempts is the number of sends, including the first, before a
+	// message is given up on
+	MaxAttempts int
+	// RetryInterval is how long to wait for an ack before resending
+	RetryInterval time.Duration
+	// StatusRetention is how long settled messages stay queryable
+	StatusRetention time.Duration
+	// OnUndelivered is called once a message exhausts its attempts, e.g. to
+	// fall back to a push notification
+	OnUndelivered func(record DeliveryRecord)
+}
+
+// DefaultAckConfig returns the default acknowledged delivery settings
+func DefaultAckConfig() AckConfig {
+	return AckConfig{
+		CriticalTypes:   []string{MessageTypeDriverAssigned, MessageTypeBidReceived},
+		MaxAttempts:     3,
+		RetryInterval:   5 * time.Second,
+		StatusRetention: 10 * time.Minute,
+	}
+}
+
+// DeliveryRecord is the delivery state of a tracked message
+type DeliveryRecord struct {
+	MessageID   string           `json:"messageId"`
+	UserID      string           `json:"userId"`
+	UserType    string           `json:"userType"`
+	Message     WebSocketMessage `json:"message"`
+	Status      string           `json:"status"`
+	Attempts    int              `json:"attempts"`
+	LastAttempt time.Time        `json:"lastAttempt"`
+	SettledAt   time.Time        `json:"settledAt,omitempty"`
+}
+
+// ackRelay is implemented by managers that can route acks between instances,
+// such as DistributedWebSocketManager
+type ackRelay interface {
+	RelayAck(userID, userType, messageID string)
+	OnRelayedAck(fn AckListener)
+}
+
+// ReliableSender adds acknowledgments to a manager: critical messages get an
+// ID, are resent until the client acks them, and are reported to
+// OnUndelivered when attempts run out. Records live on the sending instance;
+// with a DistributedWebSocketManager, acks received by another instance are
+// relayed over the fan-out channel, so every instance needs a ReliableSender
+// with AckHandler registered.
+type ReliableSender struct {
+	manager  IWebSocketManager
+	config   AckConfig
+	critical map[string]bool
+
+	mu      sync.Mutex
+	records map[string]*DeliveryRecord
+}
+
+// NewReliableSender wraps manager with acknowledged delivery
+func NewReliableSender(manager IWebSocketManager, config AckConfig) *ReliableSender {
+	defaults := DefaultAckConfig()
+	if config.CriticalTypes == nil {
+		config.CriticalTypes = defaults.CriticalTypes
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaults.RetryInterval
+	}
+	if config.StatusRetention <= 0 {
+		config.StatusRetention = defaults.StatusRetention
+	}
+
+	critical := make(map[string]bool, len(config.CriticalTypes))
+	for _, t := range config.CriticalTypes {
+		critical[t] = true
+	}
+	s := &ReliableSender{
+		manager:  manager,
+		config:   config,
+		critical: critical,
+		records:  make(map[string]*DeliveryRecord),
+	}
+	if relay, ok := manager.(ackRelay); ok {
+		relay.OnRelayedAck(func(userID, userType, messageID string) {
+			s.HandleAck(userID, userType, messageID)
+		})
+	}
+	return s
+}
+
+// SendMessage sends message to a user, tracking it until acknowledged when
+// its type is critical. The message ID is assigned if empty.
+func (s *ReliableSender) SendMessage(userID, userType string, message WebSocketMessage) error {
+	if !s.critical[message.Type] {
+		return s.manager.SendMessage(userID, userType, message)
+	}
+	_, err := s.Send(userID, userType, message)
+	return err
+}
+
+// Send tracks and sends message regardless of its type and returns its ID
+func (s *ReliableSender) Send(userID, userType string, message WebSocketMessage) (string, error) {
+	if message.ID == "" {
+		message.ID = uuid.NewString()
+	}
+	record := &DeliveryRecord{
+		MessageID:   message.ID,
+		UserID:      userID,
+		UserType:    userType,
+		Message:     message,
+		Status:      DeliveryPending,
+		Attempts:    1,
+		LastAttempt: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.records[message.ID] = record
+	s.mu.Unlock()
+
+	// a failed first send is retried like a missing ack
+	return message.ID, s.manager.SendMessage(userID, userType, message)
+}
+
+// HandleAck marks a message delivered; it returns false for unknown IDs or
+// acks from a user the message was not sent to
+func (s *ReliableSender) HandleAck(userID, userType, messageID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[messageID]
+	if !ok || record.UserID != userID || record.UserType != userType {
+		return false
+	}
+	if record.Status == DeliveryPending {
+		record.Status = DeliveryDelivered
+		record.SettledAt = time.Now()
+	}
+	return true
+}
+
+// ParseAck returns the acknowledged message ID of a client message
+func ParseAck(message WebSocketMessage) (string, bool) {
+	if message.Type != MessageTypeAck {
+		return "", false
+	}
+	id, ok := message.Data["messageId"].(string)
+	return id, ok && id != ""
+}
+
+// AckHandler returns a MessageHandler for MessageTypeAck, for use with
+// RegisterHandler. Acks for messages sent by another instance are relayed
+// to it when the manager supports that.
+func (s *ReliableSender) AckHandler() MessageHandler {
+	return func(conn *WebSocketConnection, message WebSocketMessage) {
+		id, ok := ParseAck(message)
+		if !ok || s.HandleAck(conn.UserID, conn.UserType, id) {
+			return
+		}
+		if relay, ok := s.manager.(ackRelay); ok {
+			relay.RelayAck(conn.UserID, conn.UserType, id)
+		}
+	}
+}
+
+// CreateAckMessage creates the ack a client sends for messageID
+func CreateAckMessage(messageID string) WebSocketMessage {
+	return NewPayloadMessage(AckPayload{MessageID: messageID})
+}
+
+// Status returns the delivery record of a message
+func (s *ReliableSender) Status(messageID string) (DeliveryRecord, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[messageID]
+	if !ok {
+		return DeliveryRecord{}, false
+	}
+	return *record, true
+}
+
+// Start resends unacknowledged messages until ctx is cancelled
+func (s *ReliableSender) Start(ctx context.Context) {
+	interval := s.config.RetryInterval / 2
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.retry(now)
+			}
+		}
+	}()
+}
+
+// retry resends due messages, gives up on exhausted ones and prunes
+// settled records past retention
+func (s *ReliableSender) retry(now time.Time) {
+	var resend, failed []DeliveryRecord
+
+	s.mu.Lock()
+	for id, record := range s.records {
+		switch {
+		case record.Status != DeliveryPending:
+			if now.Sub(record.SettledAt) > s.config.StatusRetention {
+				delete(s.records, id)
+			}
+		case now.Sub(record.LastAttempt) < s.config.RetryInterval:
+		case record.Attempts >= s.config.MaxAttempts:
+			record.Status = DeliveryFailed
+			record.SettledAt = now
+			failed = append(failed, *record)
+		default:
+			record.Attempts++
+			record.LastAttempt = now
+			resend = append(resend, *record)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, record := range resend {
+		if err := s.manager.SendMessage(record.UserID, record.UserType, record.Message); err != nil {
+			log.Printf("⚠️ Failed to resend %s to %s:%s: %v", record.MessageID, record.UserType, record.UserID, err)
+		}
+	}
+	for _, record := range failed {
+		log.Printf("⚠️ Message %s (%s) to %s:%s undelivered after %d attempts", record.MessageID, record.Message.Type, record.UserType, record.UserID, record.Attempts)
+		if s.config.OnUndelivered != nil {
+			s.config.OnUndelivered(record)
+		}
+	}
+}
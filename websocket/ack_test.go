@@ -0,0 +1,53 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAckReceivedByAnotherInstanceSettlesDelivery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	podA := NewDistributedWebSocketManager(client, DistributedConfig{})
+	podB := NewDistributedWebSocketManager(client, DistributedConfig{})
+	podA.Start(ctx)
+	podB.Start(ctx)
+	senderA := NewReliableSender(podA, AckConfig{})
+	senderB := NewReliableSender(podB, AckConfig{})
+
+	// pub/sub subscriptions are asynchronous; wait until both are active
+	waitFor(t, func() bool { return mr.PubSubNumSub(DefaultFanoutChannel)[DefaultFanoutChannel] == 2 })
+
+	id, err := senderA.Send("rider-1", UserTypeRider, WebSocketMessage{Type: MessageTypeDriverAssigned})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	// the rider is connected to pod B, which receives the ack
+	conn := &WebSocketConnection{UserID: "rider-1", UserType: UserTypeRider}
+	senderB.AckHandler()(conn, CreateAckMessage(id))
+
+	waitFor(t, func() bool {
+		record, ok := senderA.Status(id)
+		return ok && record.Status == DeliveryDelivered
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before deadline")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
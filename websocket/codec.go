@@ -0,0 +1,148 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Subprotocols negotiated at upgrade to select a connection's codec
+const (
+	SubprotocolJSON    = "motocabz.json.v1"
+	SubprotocolMsgpack = "motocabz.msgpack.v1"
+)
+
+// MessageCodec encodes WebSocket messages for the wire
+type MessageCodec interface {
+	// Subprotocol is the Sec-WebSocket-Protocol value selecting the codec
+	Subprotocol() string
+	// FrameType is websocket.TextMessage or websocket.BinaryMessage
+	FrameType() int
+	Encode(message WebSocketMessage) ([]byte, error)
+	Decode(data []byte) (WebSocketMessage, error)
+}
+
+// JSONMessageCodec is the default text protocol
+type JSONMessageCodec struct{}
+
+// Subprotocol returns SubprotocolJSON
+func (JSONMessageCodec) Subprotocol() string { return SubprotocolJSON }
+
+// FrameType returns websocket.TextMessage
+func (JSONMessageCodec) FrameType() int { return websocket.TextMessage }
+
+// Encode marshals message as JSON
+func (JSONMessageCodec) Encode(message WebSocketMessage) ([]byte, error) {
+	return json.Marshal(message)
+}
+
+// Decode unmarshals a JSON message
+func (JSONMessageCodec) Decode(data []byte) (WebSocketMessage, error) {
+	var message WebSocketMessage
+	err := json.Unmarshal(data, &message)
+	return message, err
+}
+
+// MsgpackMessageCodec sends messages as binary MessagePack frames using the
+// JSON field names, so a client decodes the same object shape it would get
+// from the JSON codec. Payload data is already reduced to JSON types by
+// NewPayloadMessage; numbers are packed in their smallest form, which keeps
+// high-frequency messages such as location updates well under their JSON size.
+type MsgpackMessageCodec struct{}
+
+// Subprotocol returns SubprotocolMsgpack
+func (MsgpackMessageCodec) Subprotocol() string { return SubprotocolMsgpack }
+
+// FrameType returns websocket.BinaryMessage
+func (MsgpackMessageCodec) FrameType() int { return websocket.BinaryMessage }
+
+// Encode marshals message as MessagePack
+func (MsgpackMessageCodec) Encode(message WebSocketMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	enc.UseCompactFloats(true)
+	if err := enc.Encode(message); err != nil {
+		return nil, fmt.Errorf("failed to encode msgpack message: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode unmarshals a MessagePack message
+func (MsgpackMessageCodec) Decode(data []byte) (WebSocketMessage, error) {
+	var message WebSocketMessage
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	if err := dec.Decode(&message); err != nil {
+		return WebSocketMessage{}, fmt.Errorf("failed to decode msgpack message: %w", err)
+	}
+	return message, nil
+}
+
+// NewUpgrader returns an upgrader offering codecs as subprotocols, in order
+// of preference after the client's, with optional permessage-deflate
+func NewUpgrader(compression bool, codecs ...MessageCodec) websocket.Upgrader {
+	upgrader := WebSocketUpgrader
+	upgrader.EnableCompression = compression
+	for _, codec := range codecs {
+		upgrader.Subprotocols = append(upgrader.Subprotocols, codec.Subprotocol())
+	}
+	return upgrader
+}
+
+// codecFor returns the configured codec for a negotiated subprotocol,
+// falling back to JSON for clients that negotiated none
+func (c ManagerConfig) codecFor(subprotocol string) MessageCodec {
+	for _, codec := range c.Codecs {
+		if codec.Subprotocol() == subprotocol {
+			return codec
+		}
+	}
+	return JSONMessageCodec{}
+}
+
+// frameEncoder encodes a broadcast message once per codec
+type frameEncoder struct {
+	message WebSocketMessage
+	frames  map[string]outboundFrame
+	errs    map[string]error
+}
+
+func newFrameEncoder(message WebSocketMessage) *frameEncoder {
+	return &frameEncoder{message: message, frames: make(map[string]outboundFrame), errs: make(map[string]error)}
+}
+
+func (e *frameEncoder) frameFor(codec MessageCodec) (outboundFrame, error) {
+	key := codec.Subprotocol()
+	if frame, ok := e.frames[key]; ok {
+		return frame, nil
+	}
+	if err, ok := e.errs[key]; ok {
+		return outboundFrame{}, err
+	}
+	data, err := codec.Encode(e.message)
+	if err != nil {
+		log.Printf("Failed to encode %s message with %s: %v", e.message.Type, key, err)
+		e.errs[key] = err
+		return outboundFrame{}, err
+	}
+	frame := outboundFrame{messageType: codec.FrameType(), data: data, kind: e.message.Type}
+	e.frames[key] = frame
+	return frame, nil
+}
+
+// enableCompression turns on permessage-deflate writes when the client
+// negotiated it
+func enableCompression(conn *websocket.Conn, level int) {
+	conn.EnableWriteCompression(true)
+	if level != 0 {
+		if err := conn.SetCompressionLevel(level); err != nil {
+			log.Printf("⚠️ Invalid WebSocket compression level %d: %v", level, err)
+		}
+	}
+}
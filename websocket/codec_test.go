@@ -0,0 +1,36 @@
+package websocket
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMsgpackCodecRoundTripsLikeJSON(t *testing.T) {
+	message := NewPayloadMessage(SystemMessagePayload{
+		SystemFlags: []string{"surge_disabled"},
+		Messages:    map[string]string{"surge_disabled": "Surge pricing is paused"},
+	})
+	message.ID = "msg-1"
+
+	encoded, err := MsgpackMessageCodec{}.Encode(message)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	decoded, err := MsgpackMessageCodec{}.Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	viaJSON, _ := json.Marshal(message)
+	viaMsgpack, _ := json.Marshal(decoded)
+	var want, got map[string]interface{}
+	json.Unmarshal(viaJSON, &want)
+	json.Unmarshal(viaMsgpack, &got)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("msgpack round trip = %s, want %s", viaMsgpack, viaJSON)
+	}
+	if len(encoded) >= len(viaJSON) {
+		t.Errorf("msgpack frame is %d bytes, JSON is %d", len(encoded), len(viaJSON))
+	}
+}
@@ -0,0 +1,208 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultFanoutChannel is the pub/sub channel carrying messages between instances
+const DefaultFanoutChannel = "ws:fanout"
+
+// Fan-out operations
+const (
+	fanoutUser = "user"
+	fanoutType = "type"
+	fanoutRoom = "room"
+	fanoutTags = "tags"
+	fanoutAck  = "ack"
+)
+
+// DistributedConfig configures a DistributedWebSocketManager
+type DistributedConfig struct {
+	// Channel carries outbound messages between instances
+	Channel string
+	// PublishTimeout bounds each publish, since IWebSocketManager calls carry
+	// no context
+	PublishTimeout time.Duration
+	// Manager configures the local connections
+	Manager ManagerConfig
+}
+
+// fanoutMessage is published for delivery by every other instance
+type fanoutMessage struct {
+	Source   string           `json:"source"`
+	Op       string           `json:"op"`
+	UserType string           `json:"userType"`
+	UserID   string           `json:"userId,omitempty"`
+	Room     string           `json:"room,omitempty"`
+	Tags     *ConnectionTags  `json:"tags,omitempty"`
+	AckID    string           `json:"ackId,omitempty"`
+	Message  WebSocketMessage `json:"message"`
+}
+
+// DistributedWebSocketManager is an IWebSocketManager for services running
+// several instances. Connections stay on the instance that accepted them;
+// outbound messages are delivered locally and published over Redis pub/sub
+// so the other instances deliver them to their own connections. Connection
+// queries (GetConnection, GetConnectionCount, ...) and BroadcastWhere, whose
+// predicate cannot be shipped to other instances, remain local.
+type DistributedWebSocketManager struct {
+	*WebSocketManager
+	client redis.UniversalClient
+	config DistributedConfig
+	id     string
+
+	ackMu        sync.RWMutex
+	ackListeners []AckListener
+}
+
+// AckListener receives an ack relayed from another instance
+type AckListener func(userID, userType, messageID string)
+
+// NewDistributedWebSocketManager creates a manager that fans out through client
+func NewDistributedWebSocketManager(client redis.UniversalClient, config DistributedConfig) *DistributedWebSocketManager {
+	if config.Channel == "" {
+		config.Channel = DefaultFanoutChannel
+	}
+	if config.PublishTimeout <= 0 {
+		config.PublishTimeout = 2 * time.Second
+	}
+	return &DistributedWebSocketManager{
+		WebSocketManager: NewWebSocketManagerWithConfig(config.Manager),
+		client:           client,
+		config:           config,
+		id:               uuid.NewString(),
+	}
+}
+
+// LocalOnly returns a manager that delivers to this instance's connections
+// only. Callers that already run on every instance (e.g. a subscriber to a
+// shared change feed) use it to avoid sending each message once per instance.
+func LocalOnly(manager IWebSocketManager) IWebSocketManager {
+	if dm, ok := manager.(*DistributedWebSocketManager); ok {
+		return dm.WebSocketManager
+	}
+	return manager
+}
+
+// Start delivers messages published by other instances until ctx is cancelled
+func (dm *DistributedWebSocketManager) Start(ctx context.Context) {
+	pubsub := dm.client.Subscribe(ctx, dm.config.Channel)
+	go func() {
+		defer pubsub.Close()
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				dm.handleFanout(msg.Payload)
+			}
+		}
+	}()
+}
+
+func (dm *DistributedWebSocketManager) handleFanout(payload string) {
+	var fm fanoutMessage
+	if err := json.Unmarshal([]byte(payload), &fm); err != nil {
+		log.Printf("⚠️ Ignoring malformed WebSocket fan-out message: %v", err)
+		return
+	}
+	if fm.Source == dm.id {
+		return
+	}
+	switch fm.Op {
+	case fanoutUser:
+		dm.WebSocketManager.SendMessage(fm.UserID, fm.UserType, fm.Message)
+	case fanoutType:
+		dm.WebSocketManager.BroadcastToType(fm.UserType, fm.Message)
+	case fanoutRoom:
+		dm.WebSocketManager.BroadcastToRoom(fm.Room, fm.Message)
+	case fanoutTags:
+		if fm.Tags != nil {
+			dm.WebSocketManager.BroadcastToTagged(fm.UserType, *fm.Tags, fm.Message)
+		}
+	case fanoutAck:
+		dm.ackMu.RLock()
+		listeners := dm.ackListeners
+		dm.ackMu.RUnlock()
+		for _, fn := range listeners {
+			fn(fm.UserID, fm.UserType, fm.AckID)
+		}
+	}
+}
+
+// RelayAck publishes a client ack for the instance that sent the message
+func (dm *DistributedWebSocketManager) RelayAck(userID, userType, messageID string) {
+	dm.publish(fanoutMessage{Op: fanoutAck, UserType: userType, UserID: userID, AckID: messageID})
+}
+
+// OnRelayedAck registers fn for acks relayed by other instances
+func (dm *DistributedWebSocketManager) OnRelayedAck(fn AckListener) {
+	dm.ackMu.Lock()
+	defer dm.ackMu.Unlock()
+	dm.ackListeners = append(dm.ackListeners, fn)
+}
+
+// publish hands a message to the other instances; failures are logged since
+// local delivery has already happened
+func (dm *DistributedWebSocketManager) publish(fm fanoutMessage) error {
+	fm.Source = dm.id
+	payload, err := json.Marshal(fm)
+	if err != nil {
+		log.Printf("Failed to marshal WebSocket fan-out message: %v", err)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dm.config.PublishTimeout)
+	defer cancel()
+	if err := dm.client.Publish(ctx, dm.config.Channel, payload).Err(); err != nil {
+		log.Printf("⚠️ Failed to publish WebSocket message for %s:%s: %v", fm.UserType, fm.UserID, err)
+		return err
+	}
+	return nil
+}
+
+// SendMessage delivers to the user's local connection, or publishes the
+// message for the instance holding it
+func (dm *DistributedWebSocketManager) SendMessage(userID, userType string, message WebSocketMessage) error {
+	if dm.WebSocketManager.IsConnected(userID, userType) {
+		return dm.WebSocketManager.SendMessage(userID, userType, message)
+	}
+	return dm.publish(fanoutMessage{Op: fanoutUser, UserType: userType, UserID: userID, Message: message})
+}
+
+// BroadcastToType delivers to local connections of userType and publishes
+// the message for every other instance
+func (dm *DistributedWebSocketManager) BroadcastToType(userType string, message WebSocketMessage) {
+	dm.WebSocketManager.BroadcastToType(userType, message)
+	dm.publish(fanoutMessage{Op: fanoutType, UserType: userType, Message: message})
+}
+
+// BroadcastToUser sends a message to a specific user (alias for SendMessage for consistency)
+func (dm *DistributedWebSocketManager) BroadcastToUser(userType, userID string, message WebSocketMessage) {
+	dm.SendMessage(userID, userType, message)
+}
+
+// BroadcastToRoom delivers to local members of room and publishes the
+// message for every other instance
+func (dm *DistributedWebSocketManager) BroadcastToRoom(room string, message WebSocketMessage) {
+	dm.WebSocketManager.BroadcastToRoom(room, message)
+	dm.publish(fanoutMessage{Op: fanoutRoom, Room: room, Message: message})
+}
+
+// BroadcastToTagged delivers to local connections matching tags and
+// publishes the message for every other instance
+func (dm *DistributedWebSocketManager) BroadcastToTagged(userType string, tags ConnectionTags, message WebSocketMessage) {
+	dm.WebSocketManager.BroadcastToTagged(userType, tags, message)
+	dm.publish(fanoutMessage{Op: fanoutTags, UserType: userType, Tags: &tags, Message: message})
+}
@@ -0,0 +1,165 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+)
+
+// MessageTypeRateLimited warns a client that its messages are being dropped
+const MessageTypeRateLimited = "rate_limited"
+
+// InboundRate is a token bucket rate
+type InboundRate struct {
+	PerSecond float64
+	Burst     int
+}
+
+func (r InboundRate) enabled() bool {
+	return r.PerSecond > 0
+}
+
+// InboundLimitConfig limits messages read from each connection. A message
+// over either its connection or its type limit is a violation and dropped;
+// repeated violations escalate to a warning, a temporary mute and finally a
+// disconnect. The zero value disables limiting.
+type InboundLimitConfig struct {
+	PerConnection InboundRate
+	// PerType limits individual message types, e.g. location updates
+	PerType map[string]InboundRate
+	// WarnAfter, MuteAfter and DisconnectAfter are violation counts within
+	// ViolationWindow; 0 disables the step
+	WarnAfter       int
+	MuteAfter       int
+	DisconnectAfter int
+	// MuteFor is how long every message from a muted connection is dropped
+	MuteFor         time.Duration
+	ViolationWindow time.Duration
+}
+
+// DefaultInboundLimitConfig returns limits suited to rider and driver apps
+func DefaultInboundLimitConfig() InboundLimitConfig {
+	return InboundLimitConfig{
+		PerConnection: InboundRate{PerSecond: 20, Burst: 40},
+		PerType: map[string]InboundRate{
+			MessageTypeDriverLocation: {PerSecond: 2, Burst: 5},
+			MessageTypeChatTyping:     {PerSecond: 1, Burst: 3},
+		},
+		WarnAfter:       1,
+		MuteAfter:       20,
+		DisconnectAfter: 100,
+		MuteFor:         10 * time.Second,
+		ViolationWindow: time.Minute,
+	}
+}
+
+func (c InboundLimitConfig) enabled() bool {
+	return c.PerConnection.enabled() || len(c.PerType) > 0
+}
+
+// inboundVerdict is the outcome of checking an inbound message
+type inboundVerdict int
+
+const (
+	inboundAllow inboundVerdict = iota
+	inboundDrop
+	inboundWarn
+	inboundMute
+	inboundDisconnect
+)
+
+// tokenBucket is a token bucket refilled lazily on use
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(rate InboundRate, now time.Time) bool {
+	burst := float64(max(rate.Burst, 1))
+	if b.last.IsZero() {
+		b.tokens = burst
+	} else {
+		b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*rate.PerSecond)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// inboundLimiter applies InboundLimitConfig to one connection; only the
+// read pump uses it, the mutex guards against handlers calling in
+type inboundLimiter struct {
+	mu          sync.Mutex
+	config      InboundLimitConfig
+	connection  tokenBucket
+	types       map[string]*tokenBucket
+	violations  int
+	windowStart time.Time
+	mutedUntil  time.Time
+}
+
+func newInboundLimiter(config InboundLimitConfig) *inboundLimiter {
+	if !config.enabled() {
+		return nil
+	}
+	if config.ViolationWindow <= 0 {
+		config.ViolationWindow = time.Minute
+	}
+	return &inboundLimiter{config: config, types: make(map[string]*tokenBucket)}
+}
+
+// check records an inbound message of messageType and returns what to do with it
+func (l *inboundLimiter) check(messageType string, now time.Time) inboundVerdict {
+	if l == nil {
+		return inboundAllow
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.mutedUntil) {
+		return inboundDrop
+	}
+
+	allowed := true
+	if l.config.PerConnection.enabled() && !l.connection.take(l.config.PerConnection, now) {
+		allowed = false
+	}
+	if rate, ok := l.config.PerType[messageType]; ok && rate.enabled() {
+		bucket := l.types[messageType]
+		if bucket == nil {
+			bucket = &tokenBucket{}
+			l.types[messageType] = bucket
+		}
+		if !bucket.take(rate, now) {
+			allowed = false
+		}
+	}
+	if allowed {
+		return inboundAllow
+	}
+
+	if now.Sub(l.windowStart) > l.config.ViolationWindow {
+		l.windowStart, l.violations = now, 0
+	}
+	l.violations++
+	switch {
+	case l.config.DisconnectAfter > 0 && l.violations >= l.config.DisconnectAfter:
+		return inboundDisconnect
+	case l.config.MuteAfter > 0 && l.violations == l.config.MuteAfter:
+		l.mutedUntil = now.Add(l.config.MuteFor)
+		return inboundMute
+	case l.config.WarnAfter > 0 && l.violations == l.config.WarnAfter:
+		return inboundWarn
+	}
+	return inboundDrop
+}
+
+// CreateRateLimitedMessage creates the warning sent to a throttled client
+func CreateRateLimitedMessage(messageType string, mutedFor time.Duration) WebSocketMessage {
+	return NewPayloadMessage(RateLimitedPayload{
+		ThrottledType: messageType,
+		MutedForMs:    mutedFor.Milliseconds(),
+	})
+}
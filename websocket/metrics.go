@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+)
+
+// WebSocket metrics exported through the observability metrics provider:
+//
+//	websocket_connections{user_type}                                  gauge
+//	websocket_messages_sent_total{user_type,message_type}             counter
+//	websocket_messages_received_total{user_type,message_type}         counter
+//	websocket_messages_dropped_total{user_type,message_type,reason}   counter
+//	websocket_reconnects_total{user_type}                             counter
+//	websocket_auth_failures_total{user_type,reason}                   counter
+//	websocket_send_latency_seconds{user_type,message_type}            histogram
+//	websocket_send_queue_depth{user_type}                             histogram
+
+// trackConnection adjusts the connection gauge of userType
+func (wm *WebSocketManager) trackConnection(userType string, delta int64) {
+	counter, _ := wm.typeCounts.LoadOrStore(userType, new(atomic.Int64))
+	count := counter.(*atomic.Int64).Add(delta)
+	wm.settings().Metrics.SetGauge("websocket_connections", float64(count), metrics.Labels{"user_type": userType})
+}
+
+// RecordAuthFailure counts a rejected WebSocket authentication; handlers
+// call it before refusing the upgrade
+func (wm *WebSocketManager) RecordAuthFailure(userType, reason string) {
+	wm.settings().Metrics.IncCounter("websocket_auth_failures_total", 1, metrics.Labels{"user_type": userType, "reason": reason})
+}
+
+func (c *WebSocketConnection) recordSent(frame outboundFrame) {
+	labels := metrics.Labels{"user_type": c.UserType, "message_type": frame.kind}
+	c.config.Metrics.IncCounter("websocket_messages_sent_total", 1, labels)
+	c.config.Metrics.ObserveHistogram("websocket_send_latency_seconds", time.Since(frame.queuedAt).Seconds(), labels)
+}
+
+// unknownMessageType labels inbound messages of unrecognised types
+const unknownMessageType = "unknown"
+
+// messageTypeLabel bounds the message_type label of inbound metrics: the type
+// is client supplied, so only types with a registered handler or a known
+// payload are used and anything else is counted as unknown
+func (wm *WebSocketManager) messageTypeLabel(messageType string) string {
+	if messageType == handlerKeyDefault {
+		return unknownMessageType
+	}
+	if _, ok := wm.handlers.Load(messageType); ok {
+		return messageType
+	}
+	for _, p := range payloadTypes {
+		if p.MessageType() == messageType {
+			return messageType
+		}
+	}
+	return unknownMessageType
+}
+
+func (c *WebSocketConnection) recordReceived(messageType string) {
+	c.config.Metrics.IncCounter("websocket_messages_received_total", 1, metrics.Labels{"user_type": c.UserType, "message_type": messageType})
+}
+
+func (c *WebSocketConnection) recordDropped(messageType, reason string) {
+	c.config.Metrics.IncCounter("websocket_messages_dropped_total", 1, metrics.Labels{"user_type": c.UserType, "message_type": messageType, "reason": reason})
+}
@@ -0,0 +1,271 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/mihirk-khode/motocabz-common/location"
+	"github.com/mihirk-khode/motocabz-common/util/timefmt"
+)
+
+// Payload is the typed data of a message type
+type Payload interface {
+	MessageType() string
+}
+
+// NewPayloadMessage creates a message of p's type carrying p as its data
+func NewPayloadMessage(p Payload) WebSocketMessage {
+	return CreateWebSocketMessage(p.MessageType(), payloadData(p))
+}
+
+// ParsePayload decodes a message's data into T after checking its type
+func ParsePayload[T Payload](message WebSocketMessage) (T, error) {
+	var zero T
+	if message.Type != zero.MessageType() {
+		return zero, fmt.Errorf("%w: expected %s, got %s", ErrInvalidPayload, zero.MessageType(), message.Type)
+	}
+	return DecodePayload[T](message)
+}
+
+// payloadData converts p to the map carried by WebSocketMessage
+func payloadData(p Payload) map[string]interface{} {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		log.Printf("⚠️ Failed to encode %s payload: %v", p.MessageType(), err)
+		return map[string]interface{}{}
+	}
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &data); err != nil {
+		log.Printf("⚠️ Failed to encode %s payload: %v", p.MessageType(), err)
+	}
+	return data
+}
+
+// ConnectionEstablishedPayload confirms a successful registration
+type ConnectionEstablishedPayload struct {
+	UserID   string `json:"userId"`
+	UserType string `json:"userType"`
+	Channel  string `json:"channel"`
+}
+
+// PingPayload is sent by either side to check liveness
+type PingPayload struct {
+	Timestamp int64 `json:"timestamp"`
+}
+
+// PongPayload answers a ping
+type PongPayload struct {
+	Timestamp int64 `json:"timestamp"`
+}
+
+// SystemMessagePayload carries operator notices and system flags
+type SystemMessagePayload struct {
+	Message string `json:"message,omitempty"`
+	// SystemFlags is always sent with flag updates; an empty list clears them
+	SystemFlags []string          `json:"systemFlags"`
+	Messages    map[string]string `json:"messages,omitempty"`
+}
+
+// ErrorPayload is the data of an error message; the text is in WebSocketMessage.Error
+type ErrorPayload struct {
+	// Type and ID identify the message that failed, when known
+	Type string `json:"type,omitempty"`
+	ID   string `json:"id,omitempty"`
+}
+
+// BiddingStartedPayload opens a bidding session to drivers
+type BiddingStartedPayload struct {
+	TripID          string            `json:"tripId"`
+	SessionID       string            `json:"sessionId"`
+	Pickup          location.Location `json:"pickup"`
+	Dropoff         location.Location `json:"dropoff"`
+	PickupAddress   string            `json:"pickupAddress,omitempty"`
+	DropoffAddress  string            `json:"dropoffAddress,omitempty"`
+	DistanceKm      float64           `json:"distanceKm"`
+	BaseFare        float64           `json:"baseFare"`
+	VehicleClass    string            `json:"vehicleClass,omitempty"`
+	DurationSeconds int               `json:"durationSeconds"`
+	ExpiresAt       timefmt.Time      `json:"expiresAt"`
+}
+
+// BidReceivedPayload tells the rider about a driver's bid
+type BidReceivedPayload struct {
+	TripID       string  `json:"tripId"`
+	SessionID    string  `json:"sessionId"`
+	BidID        string  `json:"bidId"`
+	DriverID     string  `json:"driverId"`
+	DriverName   string  `json:"driverName,omitempty"`
+	DriverRating float64 `json:"driverRating,omitempty"`
+	Amount       float64 `json:"amount"`
+	EtaMinutes   float64 `json:"etaMinutes"`
+}
+
+// BiddingEndedPayload closes a bidding session
+type BiddingEndedPayload struct {
+	TripID       string `json:"tripId"`
+	SessionID    string `json:"sessionId"`
+	Reason       string `json:"reason"`
+	WinningBidID string `json:"winningBidId,omitempty"`
+}
+
+// DriverAssignedPayload tells the rider and driver a trip was matched
+type DriverAssignedPayload struct {
+	TripID         string             `json:"tripId"`
+	DriverID       string             `json:"driverId"`
+	DriverName     string             `json:"driverName"`
+	DriverPhone    string             `json:"driverPhone,omitempty"`
+	DriverRating   float64            `json:"driverRating,omitempty"`
+	VehicleNumber  string             `json:"vehicleNumber"`
+	VehicleModel   string             `json:"vehicleModel,omitempty"`
+	Fare           float64            `json:"fare"`
+	EtaMinutes     float64            `json:"etaMinutes"`
+	DriverLocation *location.Location `json:"driverLocation,omitempty"`
+}
+
+// TimerUpdatePayload reports the time left in a bidding session
+type TimerUpdatePayload struct {
+	TripID           string       `json:"tripId"`
+	SessionID        string       `json:"sessionId"`
+	RemainingSeconds int          `json:"remainingSeconds"`
+	ExpiresAt        timefmt.Time `json:"expiresAt"`
+}
+
+// TripNotificationPayload is a human readable notice about a trip
+type TripNotificationPayload struct {
+	TripID  string `json:"tripId"`
+	Title   string `json:"title"`
+	Message string `json:"message"`
+}
+
+// TripStatusUpdatePayload reports a trip status transition
+type TripStatusUpdatePayload struct {
+	TripID         string             `json:"tripId"`
+	Status         string             `json:"status"`
+	PreviousStatus string             `json:"previousStatus,omitempty"`
+	Reason         string             `json:"reason,omitempty"`
+	DriverLocation *location.Location `json:"driverLocation,omitempty"`
+	EtaMinutes     *float64           `json:"etaMinutes,omitempty"`
+	UpdatedAt      timefmt.Time       `json:"updatedAt"`
+}
+
+// DriverLocationPayload is a driver position pushed to a trip's rider
+type DriverLocationPayload struct {
+	DriverID   string            `json:"driverId"`
+	TripID     string            `json:"tripId,omitempty"`
+	Location   location.Location `json:"location"`
+	Heading    float64           `json:"heading,omitempty"`
+	SpeedKmh   float64           `json:"speedKmh,omitempty"`
+	EtaMinutes *float64          `json:"etaMinutes,omitempty"`
+	RecordedAt timefmt.Time      `json:"recordedAt"`
+}
+
+// NoDriverFoundPayload tells the rider matching gave up
+type NoDriverFoundPayload struct {
+	TripID string `json:"tripId"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ChatMessagePayload is a chat message between trip participants
+type ChatMessagePayload struct {
+	ID            string       `json:"id"`
+	TripID        string       `json:"tripId"`
+	SenderID      string       `json:"senderId"`
+	SenderType    string       `json:"senderType"`
+	RecipientID   string       `json:"recipientId"`
+	RecipientType string       `json:"recipientType"`
+	Text          string       `json:"text"`
+	SentAt        timefmt.Time `json:"sentAt"`
+}
+
+// ChatTypingPayload reports that a participant started or stopped typing
+type ChatTypingPayload struct {
+	TripID     string `json:"tripId"`
+	SenderID   string `json:"senderId"`
+	SenderType string `json:"senderType"`
+	Typing     bool   `json:"typing"`
+}
+
+// EmergencyAlert is the SOS snapshot pushed to admins
+type EmergencyAlert struct {
+	ID              string             `json:"id"`
+	TripID          string             `json:"tripId,omitempty"`
+	UserID          string             `json:"userId"`
+	UserType        string             `json:"userType"`
+	Message         string             `json:"message,omitempty"`
+	Location        *location.Location `json:"location,omitempty"`
+	Status          string             `json:"status"`
+	RaisedAt        timefmt.Time       `json:"raisedAt"`
+	AcknowledgedBy  string             `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt  *timefmt.Time      `json:"acknowledgedAt,omitempty"`
+	ResolvedAt      *timefmt.Time      `json:"resolvedAt,omitempty"`
+	EscalationLevel int                `json:"escalationLevel"`
+}
+
+// EmergencyAlertPayload notifies admins of a new or escalated SOS
+type EmergencyAlertPayload struct {
+	Alert EmergencyAlert `json:"alert"`
+}
+
+// AckPayload acknowledges a reliably delivered message
+type AckPayload struct {
+	MessageID string `json:"messageId"`
+}
+
+// ReconnectPayload asks the client to reconnect, e.g. during a deploy
+type ReconnectPayload struct {
+	Reason       string `json:"reason"`
+	RetryAfterMs int64  `json:"retryAfterMs"`
+}
+
+// RateLimitedPayload warns a client that its messages are being throttled
+type RateLimitedPayload struct {
+	ThrottledType string `json:"messageType"`
+	MutedForMs    int64  `json:"mutedForMs,omitempty"`
+}
+
+// MessageType implementations tie each payload to its message type
+func (ConnectionEstablishedPayload) MessageType() string { return MessageTypeConnectionEstablished }
+func (PingPayload) MessageType() string                  { return MessageTypePing }
+func (PongPayload) MessageType() string                  { return MessageTypePong }
+func (SystemMessagePayload) MessageType() string         { return MessageTypeSystemMessage }
+func (ErrorPayload) MessageType() string                 { return MessageTypeError }
+func (BiddingStartedPayload) MessageType() string        { return MessageTypeBiddingStarted }
+func (BidReceivedPayload) MessageType() string           { return MessageTypeBidReceived }
+func (BiddingEndedPayload) MessageType() string          { return MessageTypeBiddingEnded }
+func (DriverAssignedPayload) MessageType() string        { return MessageTypeDriverAssigned }
+func (TimerUpdatePayload) MessageType() string           { return MessageTypeTimerUpdate }
+func (TripNotificationPayload) MessageType() string      { return MessageTypeTripNotification }
+func (TripStatusUpdatePayload) MessageType() string      { return MessageTypeTripStatusUpdate }
+func (DriverLocationPayload) MessageType() string        { return MessageTypeDriverLocation }
+func (NoDriverFoundPayload) MessageType() string         { return MessageTypeNoDriverFound }
+func (ChatMessagePayload) MessageType() string           { return MessageTypeChatMessage }
+func (ChatTypingPayload) MessageType() string            { return MessageTypeChatTyping }
+func (EmergencyAlertPayload) MessageType() string        { return MessageTypeEmergencyAlert }
+func (AckPayload) MessageType() string                   { return MessageTypeAck }
+func (ReconnectPayload) MessageType() string             { return MessageTypeReconnect }
+func (RateLimitedPayload) MessageType() string           { return MessageTypeRateLimited }
+
+// payloadTypes lists the payload of every message type, used for schemas
+var payloadTypes = []Payload{
+	ConnectionEstablishedPayload{},
+	PingPayload{},
+	PongPayload{},
+	SystemMessagePayload{},
+	ErrorPayload{},
+	BiddingStartedPayload{},
+	BidReceivedPayload{},
+	BiddingEndedPayload{},
+	DriverAssignedPayload{},
+	TimerUpdatePayload{},
+	TripNotificationPayload{},
+	TripStatusUpdatePayload{},
+	DriverLocationPayload{},
+	NoDriverFoundPayload{},
+	ChatMessagePayload{},
+	ChatTypingPayload{},
+	EmergencyAlertPayload{},
+	AckPayload{},
+	ReconnectPayload{},
+	RateLimitedPayload{},
+}
@@ -0,0 +1,255 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/events"
+	rediscommon "github.com/mihirk-khode/motocabz-common/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+// PresenceConfig configures the presence service
+type PresenceConfig struct {
+	KeyPrefix string
+	// InstanceID identifies this process; defaults to the hostname
+	InstanceID string
+	// TTL expires the online marker of users whose instance stopped
+	// heartbeating, e.g. after a crash
+	TTL time.Duration
+	// HeartbeatInterval refreshes the markers of local connections
+	HeartbeatInterval time.Duration
+	// LastSeenRetention is how long LastSeen stays queryable after disconnect
+	LastSeenRetention time.Duration
+	// Topic receives online/offline events for drivers
+	Topic string
+}
+
+// DefaultPresenceConfig returns the default presence settings
+func DefaultPresenceConfig() PresenceConfig {
+	return PresenceConfig{
+		KeyPrefix:         "presence",
+		TTL:               90 * time.Second,
+		HeartbeatInterval: 30 * time.Second,
+		LastSeenRetention: 7 * 24 * time.Hour,
+		Topic:             common.TopicDriverEvents,
+	}
+}
+
+// PresenceEvent is the payload of driver online/offline events
+type PresenceEvent struct {
+	UserID     string    `json:"userId"`
+	UserType   string    `json:"userType"`
+	InstanceID string    `json:"instanceId"`
+	At         time.Time `json:"at"`
+}
+
+// Presence records which users are connected and on which instance. Attach
+// it to a WebSocketManager with AddHook and run Start for heartbeats.
+// Drivers coming online or going offline are published as events; an
+// instance crash only expires its markers after TTL, without events.
+type Presence struct {
+	client    redis.UniversalClient
+	scripts   *rediscommon.ScriptManager
+	publisher events.Publisher
+	manager   *WebSocketManager
+	config    PresenceConfig
+}
+
+// NewPresence creates a presence service for manager's connections;
+// publisher may be nil to disable events
+func NewPresence(client redis.UniversalClient, manager *WebSocketManager, publisher events.Publisher, config PresenceConfig) *Presence {
+	defaults := DefaultPresenceConfig()
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = defaults.KeyPrefix
+	}
+	if config.InstanceID == "" {
+		config.InstanceID, _ = os.Hostname()
+		if config.InstanceID == "" {
+			config.InstanceID = uuid.NewString()
+		}
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.HeartbeatInterval <= 0 || config.HeartbeatInterval >= config.TTL {
+		config.HeartbeatInterval = config.TTL / 3
+	}
+	if config.LastSeenRetention <= 0 {
+		config.LastSeenRetention = defaults.LastSeenRetention
+	}
+	if config.Topic == "" {
+		config.Topic = defaults.Topic
+	}
+
+	p := &Presence{
+		client:    client,
+		scripts:   rediscommon.NewScriptManager(client),
+		publisher: publisher,
+		manager:   manager,
+		config:    config,
+	}
+	manager.AddHook(p)
+	return p
+}
+
+func (p *Presence) onlineKey(userType, userID string) string {
+	return fmt.Sprintf("%s:online:%s:%s", p.config.KeyPrefix, userType, userID)
+}
+
+func (p *Presence) lastSeenKey(userType, userID string) string {
+	return fmt.Sprintf("%s:lastseen:%s:%s", p.config.KeyPrefix, userType, userID)
+}
+
+// hookContext bounds the Redis calls made from connection hooks
+func hookContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), 2*time.Second)
+}
+
+// Connected marks the user online on this instance
+func (p *Presence) Connected(conn *WebSocketConnection) {
+	ctx, cancel := hookContext()
+	defer cancel()
+
+	now := time.Now()
+	_, err := p.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, p.onlineKey(conn.UserType, conn.UserID), p.config.InstanceID, p.config.TTL)
+		pipe.Set(ctx, p.lastSeenKey(conn.UserType, conn.UserID), now.UnixMilli(), p.config.LastSeenRetention)
+		return nil
+	})
+	if err != nil {
+		log.Printf("⚠️ Failed to record presence of %s:%s: %v", conn.UserType, conn.UserID, err)
+		return
+	}
+	p.publish(ctx, common.EventTypeDriverOnline, conn, now)
+}
+
+// Disconnected marks the user offline unless they reconnected elsewhere
+func (p *Presence) Disconnected(conn *WebSocketConnection) {
+	ctx, cancel := hookContext()
+	defer cancel()
+
+	now := time.Now()
+	// only the owning instance clears the marker, so a late disconnect of a
+	// stale socket does not hide a reconnect elsewhere
+	removed, err := p.scripts.RunInt(ctx, rediscommon.ScriptCompareAndDelete, []string{p.onlineKey(conn.UserType, conn.UserID)}, p.config.InstanceID)
+	if err != nil {
+		log.Printf("⚠️ Failed to clear presence of %s:%s: %v", conn.UserType, conn.UserID, err)
+		return
+	}
+	if err := p.client.Set(ctx, p.lastSeenKey(conn.UserType, conn.UserID), now.UnixMilli(), p.config.LastSeenRetention).Err(); err != nil {
+		log.Printf("⚠️ Failed to record last seen of %s:%s: %v", conn.UserType, conn.UserID, err)
+	}
+	if removed == 1 {
+		p.publish(ctx, common.EventTypeDriverOffline, conn, now)
+	}
+}
+
+// publish emits a presence event for drivers
+func (p *Presence) publish(ctx context.Context, eventType string, conn *WebSocketConnection, at time.Time) {
+	if p.publisher == nil || conn.UserType != UserTypeDriver {
+		return
+	}
+	event, err := events.NewEvent(eventType, common.AggregateTypeDriver, conn.UserID, PresenceEvent{
+		UserID:     conn.UserID,
+		UserType:   conn.UserType,
+		InstanceID: p.config.InstanceID,
+		At:         at,
+	})
+	if err != nil {
+		log.Printf("⚠️ Failed to build %s event: %v", eventType, err)
+		return
+	}
+	if err := p.publisher.Publish(ctx, p.config.Topic, event); err != nil {
+		log.Printf("⚠️ Failed to publish %s for %s: %v", eventType, conn.UserID, err)
+	}
+}
+
+// Start refreshes the presence of local connections until ctx is cancelled
+func (p *Presence) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.config.HeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := p.heartbeat(ctx); err != nil {
+					log.Printf("⚠️ Presence heartbeat failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func (p *Presence) heartbeat(ctx context.Context) error {
+	var connections []*WebSocketConnection
+	p.manager.connections.Range(func(key, value interface{}) bool {
+		connections = append(connections, value.(*WebSocketConnection))
+		return true
+	})
+	if len(connections) == 0 {
+		return nil
+	}
+
+	// Markers are only extended while this instance owns them: a half-open
+	// socket lingering after the user reconnected elsewhere must not take the
+	// marker back, or its eventual disconnect would report a false offline.
+	now := time.Now().UnixMilli()
+	_, err := p.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, conn := range connections {
+			if _, err := p.scripts.Queue(ctx, pipe, rediscommon.ScriptCompareAndExpire,
+				[]string{p.onlineKey(conn.UserType, conn.UserID)}, p.config.InstanceID, p.config.TTL.Milliseconds()); err != nil {
+				return err
+			}
+			pipe.Set(ctx, p.lastSeenKey(conn.UserType, conn.UserID), now, p.config.LastSeenRetention)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refresh presence of %d connections: %w", len(connections), err)
+	}
+	return nil
+}
+
+// IsOnline reports whether the user is connected to any instance
+func (p *Presence) IsOnline(ctx context.Context, userType, userID string) (bool, error) {
+	_, online, err := p.InstanceOf(ctx, userType, userID)
+	return online, err
+}
+
+// InstanceOf returns the instance holding the user's connection
+func (p *Presence) InstanceOf(ctx context.Context, userType, userID string) (string, bool, error) {
+	instance, err := p.client.Get(ctx, p.onlineKey(userType, userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read presence of %s:%s: %w", userType, userID, err)
+	}
+	return instance, true, nil
+}
+
+// LastSeen returns when the user was last connected, within LastSeenRetention
+func (p *Presence) LastSeen(ctx context.Context, userType, userID string) (time.Time, bool, error) {
+	value, err := p.client.Get(ctx, p.lastSeenKey(userType, userID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to read last seen of %s:%s: %w", userType, userID, err)
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to parse last seen of %s:%s: %w", userType, userID, err)
+	}
+	return time.UnixMilli(ms), true, nil
+}
@@ -0,0 +1,100 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/events"
+	"github.com/redis/go-redis/v9"
+)
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, topic string, event events.BaseEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event.Type)
+	return nil
+}
+
+func (p *recordingPublisher) count(eventType string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, e := range p.events {
+		if e == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+// newTestPresence creates the presence service of one instance
+func newTestPresence(t *testing.T, client redis.UniversalClient, instanceID string, publisher events.Publisher) *Presence {
+	t.Helper()
+	manager := NewWebSocketManagerWithConfig(ManagerConfig{})
+	return NewPresence(client, manager, publisher, PresenceConfig{InstanceID: instanceID})
+}
+
+// register stores conn as a live local connection of p's manager
+func register(p *Presence, conn *WebSocketConnection) {
+	p.manager.connections.Store(conn.UserType+":"+conn.UserID, conn)
+	p.Connected(conn)
+}
+
+func TestStaleSocketDoesNotReportOffline(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	publisher := &recordingPublisher{}
+
+	podA := newTestPresence(t, client, "pod-a", publisher)
+	podB := newTestPresence(t, client, "pod-b", publisher)
+
+	stale := &WebSocketConnection{UserID: "driver-1", UserType: UserTypeDriver}
+	register(podA, stale)
+	register(podB, &WebSocketConnection{UserID: "driver-1", UserType: UserTypeDriver})
+
+	// pod A still holds the half-open socket and keeps heartbeating it
+	if err := podA.heartbeat(ctx); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	if instance, _, _ := podA.InstanceOf(ctx, UserTypeDriver, "driver-1"); instance != "pod-b" {
+		t.Fatalf("InstanceOf = %q after stale heartbeat, want pod-b", instance)
+	}
+
+	podA.Disconnected(stale)
+	if online, _ := podB.IsOnline(ctx, UserTypeDriver, "driver-1"); !online {
+		t.Error("driver reported offline after the stale socket closed")
+	}
+	if n := publisher.count(common.EventTypeDriverOffline); n != 0 {
+		t.Errorf("published %d offline events, want 0", n)
+	}
+}
+
+func TestHeartbeatExtendsOwnedMarker(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	p := newTestPresence(t, client, "pod-a", nil)
+	register(p, &WebSocketConnection{UserID: "driver-1", UserType: UserTypeDriver})
+
+	mr.FastForward(p.config.TTL - time.Second)
+	if err := p.heartbeat(ctx); err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	mr.FastForward(2 * time.Second)
+	if online, _ := p.IsOnline(ctx, UserTypeDriver, "driver-1"); !online {
+		t.Error("marker expired despite heartbeat")
+	}
+}
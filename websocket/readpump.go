@@ -0,0 +1,190 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+)
+
+// MessageHandler handles an inbound message from a connection; work it starts
+// should be bound to conn.Context() so it stops when the client goes away
+type MessageHandler func(conn *WebSocketConnection, message WebSocketMessage)
+
+// handlerKeyDefault stores the handler for unregistered message types
+const handlerKeyDefault = ""
+
+// RegisterHandler routes inbound messages of messageType to handler
+func (wm *WebSocketManager) RegisterHandler(messageType string, handler MessageHandler) {
+	if messageType == handlerKeyDefault {
+		return
+	}
+	wm.handlers.Store(messageType, handler)
+}
+
+// SetDefaultHandler handles inbound messages without a registered handler;
+// by default they are logged and dropped
+func (wm *WebSocketManager) SetDefaultHandler(handler MessageHandler) {
+	wm.handlers.Store(handlerKeyDefault, handler)
+}
+
+func (wm *WebSocketManager) handlerFor(messageType string) MessageHandler {
+	if h, ok := wm.handlers.Load(messageType); ok {
+		return h.(MessageHandler)
+	}
+	if h, ok := wm.handlers.Load(handlerKeyDefault); ok {
+		return h.(MessageHandler)
+	}
+	return nil
+}
+
+// Context returns the connection's context, cancelled once the connection is
+// removed or closed; handlers should derive their work from it
+func (c *WebSocketConnection) Context() context.Context {
+	return c.ctx
+}
+
+// LastSeen returns when the client last sent a frame or answered a ping
+func (c *WebSocketConnection) LastSeen() time.Time {
+	return time.Unix(0, c.lastSeen.Load())
+}
+
+func (c *WebSocketConnection) touch() {
+	c.lastSeen.Store(time.Now().UnixNano())
+}
+
+// Serve registers conn and runs its read pump until the client disconnects
+// or stops answering pings, then removes the connection. It blocks, so HTTP
+// handlers call it after upgrading. Inbound messages are dispatched to the
+// handlers registered with RegisterHandler.
+func (wm *WebSocketManager) Serve(userID, userType string, conn *websocket.Conn) {
+	wm.ServeWithTags(userID, userType, ConnectionTags{}, conn)
+}
+
+// ServeWithTags is Serve for a connection carrying targeting tags
+func (wm *WebSocketManager) ServeWithTags(userID, userType string, tags ConnectionTags, conn *websocket.Conn) {
+	connectionID := userType + ":" + userID
+	connection := wm.addConnection(userID, userType, tags, conn)
+	if connection == nil {
+		return
+	}
+	defer func() {
+		wm.removeIfCurrent(connectionID, connection)
+		connection.disconnect()
+	}()
+
+	timeouts := common.GetTimeouts()
+	conn.SetReadLimit(wm.settings().MaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(timeouts.WebSocketPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		connection.touch()
+		return conn.SetReadDeadline(time.Now().Add(timeouts.WebSocketPongTimeout))
+	})
+
+	go wm.StartPingPong(connection)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway, websocket.CloseNoStatusReceived) {
+				log.Printf("WebSocket read failed for %s: %v", connectionID, err)
+			}
+			return
+		}
+		if connection.ctx.Err() != nil {
+			return
+		}
+		connection.touch()
+		conn.SetReadDeadline(time.Now().Add(timeouts.WebSocketPongTimeout))
+		if !wm.dispatch(connection, data) {
+			return
+		}
+	}
+}
+
+// dispatch decodes an inbound frame, applies inbound rate limits and hands
+// it to its handler; it returns false when the connection must be closed
+func (wm *WebSocketManager) dispatch(conn *WebSocketConnection, data []byte) bool {
+	message, err := conn.codec.Decode(data)
+	if err != nil {
+		conn.Send(CreateWebSocketErrorMessage(MessageTypeError, "invalid message format", nil))
+		return true
+	}
+
+	label := wm.messageTypeLabel(message.Type)
+	conn.recordReceived(label)
+	if verdict := conn.limiter.check(message.Type, time.Now()); verdict != inboundAllow {
+		return wm.throttle(conn, message.Type, label, verdict)
+	}
+
+	if message.Type == MessageTypePing {
+		conn.Send(CreatePongMessage())
+		return true
+	}
+
+	handler := wm.handlerFor(message.Type)
+	if handler == nil {
+		log.Printf("⚠️ No handler for WebSocket message type %q from %s:%s", message.Type, conn.UserType, conn.UserID)
+		return true
+	}
+	handler(conn, message)
+	return true
+}
+
+// throttle handles a message over its inbound limit
+func (wm *WebSocketManager) throttle(conn *WebSocketConnection, messageType, label string, verdict inboundVerdict) bool {
+	action := map[inboundVerdict]string{
+		inboundDrop:       "drop",
+		inboundWarn:       "warn",
+		inboundMute:       "mute",
+		inboundDisconnect: "disconnect",
+	}[verdict]
+	conn.config.Metrics.IncCounter("websocket_inbound_rate_limited_total", 1, metrics.Labels{
+		"user_type": conn.UserType, "message_type": label, "action": action,
+	})
+
+	switch verdict {
+	case inboundWarn:
+		conn.Send(CreateRateLimitedMessage(messageType, 0))
+	case inboundMute:
+		log.Printf("⚠️ Muting WebSocket client %s:%s for %s after repeated rate limit violations", conn.UserType, conn.UserID, conn.limiter.config.MuteFor)
+		conn.Send(CreateRateLimitedMessage(messageType, conn.limiter.config.MuteFor))
+	case inboundDisconnect:
+		log.Printf("⚠️ Disconnecting WebSocket client %s:%s for exceeding inbound rate limits", conn.UserType, conn.UserID)
+		conn.Conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "rate limit exceeded"), time.Now().Add(time.Second))
+		return false
+	}
+	return true
+}
+
+// Send encodes a message with the connection's codec and queues it on the
+// write pump
+func (c *WebSocketConnection) Send(message WebSocketMessage) error {
+	frame, err := newFrameEncoder(message).frameFor(c.codec)
+	if err != nil {
+		return err
+	}
+	return c.enqueue(frame)
+}
+
+// Codec returns the codec negotiated for the connection
+func (c *WebSocketConnection) Codec() MessageCodec {
+	return c.codec
+}
+
+// removeIfCurrent removes connectionID only while it still maps to conn, so
+// a stale connection closing does not remove the user's newer one
+func (wm *WebSocketManager) removeIfCurrent(connectionID string, conn *WebSocketConnection) {
+	if wm.connections.CompareAndDelete(connectionID, conn) {
+		conn.stop()
+		wm.leaveAllRooms(connectionID)
+		atomic.AddInt64(&wm.connectionCount, -1)
+		wm.trackConnection(conn.UserType, -1)
+		log.Printf("WebSocket connection removed: %s", connectionID)
+		wm.notifyHooks(func(h ConnectionHook) { h.Disconnected(conn) })
+	}
+}
@@ -0,0 +1,261 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrNotConnected is returned when joining a room without a local connection
+var ErrNotConnected = errors.New("websocket: user is not connected")
+
+// TripRoom returns the room of a trip's rider and driver
+func TripRoom(tripID string) string {
+	return "trip:" + tripID
+}
+
+// BiddingRoom returns the room of a bidding session's participants
+func BiddingRoom(sessionID string) string {
+	return "bidding:" + sessionID
+}
+
+// CityRoom returns the room of every user in a city
+func CityRoom(cityCode string) string {
+	return "city:" + cityCode
+}
+
+// RoomMember identifies a connection in a room
+type RoomMember struct {
+	UserID   string `json:"userId"`
+	UserType string `json:"userType"`
+}
+
+func (m RoomMember) connectionID() string {
+	return m.UserType + ":" + m.UserID
+}
+
+func memberFromConnectionID(connectionID string) RoomMember {
+	userType, userID, _ := strings.Cut(connectionID, ":")
+	return RoomMember{UserID: userID, UserType: userType}
+}
+
+// RoomStore mirrors room membership outside the process so every instance
+// can list a room's members
+type RoomStore interface {
+	Add(ctx context.Context, room string, member RoomMember) error
+	Remove(ctx context.Context, room string, member RoomMember) error
+	Members(ctx context.Context, room string) ([]RoomMember, error)
+}
+
+// rooms tracks local room membership by connection ID in both directions so
+// closing a connection can leave all its rooms
+type rooms struct {
+	mu      sync.RWMutex
+	members map[string]map[string]struct{}
+	joined  map[string]map[string]struct{}
+}
+
+func (r *rooms) join(room, connectionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.members == nil {
+		r.members = make(map[string]map[string]struct{})
+		r.joined = make(map[string]map[string]struct{})
+	}
+	if r.members[room] == nil {
+		r.members[room] = make(map[string]struct{})
+	}
+	if r.joined[connectionID] == nil {
+		r.joined[connectionID] = make(map[string]struct{})
+	}
+	r.members[room][connectionID] = struct{}{}
+	r.joined[connectionID][room] = struct{}{}
+}
+
+func (r *rooms) leave(room, connectionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.members[room], connectionID)
+	if len(r.members[room]) == 0 {
+		delete(r.members, room)
+	}
+	delete(r.joined[connectionID], room)
+	if len(r.joined[connectionID]) == 0 {
+		delete(r.joined, connectionID)
+	}
+}
+
+// leaveAll removes connectionID from every room and returns them
+func (r *rooms) leaveAll(connectionID string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var left []string
+	for room := range r.joined[connectionID] {
+		delete(r.members[room], connectionID)
+		if len(r.members[room]) == 0 {
+			delete(r.members, room)
+		}
+		left = append(left, room)
+	}
+	delete(r.joined, connectionID)
+	return left
+}
+
+func (r *rooms) connectionIDs(room string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := make([]string, 0, len(r.members[room]))
+	for id := range r.members[room] {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// roomStoreTimeout bounds RoomStore calls, since manager calls carry no context
+const roomStoreTimeout = 2 * time.Second
+
+func (wm *WebSocketManager) storeCall(room string, fn func(ctx context.Context, store RoomStore) error) {
+	store := wm.settings().Rooms
+	if store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), roomStoreTimeout)
+	defer cancel()
+	if err := fn(ctx, store); err != nil {
+		log.Printf("⚠️ Failed to update membership of room %s: %v", room, err)
+	}
+}
+
+// JoinRoom adds a locally connected user to room
+func (wm *WebSocketManager) JoinRoom(room, userID, userType string) error {
+	if !wm.IsConnected(userID, userType) {
+		return fmt.Errorf("%w: %s:%s", ErrNotConnected, userType, userID)
+	}
+	member := RoomMember{UserID: userID, UserType: userType}
+	wm.rooms.join(room, member.connectionID())
+	wm.storeCall(room, func(ctx context.Context, store RoomStore) error {
+		return store.Add(ctx, room, member)
+	})
+	return nil
+}
+
+// LeaveRoom removes a user from room
+func (wm *WebSocketManager) LeaveRoom(room, userID, userType string) {
+	member := RoomMember{UserID: userID, UserType: userType}
+	wm.rooms.leave(room, member.connectionID())
+	wm.storeCall(room, func(ctx context.Context, store RoomStore) error {
+		return store.Remove(ctx, room, member)
+	})
+}
+
+// leaveAllRooms removes a closed connection from its rooms
+func (wm *WebSocketManager) leaveAllRooms(connectionID string) {
+	member := memberFromConnectionID(connectionID)
+	for _, room := range wm.rooms.leaveAll(connectionID) {
+		wm.storeCall(room, func(ctx context.Context, store RoomStore) error {
+			return store.Remove(ctx, room, member)
+		})
+	}
+}
+
+// BroadcastToRoom sends a message to every local member of room
+func (wm *WebSocketManager) BroadcastToRoom(room string, message WebSocketMessage) {
+	ids := wm.rooms.connectionIDs(room)
+	if len(ids) == 0 {
+		return
+	}
+	encoder := newFrameEncoder(message)
+	for _, id := range ids {
+		if value, ok := wm.connections.Load(id); ok {
+			conn := value.(*WebSocketConnection)
+			if frame, err := encoder.frameFor(conn.codec); err == nil {
+				conn.enqueue(frame)
+			}
+		}
+	}
+}
+
+// GetRoomMembers returns the members of room, across all instances when a
+// RoomStore is configured and of this instance otherwise
+func (wm *WebSocketManager) GetRoomMembers(room string) []RoomMember {
+	if store := wm.settings().Rooms; store != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), roomStoreTimeout)
+		defer cancel()
+		members, err := store.Members(ctx, room)
+		if err == nil {
+			return members
+		}
+		log.Printf("⚠️ Failed to read members of room %s, using local members: %v", room, err)
+	}
+
+	ids := wm.rooms.connectionIDs(room)
+	members := make([]RoomMember, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, memberFromConnectionID(id))
+	}
+	return members
+}
+
+// RedisRoomStore keeps room membership in Redis sets
+type RedisRoomStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	ttl       time.Duration
+}
+
+// NewRedisRoomStore creates a Redis room store. Sets expire ttl after the
+// last join so members of crashed instances do not linger forever.
+func NewRedisRoomStore(client redis.UniversalClient, keyPrefix string, ttl time.Duration) *RedisRoomStore {
+	if keyPrefix == "" {
+		keyPrefix = "ws:room"
+	}
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &RedisRoomStore{client: client, keyPrefix: keyPrefix, ttl: ttl}
+}
+
+func (s *RedisRoomStore) key(room string) string {
+	return fmt.Sprintf("%s:{%s}", s.keyPrefix, room)
+}
+
+// Add records member in room
+func (s *RedisRoomStore) Add(ctx context.Context, room string, member RoomMember) error {
+	key := s.key(room)
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, member.connectionID())
+		pipe.Expire(ctx, key, s.ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to room %s: %w", member.connectionID(), room, err)
+	}
+	return nil
+}
+
+// Remove drops member from room
+func (s *RedisRoomStore) Remove(ctx context.Context, room string, member RoomMember) error {
+	if err := s.client.SRem(ctx, s.key(room), member.connectionID()).Err(); err != nil {
+		return fmt.Errorf("failed to remove %s from room %s: %w", member.connectionID(), room, err)
+	}
+	return nil
+}
+
+// Members returns every member of room
+func (s *RedisRoomStore) Members(ctx context.Context, room string) ([]RoomMember, error) {
+	ids, err := s.client.SMembers(ctx, s.key(room)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read room %s: %w", room, err)
+	}
+	members := make([]RoomMember, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, memberFromConnectionID(id))
+	}
+	return members, nil
+}
@@ -0,0 +1,183 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/crashreport"
+)
+
+// Router errors
+var (
+	ErrInvalidPayload = errors.New("websocket: invalid message payload")
+	ErrUnknownType    = errors.New("websocket: unknown message type")
+	ErrForbidden      = errors.New("websocket: message type not allowed")
+)
+
+// RouteHandler handles a routed inbound message; a returned error is sent
+// back to the client as an error message
+type RouteHandler func(ctx context.Context, conn *WebSocketConnection, message WebSocketMessage) error
+
+// RouteMiddleware wraps a RouteHandler, e.g. for auth, rate limiting or tracing
+type RouteMiddleware func(next RouteHandler) RouteHandler
+
+// MessageRouter routes inbound messages to handlers by type. Attach it to a
+// manager with SetDefaultHandler(router.Handler()) so the read pump invokes it.
+type MessageRouter struct {
+	mu         sync.RWMutex
+	routes     map[string]RouteHandler
+	middleware []RouteMiddleware
+	unknown    RouteHandler
+	timeout    time.Duration
+}
+
+// NewMessageRouter creates a router; each dispatch gets a context bounded by
+// timeout (0 means no deadline)
+func NewMessageRouter(timeout time.Duration) *MessageRouter {
+	return &MessageRouter{
+		routes:  make(map[string]RouteHandler),
+		timeout: timeout,
+	}
+}
+
+// Use appends middleware, applied in order to every route
+func (r *MessageRouter) Use(middleware ...RouteMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// Handle registers handler for messageType
+func (r *MessageRouter) Handle(messageType string, handler RouteHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[messageType] = handler
+}
+
+// HandleUnknown sets the handler for unregistered types; by default the
+// client receives an ErrUnknownType error message
+func (r *MessageRouter) HandleUnknown(handler RouteHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unknown = handler
+}
+
+// HandleTyped registers a handler receiving the message data decoded into T
+func HandleTyped[T any](r *MessageRouter, messageType string, handler func(ctx context.Context, conn *WebSocketConnection, payload T) error) {
+	r.Handle(messageType, func(ctx context.Context, conn *WebSocketConnection, message WebSocketMessage) error {
+		payload, err := DecodePayload[T](message)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, conn, payload)
+	})
+}
+
+// DecodePayload decodes a message's data into T
+func DecodePayload[T any](message WebSocketMessage) (T, error) {
+	var payload T
+	data, err := json.Marshal(message.Data)
+	if err != nil {
+		return payload, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return payload, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return payload, nil
+}
+
+// route returns the handler for messageType wrapped in the middleware
+func (r *MessageRouter) route(messageType string) RouteHandler {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handler, ok := r.routes[messageType]
+	if !ok {
+		handler = r.unknown
+	}
+	if handler == nil {
+		handler = func(ctx context.Context, conn *WebSocketConnection, message WebSocketMessage) error {
+			return fmt.Errorf("%w: %s", ErrUnknownType, message.Type)
+		}
+	}
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	return handler
+}
+
+// Dispatch runs the handler for message, recovering panics and replying
+// with an error message when the handler fails
+func (r *MessageRouter) Dispatch(conn *WebSocketConnection, message WebSocketMessage) {
+	ctx := conn.Context()
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	if err := r.safeCall(ctx, conn, message); err != nil {
+		conn.Send(CreateWebSocketErrorMessage(MessageTypeError, err.Error(), payloadData(ErrorPayload{
+			Type: message.Type,
+			ID:   message.ID,
+		})))
+	}
+}
+
+// safeCall runs the route, converting a panic into an error
+func (r *MessageRouter) safeCall(ctx context.Context, conn *WebSocketConnection, message WebSocketMessage) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			report := crashreport.NewReport(crashreport.SourceWebSocket, message.Type, recovered)
+			report.Tags = map[string]string{"user_type": conn.UserType}
+			crashreport.Capture(ctx, report)
+			err = errors.New("internal error")
+		}
+	}()
+	return r.route(message.Type)(ctx, conn, message)
+}
+
+// Handler adapts the router to a MessageHandler for SetDefaultHandler
+func (r *MessageRouter) Handler() MessageHandler {
+	return r.Dispatch
+}
+
+// RequireUserTypes rejects messageTypes unless sent by one of userTypes,
+// e.g. only drivers may send location updates
+func RequireUserTypes(messageTypes []string, userTypes ...string) RouteMiddleware {
+	guarded := make(map[string]bool, len(messageTypes))
+	for _, t := range messageTypes {
+		guarded[t] = true
+	}
+	allowed := make(map[string]bool, len(userTypes))
+	for _, t := range userTypes {
+		allowed[t] = true
+	}
+	return func(next RouteHandler) RouteHandler {
+		return func(ctx context.Context, conn *WebSocketConnection, message WebSocketMessage) error {
+			if guarded[message.Type] && !allowed[conn.UserType] {
+				return fmt.Errorf("%w: %s", ErrForbidden, message.Type)
+			}
+			return next(ctx, conn, message)
+		}
+	}
+}
+
+// LogSlowHandlers logs handlers taking longer than threshold
+func LogSlowHandlers(threshold time.Duration) RouteMiddleware {
+	return func(next RouteHandler) RouteHandler {
+		return func(ctx context.Context, conn *WebSocketConnection, message WebSocketMessage) error {
+			start := time.Now()
+			err := next(ctx, conn, message)
+			if elapsed := time.Since(start); elapsed > threshold {
+				log.Printf("⚠️ Slow WebSocket handler for %s from %s:%s took %s", message.Type, conn.UserType, conn.UserID, elapsed)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package websocket
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mihirk-khode/motocabz-common/util/timefmt"
+)
+
+// JSONSchema is a JSON Schema document
+type JSONSchema map[string]interface{}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	timefmtTimeType = reflect.TypeOf(timefmt.Time{})
+	unixTimeType    = reflect.TypeOf(timefmt.UnixTime{})
+)
+
+// PayloadSchema returns the JSON Schema of a message type's data
+func PayloadSchema(messageType string) (JSONSchema, bool) {
+	for _, p := range payloadTypes {
+		if p.MessageType() == messageType {
+			return payloadSchema(p), true
+		}
+	}
+	return nil, false
+}
+
+// PayloadSchemas returns the JSON Schema of every message type's data, keyed
+// by message type, e.g. for publishing client contracts
+func PayloadSchemas() map[string]JSONSchema {
+	schemas := make(map[string]JSONSchema, len(payloadTypes))
+	for _, p := range payloadTypes {
+		schemas[p.MessageType()] = payloadSchema(p)
+	}
+	return schemas
+}
+
+func payloadSchema(p Payload) JSONSchema {
+	schema := schemaOf(reflect.TypeOf(p))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = p.MessageType()
+	return schema
+}
+
+// schemaOf derives a schema from t's JSON encoding; fields without
+// omitempty are required, and values encoding nil or zero as null are nullable
+func schemaOf(t reflect.Type) JSONSchema {
+	if t.Kind() == reflect.Ptr {
+		return nullable(schemaOf(t.Elem()))
+	}
+	switch t {
+	case timeType:
+		return JSONSchema{"type": "string", "format": "date-time"}
+	case timefmtTimeType:
+		return nullable(JSONSchema{"type": "string", "format": "date-time"})
+	case unixTimeType:
+		return nullable(JSONSchema{"type": "integer"})
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return JSONSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return JSONSchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return JSONSchema{"type": "number"}
+	case reflect.String:
+		return JSONSchema{"type": "string"}
+	case reflect.Slice:
+		return nullable(JSONSchema{"type": "array", "items": schemaOf(t.Elem())})
+	case reflect.Array:
+		return JSONSchema{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return nullable(JSONSchema{"type": "object", "additionalProperties": schemaOf(t.Elem())})
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return JSONSchema{}
+	}
+}
+
+// nullable additionally allows null
+func nullable(schema JSONSchema) JSONSchema {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+	}
+	return schema
+}
+
+func structSchema(t reflect.Type) JSONSchema {
+	properties := make(map[string]interface{})
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaOf(field.Type)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := JSONSchema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,87 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// MessageTypeReconnect tells clients to reconnect, usually to another instance
+const MessageTypeReconnect = "reconnect"
+
+// shutdownReason is sent in close frames during Shutdown
+const shutdownReason = "server shutting down"
+
+// CreateReconnectMessage creates a reconnect message; clients should wait
+// retryAfter before reconnecting
+func CreateReconnectMessage(reason string, retryAfter time.Duration) WebSocketMessage {
+	return NewPayloadMessage(ReconnectPayload{
+		Reason:       reason,
+		RetryAfterMs: retryAfter.Milliseconds(),
+	})
+}
+
+// IsDraining reports whether Shutdown has been called
+func (wm *WebSocketManager) IsDraining() bool {
+	return wm.draining.Load()
+}
+
+// rejectDraining refuses a new connection during shutdown with a close frame
+func rejectDraining(conn *websocket.Conn) {
+	deadline := time.Now().Add(time.Second)
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, shutdownReason), deadline)
+	conn.Close()
+}
+
+// Shutdown drains the manager for a deploy: new connections are refused,
+// every client is told to reconnect and sent a close frame, and Shutdown
+// waits for write queues to flush until ctx expires. Connections still open
+// at the deadline are closed forcibly and ctx's error is returned.
+func (wm *WebSocketManager) Shutdown(ctx context.Context) error {
+	if !wm.draining.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	var connections []*WebSocketConnection
+	wm.connections.Range(func(key, value interface{}) bool {
+		connections = append(connections, value.(*WebSocketConnection))
+		return true
+	})
+	log.Printf("Draining %d WebSocket connections", len(connections))
+
+	reconnect := CreateReconnectMessage(shutdownReason, time.Second)
+	closeFrame := outboundFrame{
+		messageType: websocket.CloseMessage,
+		kind:        "close",
+		data:        websocket.FormatCloseMessage(websocket.CloseServiceRestart, shutdownReason),
+	}
+	for _, conn := range connections {
+		conn.Send(reconnect)
+		conn.enqueue(closeFrame)
+	}
+
+	var err error
+	for _, conn := range connections {
+		select {
+		case <-conn.flushed:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	for _, conn := range connections {
+		conn.disconnect()
+		wm.removeIfCurrent(conn.UserType+":"+conn.UserID, conn)
+	}
+	if err != nil {
+		log.Printf("⚠️ WebSocket drain timed out, closed remaining connections: %v", err)
+		return err
+	}
+	log.Printf("✅ WebSocket connections drained")
+	return nil
+}
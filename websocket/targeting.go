@@ -0,0 +1,51 @@
+package websocket
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// ConnectionTags describe a client for targeted broadcasts; they are set
+// when the connection registers, typically from the handshake or token
+type ConnectionTags struct {
+	City         string `json:"city,omitempty"`
+	VehicleClass string `json:"vehicleClass,omitempty"`
+	AppVersion   string `json:"appVersion,omitempty"`
+}
+
+// Matches reports whether t satisfies filter; empty filter fields match any
+// value and comparisons ignore case
+func (t ConnectionTags) Matches(filter ConnectionTags) bool {
+	return tagMatches(t.City, filter.City) &&
+		tagMatches(t.VehicleClass, filter.VehicleClass) &&
+		tagMatches(t.AppVersion, filter.AppVersion)
+}
+
+func tagMatches(value, filter string) bool {
+	return filter == "" || strings.EqualFold(value, filter)
+}
+
+// BroadcastWhere sends a message to every open connection for which
+// predicate returns true. The message is encoded once per codec, and the
+// predicate runs while iterating so it must not block.
+func (wm *WebSocketManager) BroadcastWhere(predicate func(*WebSocketConnection) bool, message WebSocketMessage) {
+	encoder := newFrameEncoder(message)
+	wm.connections.Range(func(key, value interface{}) bool {
+		conn := value.(*WebSocketConnection)
+		if atomic.LoadInt32(&conn.Closed) == 0 && predicate(conn) {
+			if frame, err := encoder.frameFor(conn.codec); err == nil {
+				conn.enqueue(frame)
+			}
+		}
+		return true
+	})
+}
+
+// BroadcastToTagged sends a message to connections of userType whose tags
+// match, e.g. drivers in one city with one vehicle class; an empty userType
+// matches every user type
+func (wm *WebSocketManager) BroadcastToTagged(userType string, tags ConnectionTags, message WebSocketMessage) {
+	wm.BroadcastWhere(func(conn *WebSocketConnection) bool {
+		return (userType == "" || conn.UserType == userType) && conn.Tags.Matches(tags)
+	}, message)
+}
@@ -1,7 +1,7 @@
 package websocket
 
 import (
-	"encoding/json"
+	"context"
 	"log"
 	"net/http"
 	"sync"
@@ -9,10 +9,15 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+	"github.com/mihirk-khode/motocabz-common/util/timefmt"
 )
 
 // WebSocketMessage represents a WebSocket message structure
 type WebSocketMessage struct {
+	// ID is set on messages the client must acknowledge
+	ID        string                 `json:"id,omitempty"`
 	Type      string                 `json:"type"`
 	Data      map[string]interface{} `json:"data"`
 	Timestamp string                 `json:"timestamp"`
@@ -24,8 +29,23 @@ type WebSocketConnection struct {
 	Conn     *websocket.Conn
 	UserID   string
 	UserType string
+	// Tags are set at registration and must not be modified afterwards
+	Tags ConnectionTags
+	// LastPing is when the connection was added; see LastSeen for liveness
 	LastPing time.Time
 	Closed   int32 // Atomic flag for connection state
+
+	lastSeen atomic.Int64
+
+	config  ManagerConfig
+	codec   MessageCodec
+	limiter *inboundLimiter
+	send    chan outboundFrame
+	flushed chan struct{}
+	// ctx is cancelled when the connection stops, ending its goroutines
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopOnce sync.Once
 }
 
 // IWebSocketManager defines the interface for WebSocket connection management
@@ -42,41 +62,139 @@ type IWebSocketManager interface {
 	IsConnected(userID, userType string) bool
 }
 
-// WebSocketManager manages WebSocket connections
+// IRoomManager groups connections into rooms, e.g. everyone following a trip
+type IRoomManager interface {
+	JoinRoom(room, userID, userType string) error
+	LeaveRoom(room, userID, userType string)
+	BroadcastToRoom(room string, message WebSocketMessage)
+	GetRoomMembers(room string) []RoomMember
+}
+
+// IConnectionServer runs the read and write pumps of accepted connections
+// and dispatches inbound messages to registered handlers
+type IConnectionServer interface {
+	Serve(userID, userType string, conn *websocket.Conn)
+	ServeWithTags(userID, userType string, tags ConnectionTags, conn *websocket.Conn)
+	RegisterHandler(messageType string, handler MessageHandler)
+}
+
+// ITargetedBroadcaster sends to connections selected by their tags
+type ITargetedBroadcaster interface {
+	BroadcastWhere(predicate func(*WebSocketConnection) bool, message WebSocketMessage)
+	BroadcastToTagged(userType string, tags ConnectionTags, message WebSocketMessage)
+}
+
+// IGracefulShutdown drains connections before the process exits
+type IGracefulShutdown interface {
+	Shutdown(ctx context.Context) error
+}
+
+var (
+	_ IWebSocketManager    = (*WebSocketManager)(nil)
+	_ IRoomManager         = (*WebSocketManager)(nil)
+	_ IConnectionServer    = (*WebSocketManager)(nil)
+	_ ITargetedBroadcaster = (*WebSocketManager)(nil)
+	_ IGracefulShutdown    = (*WebSocketManager)(nil)
+	_ IWebSocketManager    = (*DistributedWebSocketManager)(nil)
+	_ IRoomManager         = (*DistributedWebSocketManager)(nil)
+	_ ITargetedBroadcaster = (*DistributedWebSocketManager)(nil)
+)
+
+// WebSocketManager manages WebSocket connections. Each connection has its
+// own write pump, so senders never write to a socket directly and a slow
+// client cannot block broadcasts.
 type WebSocketManager struct {
 	connections     sync.Map
 	connectionCount int64 // Atomic counter
+	config          ManagerConfig
+	configOnce      sync.Once
+	rooms           rooms
+	handlers        sync.Map
+	draining        atomic.Bool
+	typeCounts      sync.Map // userType -> *atomic.Int64
+	hooksMu         sync.RWMutex
+	hooks           []ConnectionHook
+}
+
+// ConnectionHook observes connections being added and removed
+type ConnectionHook interface {
+	Connected(conn *WebSocketConnection)
+	Disconnected(conn *WebSocketConnection)
+}
+
+// AddHook registers a hook called synchronously on every connect and disconnect
+func (wm *WebSocketManager) AddHook(hook ConnectionHook) {
+	wm.hooksMu.Lock()
+	defer wm.hooksMu.Unlock()
+	wm.hooks = append(wm.hooks, hook)
+}
+
+func (wm *WebSocketManager) notifyHooks(fn func(h ConnectionHook)) {
+	wm.hooksMu.RLock()
+	hooks := wm.hooks
+	wm.hooksMu.RUnlock()
+	for _, h := range hooks {
+		fn(h)
+	}
 }
 
 // NewWebSocketManager creates a new WebSocket manager
 func NewWebSocketManager() IWebSocketManager {
-	return &WebSocketManager{}
+	return NewWebSocketManagerWithConfig(DefaultManagerConfig())
+}
+
+// NewWebSocketManagerWithConfig creates a WebSocket manager with custom write pump settings
+func NewWebSocketManagerWithConfig(config ManagerConfig) *WebSocketManager {
+	return &WebSocketManager{config: config.withDefaults()}
+}
+
+// settings returns the config, defaulting it for zero-value managers
+func (wm *WebSocketManager) settings() ManagerConfig {
+	wm.configOnce.Do(func() {
+		wm.config = wm.config.withDefaults()
+	})
+	return wm.config
 }
 
-// AddConnection adds a new WebSocket connection
+// AddConnection adds a new WebSocket connection, replacing any previous
+// connection of the same user. During Shutdown the connection is refused
+// with a close frame.
 func (wm *WebSocketManager) AddConnection(userID, userType string, conn *websocket.Conn) {
+	wm.addConnection(userID, userType, ConnectionTags{}, conn)
+}
+
+// AddConnectionWithTags adds a WebSocket connection carrying targeting tags
+func (wm *WebSocketManager) AddConnectionWithTags(userID, userType string, tags ConnectionTags, conn *websocket.Conn) {
+	wm.addConnection(userID, userType, tags, conn)
+}
+
+func (wm *WebSocketManager) addConnection(userID, userType string, tags ConnectionTags, conn *websocket.Conn) *WebSocketConnection {
+	if wm.IsDraining() {
+		rejectDraining(conn)
+		return nil
+	}
 	connectionID := userType + ":" + userID
-	connection := &WebSocketConnection{
-		Conn:     conn,
-		UserID:   userID,
-		UserType: userType,
-		LastPing: time.Now(),
-		Closed:   0, // Atomic flag, 0 = open
+	connection := newConnection(userID, userType, conn, wm.settings())
+	connection.Tags = tags
+
+	if previous, replaced := wm.connections.Swap(connectionID, connection); replaced {
+		previous.(*WebSocketConnection).disconnect()
+		connection.config.Metrics.IncCounter("websocket_reconnects_total", 1, metrics.Labels{"user_type": userType})
+	} else {
+		atomic.AddInt64(&wm.connectionCount, 1)
+		wm.trackConnection(userType, 1)
 	}
-
-	wm.connections.Store(connectionID, connection)
-	atomic.AddInt64(&wm.connectionCount, 1)
 	log.Printf("WebSocket connection added: %s", connectionID)
+	wm.notifyHooks(func(h ConnectionHook) { h.Connected(connection) })
+	return connection
 }
 
-// RemoveConnection removes a WebSocket connection
+// RemoveConnection removes a WebSocket connection and cancels its context,
+// closing the socket and stopping its pumps and handlers
 func (wm *WebSocketManager) RemoveConnection(userID, userType string) {
 	connectionID := userType + ":" + userID
-	if connInterface, exists := wm.connections.LoadAndDelete(connectionID); exists {
-		conn := connInterface.(*WebSocketConnection)
-		atomic.StoreInt32(&conn.Closed, 1)
-		atomic.AddInt64(&wm.connectionCount, -1)
-		log.Printf("WebSocket connection removed: %s", connectionID)
+	if connInterface, exists := wm.connections.Load(connectionID); exists {
+		wm.removeIfCurrent(connectionID, connInterface.(*WebSocketConnection))
 	}
 }
 
@@ -95,44 +213,17 @@ func (wm *WebSocketManager) SendMessage(userID, userType string, message WebSock
 		return nil
 	}
 
-	messageBytes, err := json.Marshal(message)
-	if err != nil {
-		log.Printf("Failed to marshal WebSocket message: %v", err)
-		return err
-	}
-
-	// Double-check if connection is still open
-	if atomic.LoadInt32(&conn.Closed) == 1 {
-		return nil
-	}
-
-	conn.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-	if err := conn.Conn.WriteMessage(websocket.TextMessage, messageBytes); err != nil {
-		log.Printf("Failed to send WebSocket message to %s: %v", connectionID, err)
-		atomic.StoreInt32(&conn.Closed, 1)
-		return err
-	}
-
-	return nil
+	return conn.Send(message)
 }
 
 // BroadcastToType sends a message to all connections of a specific type
 func (wm *WebSocketManager) BroadcastToType(userType string, message WebSocketMessage) {
-	messageBytes, err := json.Marshal(message)
-	if err != nil {
-		log.Printf("Failed to marshal broadcast message: %v", err)
-		return
-	}
-
+	encoder := newFrameEncoder(message)
 	wm.connections.Range(func(key, value interface{}) bool {
-		connectionID := key.(string)
 		conn := value.(*WebSocketConnection)
-
-		if conn.UserType == userType && atomic.LoadInt32(&conn.Closed) == 0 {
-			conn.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := conn.Conn.WriteMessage(websocket.TextMessage, messageBytes); err != nil {
-				log.Printf("Failed to broadcast to %s: %v", connectionID, err)
-				atomic.StoreInt32(&conn.Closed, 1)
+		if conn.UserType == userType {
+			if frame, err := encoder.frameFor(conn.codec); err == nil {
+				conn.enqueue(frame)
 			}
 		}
 		return true // Continue iteration
@@ -144,21 +235,21 @@ func (wm *WebSocketManager) BroadcastToUser(userType, userID string, message Web
 	wm.SendMessage(userID, userType, message)
 }
 
-// StartPingPong starts ping-pong mechanism for connection health
+// StartPingPong starts ping-pong mechanism for connection health. Pings go
+// through the connection's write pump and the loop ends when it stops.
 func (wm *WebSocketManager) StartPingPong(conn *WebSocketConnection) {
-	ticker := time.NewTicker(30 * time.Second)
+	timeouts := common.GetTimeouts()
+	ticker := time.NewTicker(timeouts.WebSocketPingInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		if atomic.LoadInt32(&conn.Closed) == 1 {
-			return
-		}
-
-		conn.Conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-		if err := conn.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-			log.Printf("Ping failed for %s:%s: %v", conn.UserType, conn.UserID, err)
-			atomic.StoreInt32(&conn.Closed, 1)
+	for {
+		select {
+		case <-conn.ctx.Done():
 			return
+		case <-ticker.C:
+			if err := conn.enqueue(outboundFrame{messageType: websocket.PingMessage, kind: "ping"}); err != nil {
+				log.Printf("Ping failed for %s:%s: %v", conn.UserType, conn.UserID, err)
+			}
 		}
 	}
 }
@@ -200,12 +291,12 @@ func (wm *WebSocketManager) IsConnected(userID, userType string) bool {
 	return atomic.LoadInt32(&conn.Closed) == 0
 }
 
-// WebSocket configuration constants
+// WebSocket configuration defaults; runtime values come from common.GetTimeouts
 const (
-	WebSocketPingInterval   = 30 * time.Second
-	WebSocketWriteTimeout   = 10 * time.Second
-	WebSocketReadTimeout    = 10 * time.Second
-	WebSocketPongTimeout    = 60 * time.Second
+	WebSocketPingInterval   = common.DefaultWebSocketPingInterval
+	WebSocketWriteTimeout   = common.DefaultWebSocketWriteTimeout
+	WebSocketReadTimeout    = common.DefaultWebSocketReadTimeout
+	WebSocketPongTimeout    = common.DefaultWebSocketPongTimeout
 	WebSocketMaxMessageSize = 1024
 )
 
@@ -221,7 +312,7 @@ func CreateWebSocketMessage(messageType string, data map[string]interface{}) Web
 	return WebSocketMessage{
 		Type:      messageType,
 		Data:      data,
-		Timestamp: time.Now().Format(time.RFC3339),
+		Timestamp: timefmt.Now(),
 	}
 }
 
@@ -234,39 +325,33 @@ func CreateWebSocketErrorMessage(messageType string, errorMsg string, data map[s
 	return WebSocketMessage{
 		Type:      messageType,
 		Data:      data,
-		Timestamp: time.Now().Format(time.RFC3339),
+		Timestamp: timefmt.Now(),
 		Error:     errorMsg,
 	}
 }
 
 // CreateConnectionEstablishedMessage creates a connection established message
 func CreateConnectionEstablishedMessage(userID, userType string, channel string) WebSocketMessage {
-	return CreateWebSocketMessage("connection_established", map[string]interface{}{
-		"userId":   userID,
-		"userType": userType,
-		"channel":  channel,
+	return NewPayloadMessage(ConnectionEstablishedPayload{
+		UserID:   userID,
+		UserType: userType,
+		Channel:  channel,
 	})
 }
 
 // CreatePingMessage creates a ping message
 func CreatePingMessage() WebSocketMessage {
-	return CreateWebSocketMessage("ping", map[string]interface{}{
-		"timestamp": time.Now().Unix(),
-	})
+	return NewPayloadMessage(PingPayload{Timestamp: time.Now().Unix()})
 }
 
 // CreatePongMessage creates a pong message
 func CreatePongMessage() WebSocketMessage {
-	return CreateWebSocketMessage("pong", map[string]interface{}{
-		"timestamp": time.Now().Unix(),
-	})
+	return NewPayloadMessage(PongPayload{Timestamp: time.Now().Unix()})
 }
 
 // CreateSystemMessage creates a system message
 func CreateSystemMessage(message string) WebSocketMessage {
-	return CreateWebSocketMessage("system_message", map[string]interface{}{
-		"message": message,
-	})
+	return NewPayloadMessage(SystemMessagePayload{Message: message})
 }
 
 // WebSocket message type constants
@@ -285,6 +370,9 @@ const (
 	MessageTypeTripStatusUpdate      = "trip_status_update"
 	MessageTypeDriverLocation        = "driver_location_update"
 	MessageTypeNoDriverFound         = "no_driver_found"
+	MessageTypeChatMessage           = "chat_message"
+	MessageTypeChatTyping            = "chat_typing"
+	MessageTypeEmergencyAlert        = "emergency_alert"
 )
 
 // WebSocket user type constants
@@ -314,7 +402,7 @@ func GetWebSocketStats(manager IWebSocketManager) WebSocketStats {
 		DriverConnections: len(driverConns),
 		RiderConnections:  len(riderConns),
 		AdminConnections:  len(adminConns),
-		Timestamp:         time.Now().Format(time.RFC3339),
+		Timestamp:         timefmt.Now(),
 	}
 }
 
@@ -343,7 +431,7 @@ func GetConnectionHealth(manager IWebSocketManager, userID, userType string) Con
 	return ConnectionHealth{
 		UserID:     userID,
 		UserType:   userType,
-		LastPing:   conn.LastPing,
+		LastPing:   conn.LastSeen(),
 		IsHealthy:  atomic.LoadInt32(&conn.Closed) == 0,
 		Connection: "connected",
 	}
@@ -0,0 +1,197 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	common "github.com/mihirk-khode/motocabz-common"
+	"github.com/mihirk-khode/motocabz-common/observability/metrics"
+)
+
+// Overflow policies applied when a connection's send queue is full
+const (
+	// OverflowDropNewest discards the message being sent
+	OverflowDropNewest = "drop_newest"
+	// OverflowDropOldest discards the oldest queued message to make room
+	OverflowDropOldest = "drop_oldest"
+	// OverflowDisconnect closes the connection; the client reconnects and
+	// resyncs instead of silently missing messages
+	OverflowDisconnect = "disconnect"
+)
+
+// ErrSendQueueFull is returned when a message could not be queued
+var ErrSendQueueFull = errors.New("websocket: send queue full")
+
+// ManagerConfig configures per-connection write pumps and room membership
+type ManagerConfig struct {
+	// QueueSize bounds each connection's outbound queue
+	QueueSize int
+	// Overflow is the policy applied when the queue is full
+	Overflow string
+	// Metrics receives queue depth and drop metrics; nil uses metrics.Default
+	Metrics metrics.Provider
+	// MaxMessageSize bounds inbound frames read by Serve
+	MaxMessageSize int64
+	// Inbound limits messages read by Serve; the zero value disables limiting
+	Inbound InboundLimitConfig
+	// Codecs are the message codecs clients may negotiate through the
+	// WebSocket subprotocol (see NewUpgrader); JSON is always available
+	Codecs []MessageCodec
+	// Compression enables permessage-deflate writes on connections that
+	// negotiated it; CompressionLevel 0 keeps the library default
+	Compression      bool
+	CompressionLevel int
+	// Rooms optionally mirrors room membership, e.g. a RedisRoomStore
+	Rooms RoomStore
+}
+
+// DefaultManagerConfig returns the default write pump settings
+func DefaultManagerConfig() ManagerConfig {
+	return ManagerConfig{
+		QueueSize:      256,
+		Overflow:       OverflowDisconnect,
+		MaxMessageSize: WebSocketMaxMessageSize,
+		Inbound:        DefaultInboundLimitConfig(),
+	}
+}
+
+func (c ManagerConfig) withDefaults() ManagerConfig {
+	defaults := DefaultManagerConfig()
+	if c.QueueSize <= 0 {
+		c.QueueSize = defaults.QueueSize
+	}
+	if c.MaxMessageSize <= 0 {
+		c.MaxMessageSize = defaults.MaxMessageSize
+	}
+	switch c.Overflow {
+	case OverflowDropNewest, OverflowDropOldest, OverflowDisconnect:
+	default:
+		c.Overflow = defaults.Overflow
+	}
+	c.Metrics = metrics.OrDefault(c.Metrics)
+	return c
+}
+
+// outboundFrame is a queued WebSocket frame
+type outboundFrame struct {
+	messageType int
+	data        []byte
+	// kind is the WebSocketMessage type, for metrics
+	kind     string
+	queuedAt time.Time
+}
+
+// QueueDepth returns the number of messages waiting to be written
+func (c *WebSocketConnection) QueueDepth() int {
+	return len(c.send)
+}
+
+// stop cancels the connection's context, which ends the write pump and ping
+// loop and closes the socket so the read loop exits; it is safe to call more
+// than once
+func (c *WebSocketConnection) stop() {
+	c.stopOnce.Do(func() {
+		atomic.StoreInt32(&c.Closed, 1)
+		c.cancel()
+	})
+}
+
+// disconnect stops the write pump and closes the socket so the handler's
+// read loop fails and removes the connection
+func (c *WebSocketConnection) disconnect() {
+	c.stop()
+	c.Conn.Close()
+}
+
+// enqueue queues a frame without blocking, applying the overflow policy
+// when the queue is full
+func (c *WebSocketConnection) enqueue(frame outboundFrame) error {
+	config := c.config
+	if atomic.LoadInt32(&c.Closed) == 1 {
+		return nil
+	}
+	frame.queuedAt = time.Now()
+	labels := metrics.Labels{"user_type": c.UserType}
+	defer func() {
+		config.Metrics.ObserveHistogram("websocket_send_queue_depth", float64(len(c.send)), labels)
+	}()
+
+	select {
+	case c.send <- frame:
+		return nil
+	default:
+	}
+
+	switch config.Overflow {
+	case OverflowDropOldest:
+		select {
+		case oldest := <-c.send:
+			c.recordDropped(oldest.kind, config.Overflow)
+		default:
+		}
+		select {
+		case c.send <- frame:
+			return nil
+		default:
+		}
+	case OverflowDisconnect:
+		log.Printf("⚠️ Disconnecting slow WebSocket client %s:%s", c.UserType, c.UserID)
+		c.disconnect()
+	}
+	c.recordDropped(frame.kind, config.Overflow)
+	return ErrSendQueueFull
+}
+
+// writePump is the only goroutine writing data frames to the connection.
+// It ends after writing a close frame.
+func (c *WebSocketConnection) writePump() {
+	defer close(c.flushed)
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case frame := <-c.send:
+			c.Conn.SetWriteDeadline(time.Now().Add(common.GetTimeouts().WebSocketWriteTimeout))
+			if err := c.Conn.WriteMessage(frame.messageType, frame.data); err != nil {
+				log.Printf("Failed to send WebSocket message to %s:%s: %v", c.UserType, c.UserID, err)
+				c.recordDropped(frame.kind, "write_error")
+				c.disconnect()
+				return
+			}
+			c.recordSent(frame)
+			if frame.messageType == websocket.CloseMessage {
+				c.stop()
+				return
+			}
+		}
+	}
+}
+
+// newConnection creates a connection and starts its write pump
+func newConnection(userID, userType string, conn *websocket.Conn, config ManagerConfig) *WebSocketConnection {
+	ctx, cancel := context.WithCancel(context.Background())
+	connection := &WebSocketConnection{
+		Conn:     conn,
+		UserID:   userID,
+		UserType: userType,
+		LastPing: time.Now(),
+		config:   config,
+		limiter:  newInboundLimiter(config.Inbound),
+		codec:    config.codecFor(conn.Subprotocol()),
+		send:     make(chan outboundFrame, config.QueueSize),
+		flushed:  make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if config.Compression {
+		enableCompression(conn, config.CompressionLevel)
+	}
+	connection.touch()
+	context.AfterFunc(ctx, func() { conn.Close() })
+	go connection.writePump()
+	return connection
+}